@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,9 +10,14 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App `yaml:"app"`
-		Log `yaml:"logger"`
-		PG  `yaml:"postgres"`
+		App       `yaml:"app"`
+		Log       `yaml:"logger"`
+		PG        `yaml:"postgres"`
+		FX        `yaml:"rates"`
+		Hold      `yaml:"holds"`
+		Schedule  `yaml:"scheduled_transfers"`
+		Recurring `yaml:"recurring_payments"`
+		Escrow    `yaml:"escrows"`
 	}
 
 	// App -.
@@ -30,6 +36,43 @@ type (
 		PoolMax int    `env-required:"true" yaml:"pool_max" env:"PG_POOL_MAX"`
 		URL     string `env-required:"true"                 env:"PG_URL"`
 	}
+
+	// FX -.
+	FX struct {
+		Provider string        `env-required:"true" yaml:"provider"  env:"RATES_PROVIDER"`
+		File     string        `                    yaml:"file"      env:"RATES_FILE"`
+		URL      string        `                    yaml:"url"       env:"RATES_URL"`
+		Timeout  time.Duration `env-required:"true" yaml:"timeout"   env:"RATES_TIMEOUT"`
+		CacheTTL time.Duration `env-required:"true" yaml:"cache_ttl" env:"RATES_CACHE_TTL"`
+	}
+
+	// Hold -.
+	Hold struct {
+		ExpireInterval time.Duration `env-required:"true" yaml:"expire_interval" env:"HOLDS_EXPIRE_INTERVAL"`
+		ExpireBatch    uint64        `env-required:"true" yaml:"expire_batch"    env:"HOLDS_EXPIRE_BATCH"`
+	}
+
+	// Schedule -.
+	Schedule struct {
+		ExecuteInterval time.Duration `env-required:"true" yaml:"execute_interval" env:"SCHEDULED_TRANSFERS_EXECUTE_INTERVAL"`
+		ExecuteBatch    uint64        `env-required:"true" yaml:"execute_batch"    env:"SCHEDULED_TRANSFERS_EXECUTE_BATCH"`
+		MaxAttempts     int           `env-required:"true" yaml:"max_attempts"     env:"SCHEDULED_TRANSFERS_MAX_ATTEMPTS"`
+		RetryInterval   time.Duration `env-required:"true" yaml:"retry_interval"   env:"SCHEDULED_TRANSFERS_RETRY_INTERVAL"`
+	}
+
+	// Recurring -.
+	Recurring struct {
+		ChargeInterval  time.Duration `env-required:"true" yaml:"charge_interval"  env:"RECURRING_PAYMENTS_CHARGE_INTERVAL"`
+		ChargeBatch     uint64        `env-required:"true" yaml:"charge_batch"     env:"RECURRING_PAYMENTS_CHARGE_BATCH"`
+		DunningRetries  int           `env-required:"true" yaml:"dunning_retries"  env:"RECURRING_PAYMENTS_DUNNING_RETRIES"`
+		DunningInterval time.Duration `env-required:"true" yaml:"dunning_interval" env:"RECURRING_PAYMENTS_DUNNING_INTERVAL"`
+	}
+
+	// Escrow -.
+	Escrow struct {
+		RefundInterval time.Duration `env-required:"true" yaml:"refund_interval" env:"ESCROWS_REFUND_INTERVAL"`
+		RefundBatch    uint64        `env-required:"true" yaml:"refund_batch"    env:"ESCROWS_REFUND_BATCH"`
+	}
 )
 
 // NewConfig returns app config.
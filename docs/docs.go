@@ -30,6 +30,26 @@ const docTemplate = `{
                 ],
                 "summary": "Create new account",
                 "operationId": "create",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code, RUB by default",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "External owner ID",
+                        "name": "ownerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of owner: main (by default), bonus or escrow",
+                        "name": "wallet",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -48,7 +68,7 @@ const docTemplate = `{
         },
         "/account/amount/{redeemId}/transfer/{accrId}": {
             "put": {
-                "description": "Transferring amount of money between accounts",
+                "description": "Transferring amount of money between accounts, accrual account of unknown owner is created",
                 "consumes": [
                     "application/json"
                 ],
@@ -62,25 +82,73 @@ const docTemplate = `{
                 "operationId": "transferAmount",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID for redeem funds",
+                        "type": "string",
+                        "description": "Account ID or external owner ID for redeem funds",
                         "name": "redeemId",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Account ID for accrual funds",
+                        "type": "string",
+                        "description": "Account ID or external owner ID for accrual funds",
                         "name": "accrId",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Type of IDs in path: owner for external owner ID",
+                        "name": "idType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of redeem owner, main by default",
+                        "name": "redeemWallet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of accrual owner, main by default",
+                        "name": "accrWallet",
+                        "in": "query"
+                    },
                     {
                         "type": "number",
                         "description": "Amount of money to transfer",
                         "name": "amount",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow transfer between accounts in different currencies",
+                        "name": "convert",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of operation",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code of operation, e.g. order_payment",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of service which makes the operation",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of request, retry with the same key returns the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -90,6 +158,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.transferAccountPair"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -115,12 +195,24 @@ const docTemplate = `{
                 "operationId": "history",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID",
+                        "type": "string",
+                        "description": "Account ID or external owner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Type of ID in path: owner for external owner ID",
+                        "name": "idType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of owner: main (by default), bonus or escrow",
+                        "name": "wallet",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "The value of limit in pagination",
@@ -146,6 +238,63 @@ const docTemplate = `{
                         "description": "Descending sort flag",
                         "name": "isDecreasing",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Substring of description",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of service which made the operation",
+                        "name": "source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/account/wallets/{ownerId}": {
+            "get": {
+                "description": "Return all wallets of owner, balances are read at the same moment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Wallets of owner",
+                "operationId": "wallets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "External owner ID",
+                        "name": "ownerId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -166,7 +315,7 @@ const docTemplate = `{
         },
         "/account/{id}": {
             "get": {
-                "description": "Returns account fields by ID in the response",
+                "description": "Returns account fields by ID in the response, the balance is converted when currency is passed",
                 "consumes": [
                     "application/json"
                 ],
@@ -180,11 +329,29 @@ const docTemplate = `{
                 "operationId": "getById",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID",
+                        "type": "string",
+                        "description": "Account ID or external owner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of ID in path: owner for external owner ID",
+                        "name": "idType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of owner: main (by default), bonus or escrow",
+                        "name": "wallet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code to convert balance",
+                        "name": "currency",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -203,7 +370,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Changing the account balance to the amount passed in the parameter",
+                "description": "Changing the account balance to the amount passed in the parameter, the first accrual to unknown owner creates the account",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,18 +384,66 @@ const docTemplate = `{
                 "operationId": "updBalance",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Account ID",
+                        "type": "string",
+                        "description": "Account ID or external owner ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Type of ID in path: owner for external owner ID",
+                        "name": "idType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet of owner: main (by default), bonus or escrow",
+                        "name": "wallet",
+                        "in": "query"
+                    },
                     {
                         "type": "number",
                         "description": "The value by which the balance changes",
                         "name": "amount",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code of the amount, must match the account",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "System account on the other side: cash_in (by default for accrual), cash_out (by default for redeem), fees, suspense or write_off",
+                        "name": "system",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of operation",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code of operation, e.g. order_payment",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of service which makes the operation",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of request, retry with the same key returns the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -238,6 +453,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/v1.correctResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -246,46 +473,3293 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.Account": {
-            "type": "object",
-            "properties": {
-                "balance": {
-                    "type": "number"
-                },
-                "created_dt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "v1.correctResponse": {
-            "type": "object",
-            "properties": {
-                "data": {}
-            }
-        },
-        "v1.response": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "message"
-                }
-            }
         },
-        "v1.transferAccountPair": {
-            "type": "object",
-            "properties": {
-                "accrualAccount": {
-                    "$ref": "#/definitions/entity.Account"
-                },
-                "redeemAccount": {
-                    "$ref": "#/definitions/entity.Account"
+        "/accounts": {
+            "get": {
+                "description": "Return page of accounts found by filters with total count, next page is requested with cursor of previous page",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Search accounts",
+                "operationId": "list",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Min balance",
+                        "name": "minBalance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Max balance",
+                        "name": "maxBalance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of creation date range in RFC3339",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of creation date range in RFC3339, not included",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "External owner ID",
+                        "name": "ownerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Column to sort: id (by default), balance or created_dt",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Descending sort flag",
+                        "name": "isDecreasing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of next page from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/close": {
+            "put": {
+                "description": "Close account, account with non-zero balance is closed only with sweep of the balance to another account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Close account",
+                "operationId": "close",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of status change",
+                        "name": "reason",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account ID to sweep the balance to",
+                        "name": "sweepTo",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/freeze": {
+            "put": {
+                "description": "Freeze account, frozen account accepts credits only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Freeze account",
+                "operationId": "freeze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of status change",
+                        "name": "reason",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/limit": {
+            "put": {
+                "description": "Set credit limit of account, account can go negative up to the limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set credit limit",
+                "operationId": "setCreditLimit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Credit limit in currency of account, 0 means no credit",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/status/history": {
+            "get": {
+                "description": "Return history of account's status changes with reasons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Account status history",
+                "operationId": "statusHistory",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/tier": {
+            "put": {
+                "description": "Set tier of account, fee rules of the tier apply to operations of account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set account tier",
+                "operationId": "setTier",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tier of account: standard, premium or business",
+                        "name": "tier",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/account/{id}/unfreeze": {
+            "put": {
+                "description": "Make frozen account active",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unfreeze account",
+                "operationId": "unfreeze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of status change",
+                        "name": "reason",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/campaigns/": {
+            "get": {
+                "description": "Return cashback campaigns with spent budget in order of ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List cashback campaigns",
+                "operationId": "listCampaigns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status of campaign: active, paused or exhausted",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Campaign pays percent of redeems with purpose as cashback from promo system account, capped per account in calendar month, until its budget is spent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create cashback campaign",
+                "operationId": "createCampaign",
+                "parameters": [
+                    {
+                        "description": "Campaign, start is now by default",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.campaignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Campaign"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/campaigns/{id}": {
+            "get": {
+                "description": "Return cashback campaign with spent budget",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get cashback campaign",
+                "operationId": "getCampaign",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Campaign"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/campaigns/{id}/budget": {
+            "put": {
+                "description": "Add amount to budget of campaign, exhausted campaign pays cashback again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Add budget of cashback campaign",
+                "operationId": "addCampaignBudget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount to add in currency of campaign",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Campaign"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/campaigns/{id}/pause": {
+            "put": {
+                "description": "Pause active or exhausted campaign, it does not pay cashback until it is resumed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Pause cashback campaign",
+                "operationId": "pauseCampaign",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Campaign"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/campaigns/{id}/resume": {
+            "put": {
+                "description": "Resume paused campaign, it is exhausted when its budget is spent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume cashback campaign",
+                "operationId": "resumeCampaign",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Campaign"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fees/": {
+            "get": {
+                "description": "Return active fee rules in order of operation, currency and tier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List fee rules",
+                "operationId": "listFeeRules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Fee of operation in currency for accounts of tier: flat, percent or tiered with min and max fee. Rule without tier applies to any tier, the rule replaces active rule of the same operation, currency and tier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create fee rule",
+                "operationId": "createFeeRule",
+                "parameters": [
+                    {
+                        "description": "Fee rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.feeRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeeRule"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fees/{id}/disable": {
+            "put": {
+                "description": "Disable active fee rule, operations are not charged by it anymore",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disable fee rule",
+                "operationId": "disableFeeRule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fee rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeeRule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ledger/accounts": {
+            "get": {
+                "description": "Return chart of system accounts in every currency with their balances, which are sums of their postings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "System accounts",
+                "operationId": "systemAccounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ledger/check": {
+            "get": {
+                "description": "Check that sum of all postings is zero in every currency and in every journal entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Check ledger",
+                "operationId": "checkLedger",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.LedgerCheck"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/limits/": {
+            "get": {
+                "description": "Return active velocity limits of account and default ones in order of currency, period and account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List velocity limits",
+                "operationId": "listVelocityLimits",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID, limits of all accounts by default",
+                        "name": "accountId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Limit of total amount and number of debits of account in currency per calendar day, week or month (UTC). Limit without account applies to all accounts, the limit replaces active limit of the same account, currency and period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create velocity limit",
+                "operationId": "createVelocityLimit",
+                "parameters": [
+                    {
+                        "description": "Velocity limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.VelocityLimit"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/limits/{id}/disable": {
+            "put": {
+                "description": "Disable active velocity limit, debits are not limited by it anymore",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disable velocity limit",
+                "operationId": "disableVelocityLimit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Velocity limit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.VelocityLimit"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rate": {
+            "post": {
+                "description": "Add rate of currency pair to the rate history, the rate is valid till the next rate of the pair",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rate"
+                ],
+                "summary": "Add exchange rate",
+                "operationId": "createRate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency code",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency code",
+                        "name": "quote",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Price of one unit of base currency in quote currency",
+                        "name": "rate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Share of converted amount kept by the service, 0 by default",
+                        "name": "spread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of validity in RFC 3339, not in the past and after the latest rate of the pair, current time by default",
+                        "name": "validFrom",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rate/history/{base}/{quote}": {
+            "get": {
+                "description": "Return history of currency pair's rates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rate"
+                ],
+                "summary": "Exchange rate history",
+                "operationId": "rateHistory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency code",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency code",
+                        "name": "quote",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The value of limit in pagination",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The value of offset in pagination",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rate/{base}/{quote}": {
+            "get": {
+                "description": "Returns rate of currency pair which was valid at the moment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rate"
+                ],
+                "summary": "Get exchange rate",
+                "operationId": "getRate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Base currency code",
+                        "name": "base",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Quote currency code",
+                        "name": "quote",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Moment in RFC 3339, current time by default",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/": {
+            "post": {
+                "description": "Move amount of buyer to escrow until it is released to seller or refunded to buyer, deal which is not released for TTL is refunded. Returns deal and buyer account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Fund safe deal",
+                "operationId": "fundEscrow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID of buyer",
+                        "name": "buyerId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account ID of seller",
+                        "name": "sellerId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID, one order of buyer has one safe deal",
+                        "name": "orderId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount of deal",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time to release deal, e.g. 72h, 336h by default",
+                        "name": "ttl",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of deal",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code of deal",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source service of deal",
+                        "name": "source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/{id}": {
+            "get": {
+                "description": "Return safe deal by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Get safe deal",
+                "operationId": "getEscrow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Safe deal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/{id}/dispute": {
+            "put": {
+                "description": "Dispute funded safe deal, money is held until the dispute is resolved by release or refund",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Dispute safe deal",
+                "operationId": "disputeEscrow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Safe deal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of dispute",
+                        "name": "reason",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/{id}/history": {
+            "get": {
+                "description": "Return history of safe deal's status changes with reasons and journal entries of moved money",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Safe deal history",
+                "operationId": "escrowHistory",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Safe deal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/{id}/refund": {
+            "put": {
+                "description": "Return amount of funded or disputed safe deal to buyer. Returns deal and buyer account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Refund safe deal",
+                "operationId": "refundEscrow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Safe deal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of refund",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/escrows/{id}/release": {
+            "put": {
+                "description": "Pay amount of funded or disputed safe deal to seller, e.g. delivery is confirmed. Returns deal and seller account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "escrow"
+                ],
+                "summary": "Release safe deal",
+                "operationId": "releaseEscrow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Safe deal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of release",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/hold/": {
+            "post": {
+                "description": "Reserve amount on account for order, reserved amount is not available for redeem",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hold"
+                ],
+                "summary": "Create hold",
+                "operationId": "createHold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID",
+                        "name": "accountId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID, one order has one hold on account",
+                        "name": "orderId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount to reserve",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time to live of hold, e.g. 15m or 2h, 24h by default",
+                        "name": "ttl",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdAccount"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.insufficientFundsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/hold/{id}": {
+            "get": {
+                "description": "Return hold by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hold"
+                ],
+                "summary": "Get hold",
+                "operationId": "getHold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/hold/{id}/capture": {
+            "put": {
+                "description": "Charge amount of hold from account, the rest of hold is released",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hold"
+                ],
+                "summary": "Capture hold",
+                "operationId": "captureHold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount to charge, whole hold by default",
+                        "name": "amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdAccount"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/hold/{id}/release": {
+            "put": {
+                "description": "Return reserved amount of hold to available balance of account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "hold"
+                ],
+                "summary": "Release hold",
+                "operationId": "releaseHold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.holdAccount"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/": {
+            "get": {
+                "description": "Return recurring payment plans in order of ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "List recurring payment plans",
+                "operationId": "listRecurringPlans",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID on any side of plan",
+                        "name": "accountId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of plan: active, suspended, completed or cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of page, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of page",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Periodic transfer from redeem account to accrual account by interval or cron schedule, e.g. subscription. Failed charge is retried, then plan is suspended",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Create recurring payment plan",
+                "operationId": "createRecurringPlan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID for redeem funds",
+                        "name": "redeemId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account ID for accrual funds",
+                        "name": "accrId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount of charge",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval like @every 720h or cron expression in UTC like 0 10 1 * *",
+                        "name": "schedule",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of plan in RFC3339, now by default",
+                        "name": "startAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of charges, no limit by default",
+                        "name": "maxCharges",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of plan in RFC3339",
+                        "name": "endAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of charges",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code of charges",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source service of charges",
+                        "name": "source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecurringPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}": {
+            "get": {
+                "description": "Return recurring payment plan by ID with number of charges and failed attempts of the next charge",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get recurring payment plan",
+                "operationId": "getRecurringPlan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecurringPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}/attempts": {
+            "get": {
+                "description": "Return history of attempts of charges of plan, successful and failed ones with reason",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Get attempts of recurring charges",
+                "operationId": "getRecurringAttempts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of page, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of page",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}/cancel": {
+            "put": {
+                "description": "Cancel active or suspended plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Cancel recurring payment plan",
+                "operationId": "cancelRecurringPlan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecurringPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/recurring/{id}/resume": {
+            "put": {
+                "description": "Activate plan which is suspended after failed retries, the failed charge is attempted at once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recurring"
+                ],
+                "summary": "Resume recurring payment plan",
+                "operationId": "resumeRecurringPlan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recurring plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecurringPlan"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{id}/reverse": {
+            "post": {
+                "description": "Reverse accrual or redeem fully or partially with compensating transactions, transfer is reversed on both accounts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Reverse transaction",
+                "operationId": "reverse",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount to reverse in currency of transaction, the whole remaining amount by default",
+                        "name": "amount",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/batch": {
+            "post": {
+                "description": "Make all legs of transfer at once or none of them, e.g. buyer pays seller, platform fee and courier. Returns all accounts of legs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Batch transfer",
+                "operationId": "batchTransfer",
+                "parameters": [
+                    {
+                        "description": "Legs of transfer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.batchTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of request, retry with the same key returns the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/scheduled": {
+            "get": {
+                "description": "Return scheduled transfers in order of run time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "List scheduled transfers",
+                "operationId": "listScheduledTransfers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID on any side of transfer",
+                        "name": "accountId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status of transfer: pending, executed, failed or cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit of page, 50 by default",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset of page",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedule transfer between accounts of the same currency at run time, failed attempt is retried later",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Create scheduled transfer",
+                "operationId": "createScheduledTransfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Account ID for redeem funds",
+                        "name": "redeemId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Account ID for accrual funds",
+                        "name": "accrId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Amount of transfer",
+                        "name": "amount",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run time of transfer in RFC3339, e.g. 2022-11-01T10:00:00Z",
+                        "name": "runAt",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of transfer",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purpose code of transfer",
+                        "name": "purpose",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source service of transfer",
+                        "name": "source",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduledTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/scheduled/{id}": {
+            "get": {
+                "description": "Return scheduled transfer by ID with its status, attempts and reason of the last failure",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get scheduled transfer",
+                "operationId": "getScheduledTransfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduledTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change amount or run time of pending transfer, failed attempts of transfer are forgotten",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Update scheduled transfer",
+                "operationId": "updateScheduledTransfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "New amount of transfer",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "New run time of transfer in RFC3339",
+                        "name": "runAt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduledTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/scheduled/{id}/cancel": {
+            "put": {
+                "description": "Cancel pending transfer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Cancel scheduled transfer",
+                "operationId": "cancelScheduledTransfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduledTransfer"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        },
+        "/transfers/split": {
+            "post": {
+                "description": "Split payment of one payer between many payees by percents or fixed amounts at once, e.g. order of several sellers. Unit left by rounding of percents goes to share with the largest remainder, zero amount of fixed shares means their sum",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Split transfer",
+                "operationId": "splitTransfer",
+                "parameters": [
+                    {
+                        "description": "Payer, amount and shares of payees",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.splitTransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of request, retry with the same key returns the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.correctResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/v1.velocityLimitResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/v1.response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.Account": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "string",
+                    "example": "56.5"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "56.5"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "credit_limit": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "reserved": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_dt": {
+                    "type": "string"
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Campaign": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "string",
+                    "example": "100000"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_dt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "monthly_cap": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "spent": {
+                    "type": "string",
+                    "example": "1500"
+                },
+                "start_dt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_dt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FeeRule": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "flat": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "max_fee": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "min_fee": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "1.5"
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeeTier"
+                    }
+                }
+            }
+        },
+        "entity.FeeTier": {
+            "type": "object",
+            "properties": {
+                "flat": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "10000"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "0.5"
+                }
+            }
+        },
+        "entity.Hold": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "captured": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "expires_dt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_dt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.LedgerCheck": {
+            "type": "object",
+            "properties": {
+                "balanced": {
+                    "type": "boolean"
+                },
+                "totals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LedgerTotal"
+                    }
+                },
+                "unbalanced_journals": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "entity.LedgerTotal": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "sum": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "entity.RecurringPlan": {
+            "type": "object",
+            "properties": {
+                "accrual_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "299"
+                },
+                "charges": {
+                    "type": "integer"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_dt": {
+                    "type": "string"
+                },
+                "failed_attempts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_charges": {
+                    "type": "integer"
+                },
+                "next_attempt_dt": {
+                    "type": "string"
+                },
+                "next_charge_dt": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "redeem_id": {
+                    "type": "integer"
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "0 10 1 * *"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_dt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ScheduledTransfer": {
+            "type": "object",
+            "properties": {
+                "accrual_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "failure": {
+                    "type": "string"
+                },
+                "failure_code": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "journal_id": {
+                    "type": "integer"
+                },
+                "next_attempt_dt": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "redeem_id": {
+                    "type": "integer"
+                },
+                "run_dt": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_dt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.SplitShare": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "700"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "70"
+                }
+            }
+        },
+        "entity.TransferLeg": {
+            "type": "object",
+            "properties": {
+                "accrual_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "redeem_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.VelocityLimit": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "created_dt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_amount": {
+                    "type": "string",
+                    "example": "50000"
+                },
+                "max_count": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "period": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.batchTransferRequest": {
+            "type": "object",
+            "properties": {
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TransferLeg"
+                    }
+                }
+            }
+        },
+        "v1.campaignRequest": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "string",
+                    "example": "100000"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_dt": {
+                    "type": "string"
+                },
+                "monthly_cap": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Cashback on groceries"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "5"
+                },
+                "purpose": {
+                    "type": "string",
+                    "example": "groceries"
+                },
+                "start_dt": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.correctResponse": {
+            "type": "object",
+            "properties": {
+                "data": {}
+            }
+        },
+        "v1.feeRuleRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "flat": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "percent"
+                },
+                "max_fee": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "min_fee": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "transfer"
+                },
+                "percent": {
+                    "type": "string",
+                    "example": "1.5"
+                },
+                "tier": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeeTier"
+                    }
+                }
+            }
+        },
+        "v1.holdAccount": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/entity.Account"
+                },
+                "hold": {
+                    "$ref": "#/definitions/entity.Hold"
+                }
+            }
+        },
+        "v1.insufficientFundsResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "string",
+                    "example": "30"
+                },
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "not_enough_money",
+                        "credit_limit_exceeded"
+                    ],
+                    "example": "not_enough_money"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "not enough money, available amount is 30"
+                }
+            }
+        },
+        "v1.response": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "message"
+                }
+            }
+        },
+        "v1.splitTransferRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1000"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "redeem_id": {
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.SplitShare"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.transferAccountPair": {
+            "type": "object",
+            "properties": {
+                "accrualAccount": {
+                    "$ref": "#/definitions/entity.Account"
+                },
+                "redeemAccount": {
+                    "$ref": "#/definitions/entity.Account"
+                }
+            }
+        },
+        "v1.velocityLimitRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "max_amount": {
+                    "type": "string",
+                    "example": "50000"
+                },
+                "max_count": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "period": {
+                    "type": "string",
+                    "example": "day"
+                }
+            }
+        },
+        "v1.velocityLimitResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "velocity limit exceeded"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "amount"
+                },
+                "max": {
+                    "type": "string",
+                    "example": "50000"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "remaining": {
+                    "type": "string",
+                    "example": "1500"
                 }
             }
         }
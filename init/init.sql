@@ -1,20 +1,303 @@
 -- \c avito_processing;
 DROP TYPE IF EXISTS trans_type;
+DROP TYPE IF EXISTS account_status;
+DROP TYPE IF EXISTS hold_status;
+DROP TYPE IF EXISTS account_type;
+DROP TYPE IF EXISTS scheduled_status;
+DROP TYPE IF EXISTS recurring_status;
+DROP TYPE IF EXISTS recurring_attempt_status;
+DROP TYPE IF EXISTS account_tier;
+DROP TYPE IF EXISTS fee_operation;
+DROP TYPE IF EXISTS fee_kind;
+DROP TYPE IF EXISTS campaign_status;
+DROP TYPE IF EXISTS escrow_status;
+DROP TYPE IF EXISTS limit_period;
 DROP TABLE IF EXISTS account;
+DROP TABLE IF EXISTS account_status_history;
 DROP TABLE IF EXISTS fct_transcation;
-CREATE TYPE trans_type AS ENUM ('accrual', 'redeem');
+DROP TABLE IF EXISTS exchange_rate;
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS hold;
+DROP TABLE IF EXISTS scheduled_transfer;
+DROP TABLE IF EXISTS recurring_plan;
+DROP TABLE IF EXISTS recurring_attempt;
+DROP TABLE IF EXISTS fee_rule;
+DROP TABLE IF EXISTS velocity_limit;
+DROP TABLE IF EXISTS campaign;
+DROP TABLE IF EXISTS escrow;
+DROP TABLE IF EXISTS escrow_history;
+DROP TABLE IF EXISTS journal;
+DROP FUNCTION IF EXISTS check_journal_balance;
+CREATE TYPE trans_type AS ENUM ('accrual', 'redeem', 'reversal', 'fee', 'cashback', 'hold_expired');
+CREATE TYPE account_status AS ENUM ('active', 'frozen', 'closed');
+CREATE TYPE hold_status AS ENUM ('active', 'captured', 'released', 'expired');
+CREATE TYPE account_type AS ENUM ('customer', 'system');
+CREATE TYPE scheduled_status AS ENUM ('pending', 'executed', 'failed', 'cancelled');
+CREATE TYPE recurring_status AS ENUM ('active', 'suspended', 'completed', 'cancelled');
+CREATE TYPE recurring_attempt_status AS ENUM ('succeeded', 'failed');
+CREATE TYPE account_tier AS ENUM ('standard', 'premium', 'business');
+CREATE TYPE fee_operation AS ENUM ('transfer', 'batch_transfer', 'withdrawal');
+CREATE TYPE fee_kind AS ENUM ('flat', 'percent', 'tiered');
+CREATE TYPE campaign_status AS ENUM ('active', 'paused', 'exhausted');
+CREATE TYPE escrow_status AS ENUM ('funded', 'disputed', 'released', 'refunded');
+CREATE TYPE limit_period AS ENUM ('day', 'week', 'month');
 CREATE TABLE account (
 	id BIGSERIAL PRIMARY KEY,
-	balance NUMERIC (16, 3) NOT NULL DEFAULT 0.000 CHECK (balance >= 0.000),
-    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    owner_id VARCHAR(64), -- external user ID
+	balance NUMERIC (16, 3) NOT NULL DEFAULT 0.000,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    status account_status NOT NULL DEFAULT 'active',
+    status_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    credit_limit NUMERIC (16, 3) NOT NULL DEFAULT 0.000 CHECK (credit_limit >= 0.000),
+    reserved NUMERIC (16, 3) NOT NULL DEFAULT 0.000 CHECK (reserved >= 0.000), -- sum of active holds
+    wallet VARCHAR(16) NOT NULL DEFAULT 'main',
+    type account_type NOT NULL DEFAULT 'customer',
+    tier account_tier NOT NULL DEFAULT 'standard', -- service level, fees depend on it
+    system_code VARCHAR(32), -- code of system account, NULL for customer account
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (balance + credit_limit - reserved >= 0.000),
+    CHECK ((type = 'system') = (system_code IS NOT NULL)),
+    UNIQUE (owner_id, wallet),
+    UNIQUE (system_code, currency)
+);
+CREATE TABLE account_status_history (
+	id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT REFERENCES account ON DELETE CASCADE,
+    from_status account_status NOT NULL,
+    to_status account_status NOT NULL,
+    reason TEXT NOT NULL,
+    sweep_id BIGINT REFERENCES account, -- account the balance was swept to
+    changed_dt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE TABLE exchange_rate (
+	id BIGSERIAL PRIMARY KEY,
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    spread NUMERIC(6, 5) NOT NULL DEFAULT 0 CHECK (spread >= 0 AND spread < 1),
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMPTZ CHECK (valid_to > valid_from),
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (base, quote, valid_from)
+);
+CREATE TABLE hold (
+	id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    order_id VARCHAR(64) NOT NULL,
+    amount NUMERIC(16, 3) NOT NULL CHECK (amount > 0),
+    captured NUMERIC(16, 3) NOT NULL DEFAULT 0 CHECK (captured >= 0 AND captured <= amount),
+    currency CHAR(3) NOT NULL,
+    status hold_status NOT NULL DEFAULT 'active',
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_dt TIMESTAMPTZ NOT NULL CHECK (expires_dt > created_dt),
+    updated_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, order_id)
+);
+CREATE INDEX hold_active_expires_idx ON hold (expires_dt) WHERE status = 'active';
+CREATE TABLE campaign (
+	id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(128) NOT NULL,
+    purpose VARCHAR(32) NOT NULL DEFAULT '', -- purpose code of redeems, empty means any redeem
+    currency CHAR(3) NOT NULL,
+    percent NUMERIC(7, 4) NOT NULL CHECK (percent > 0 AND percent < 100),
+    monthly_cap NUMERIC(16, 3) CHECK (monthly_cap > 0), -- cashback of account in calendar month, NULL means no cap
+    budget NUMERIC(16, 3) NOT NULL CHECK (budget >= 0),
+    spent NUMERIC(16, 3) NOT NULL DEFAULT 0.000 CHECK (spent >= 0), -- cashback paid minus cashback taken back
+    status campaign_status NOT NULL DEFAULT 'active',
+    start_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    end_dt TIMESTAMPTZ CHECK (end_dt > start_dt),
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_dt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX campaign_active_idx ON campaign (currency) WHERE status = 'active';
+CREATE TABLE escrow (
+	id BIGSERIAL PRIMARY KEY,
+    buyer_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    seller_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    order_id VARCHAR(64) NOT NULL,
+    amount NUMERIC(16, 3) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    status escrow_status NOT NULL DEFAULT 'funded',
+    expires_dt TIMESTAMPTZ NOT NULL CHECK (expires_dt > created_dt), -- funded deal is refunded after it
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    purpose VARCHAR(32) NOT NULL DEFAULT '',
+    source VARCHAR(64) NOT NULL DEFAULT '',
+    CHECK (buyer_id <> seller_id),
+    UNIQUE (buyer_id, order_id)
+);
+CREATE INDEX escrow_funded_expires_idx ON escrow (expires_dt) WHERE status = 'funded';
+CREATE INDEX escrow_seller_idx ON escrow (seller_id);
+CREATE TABLE journal (
+	id BIGSERIAL PRIMARY KEY,
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    batch_id BIGINT REFERENCES journal -- first entry of batch the entry belongs to, NULL for entry out of batch
 );
 CREATE TABLE fct_transcation (
 	id SERIAL PRIMARY KEY,
     trans_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     account_id BIGINT REFERENCES account ON DELETE CASCADE,
-    doc_num BIGINT DEFAULT -999, -- redeem_id
+    doc_num BIGINT, -- account of the other side of operation
+    journal_id BIGINT REFERENCES journal, -- journal entry of posting, NULL for memo of expired hold
     type trans_type,
-	amount NUMERIC(16, 3) NOT NULL
+	amount NUMERIC(16, 3) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    rate_id BIGINT REFERENCES exchange_rate, -- rate of conversion
+    rate NUMERIC(20, 10),
+    spread NUMERIC(6, 5),
+    hold_id BIGINT REFERENCES hold, -- hold the amount was reserved by
+    reversal_of BIGINT REFERENCES fct_transcation, -- reversed transaction
+    fee_of BIGINT REFERENCES fct_transcation, -- transaction of operation the fee is charged for
+    campaign_id BIGINT REFERENCES campaign, -- campaign the cashback is paid by
+    escrow_id BIGINT REFERENCES escrow, -- safe deal the money is held for
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    purpose VARCHAR(32) NOT NULL DEFAULT '', -- purpose code of operation
+    source VARCHAR(64) NOT NULL DEFAULT '', -- service which made the operation
+    CHECK (journal_id IS NOT NULL OR type = 'hold_expired' AND amount = 0)
+);
+CREATE INDEX fct_transcation_journal_idx ON fct_transcation (journal_id);
+CREATE INDEX fct_transcation_reversal_idx ON fct_transcation (reversal_of);
+CREATE INDEX fct_transcation_purpose_idx ON fct_transcation (account_id, purpose);
+CREATE INDEX fct_transcation_fee_idx ON fct_transcation (fee_of);
+CREATE INDEX fct_transcation_campaign_idx ON fct_transcation (campaign_id, account_id, trans_dt);
+CREATE INDEX fct_transcation_escrow_idx ON fct_transcation (escrow_id);
+CREATE INDEX fct_transcation_debit_idx ON fct_transcation (account_id, trans_dt) WHERE type = 'redeem';
+CREATE TABLE fee_rule (
+	id BIGSERIAL PRIMARY KEY,
+    operation fee_operation NOT NULL,
+    currency CHAR(3) NOT NULL,
+    tier account_tier, -- NULL means any tier
+    kind fee_kind NOT NULL,
+    flat NUMERIC(16, 3) NOT NULL DEFAULT 0 CHECK (flat >= 0),
+    percent NUMERIC(7, 4) NOT NULL DEFAULT 0 CHECK (percent >= 0 AND percent < 100),
+    tiers JSONB NOT NULL DEFAULT '[]', -- tiers of amount with their flat fee and percent
+    min_fee NUMERIC(16, 3) CHECK (min_fee >= 0),
+    max_fee NUMERIC(16, 3) CHECK (max_fee >= min_fee),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- One active rule of operation, currency and tier, or any tier.
+CREATE UNIQUE INDEX fee_rule_tier_idx ON fee_rule (operation, currency, tier) WHERE active AND tier IS NOT NULL;
+CREATE UNIQUE INDEX fee_rule_any_tier_idx ON fee_rule (operation, currency) WHERE active AND tier IS NULL;
+CREATE TABLE velocity_limit (
+	id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT REFERENCES account ON DELETE CASCADE, -- NULL means default limit of all accounts
+    currency CHAR(3) NOT NULL,
+    period limit_period NOT NULL,
+    max_amount NUMERIC(16, 3) CHECK (max_amount >= 0), -- total amount of debits in period
+    max_count INT CHECK (max_count >= 0), -- number of debits in period
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (max_amount IS NOT NULL OR max_count IS NOT NULL)
+);
+-- One active limit of account, or of all accounts, in currency per period.
+CREATE UNIQUE INDEX velocity_limit_account_idx ON velocity_limit (account_id, currency, period) WHERE active AND account_id IS NOT NULL;
+CREATE UNIQUE INDEX velocity_limit_default_idx ON velocity_limit (currency, period) WHERE active AND account_id IS NULL;
+CREATE TABLE scheduled_transfer (
+	id BIGSERIAL PRIMARY KEY,
+    redeem_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    accrual_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    amount NUMERIC(16, 3) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    status scheduled_status NOT NULL DEFAULT 'pending',
+    run_dt TIMESTAMPTZ NOT NULL, -- planned time of transfer
+    next_attempt_dt TIMESTAMPTZ NOT NULL, -- time of the next attempt of pending transfer
+    attempts INT NOT NULL DEFAULT 0,
+    failure TEXT NOT NULL DEFAULT '', -- reason of the last failed attempt
+    failure_code VARCHAR(32) NOT NULL DEFAULT '', -- kind of reason of the last failed attempt
+    journal_id BIGINT REFERENCES journal, -- journal entry of executed transfer
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    purpose VARCHAR(32) NOT NULL DEFAULT '',
+    source VARCHAR(64) NOT NULL DEFAULT '',
+    CHECK (redeem_id <> accrual_id)
+);
+CREATE INDEX scheduled_transfer_pending_idx ON scheduled_transfer (next_attempt_dt) WHERE status = 'pending';
+CREATE INDEX scheduled_transfer_redeem_idx ON scheduled_transfer (redeem_id);
+CREATE INDEX scheduled_transfer_accrual_idx ON scheduled_transfer (accrual_id);
+CREATE TABLE recurring_plan (
+	id BIGSERIAL PRIMARY KEY,
+    redeem_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    accrual_id BIGINT NOT NULL REFERENCES account ON DELETE CASCADE,
+    amount NUMERIC(16, 3) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    schedule VARCHAR(64) NOT NULL, -- interval (@every 720h) or cron expression
+    status recurring_status NOT NULL DEFAULT 'active',
+    next_charge_dt TIMESTAMPTZ NOT NULL, -- scheduled time of the next charge
+    next_attempt_dt TIMESTAMPTZ NOT NULL, -- time of the next attempt of the charge, later while it is retried
+    charges INT NOT NULL DEFAULT 0 CHECK (charges >= 0),
+    max_charges INT NOT NULL DEFAULT 0 CHECK (max_charges >= 0), -- 0 means no limit
+    end_dt TIMESTAMPTZ,
+    failed_attempts INT NOT NULL DEFAULT 0, -- failed attempts of the next charge
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    purpose VARCHAR(32) NOT NULL DEFAULT '',
+    source VARCHAR(64) NOT NULL DEFAULT '',
+    CHECK (redeem_id <> accrual_id)
+);
+CREATE INDEX recurring_plan_active_idx ON recurring_plan (next_attempt_dt) WHERE status = 'active';
+CREATE INDEX recurring_plan_redeem_idx ON recurring_plan (redeem_id);
+CREATE INDEX recurring_plan_accrual_idx ON recurring_plan (accrual_id);
+CREATE TABLE recurring_attempt (
+	id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES recurring_plan ON DELETE CASCADE,
+    charge INT NOT NULL, -- number of charge in plan
+    attempt_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    amount NUMERIC(16, 3) NOT NULL,
+    status recurring_attempt_status NOT NULL,
+    failure TEXT NOT NULL DEFAULT '',
+    failure_code VARCHAR(32) NOT NULL DEFAULT ''
+);
+CREATE INDEX recurring_attempt_plan_idx ON recurring_attempt (plan_id, id);
+CREATE TABLE escrow_history (
+	id BIGSERIAL PRIMARY KEY,
+    escrow_id BIGINT NOT NULL REFERENCES escrow ON DELETE CASCADE,
+    from_status escrow_status, -- NULL for funding
+    to_status escrow_status NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    journal_id BIGINT REFERENCES journal, -- journal entry of money moved by the change
+    changed_dt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX escrow_history_escrow_idx ON escrow_history (escrow_id, id);
+CREATE TABLE idempotency_key (
+	scope VARCHAR(128) NOT NULL, -- operation and account of caller the key belongs to
+    key VARCHAR(128) NOT NULL,
+    request_hash CHAR(64) NOT NULL, -- sha256 of request payload
+    response JSONB NOT NULL,
+    created_dt TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, key)
 );
-INSERT INTO account VALUES(-999, DEFAULT, DEFAULT);
+-- Postings of journal entry are balanced in every currency, the check is done on commit
+-- as postings of entry are written one by one.
+CREATE FUNCTION check_journal_balance() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM fct_transcation
+        WHERE journal_id = NEW.journal_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+CREATE CONSTRAINT TRIGGER fct_transcation_journal_balance
+    AFTER INSERT ON fct_transcation
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW WHEN (NEW.journal_id IS NOT NULL)
+    EXECUTE FUNCTION check_journal_balance();
+-- Chart of system accounts, there is one account of each code in every currency, e.g. cash_in in RUB is -101.
+INSERT INTO account (id, type, currency, system_code)
+SELECT -(s.n * 100 + c.n), 'system'::account_type, c.currency, s.code
+FROM (VALUES (1, 'cash_in'), (2, 'cash_out'), (3, 'fees'), (4, 'suspense'), (5, 'write_off'), (6, 'exchange'), (7, 'promo'), (8, 'escrow')) AS s (n, code)
+CROSS JOIN (VALUES (1, 'RUB'), (2, 'USD'), (3, 'EUR'), (4, 'GBP'), (5, 'CNY'), (6, 'KZT'), (7, 'JPY')) AS c (n, currency);
+INSERT INTO exchange_rate (base, quote, rate, valid_from) VALUES
+    ('USD', 'RUB', 62.5, '2022-07-11T00:00:00Z'),
+    ('EUR', 'RUB', 63.1, '2022-07-11T00:00:00Z'),
+    ('CNY', 'RUB', 9.31, '2022-07-11T00:00:00Z'),
+    ('JPY', 'RUB', 0.4589, '2022-07-11T00:00:00Z');
 
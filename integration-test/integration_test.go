@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	. "github.com/Eun/go-hit"
 	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -69,6 +71,18 @@ func TestHttp_GetId(t *testing.T) {
 		Expect().Status().Equal(http.StatusOK),
 		Expect().Body().String().Contains(`{"data":{"id":2`),
 	)
+	Test(t,
+		Description("Get account by ID: balance converted to USD"),
+		Get(basePath+"/account/2?currency=USD"),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"conversion":{"currency":"USD"`),
+	)
+	Test(t,
+		Description("Get account by ID: unknown currency"),
+		Get(basePath+"/account/2?currency=XYZ"),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`unknown currency`),
+	)
 	Test(t,
 		Description("Get account by ID: incorrect ID"),
 		Get(basePath+"/account/ry1"),
@@ -101,24 +115,24 @@ func TestHttp_UpdateBalance(t *testing.T) {
 		Description("Update account's balance: increase balance for accout with ID=1"),
 		Put(basePath+"/account/1?amount=35"),
 		Expect().Status().Equal(http.StatusOK),
-		Expect().Body().String().Contains(`{"data":{"id":1,"balance":35`),
+		Expect().Body().String().Contains(`{"data":{"id":1,"balance":"35"`),
 	)
 	Test(t,
 		Description("Update account's balance: increase balance for accout with ID=2"),
 		Put(basePath+"/account/2?amount=5"),
 		Expect().Status().Equal(http.StatusOK),
-		Expect().Body().String().Contains(`{"data":{"id":2,"balance":5`),
+		Expect().Body().String().Contains(`{"data":{"id":2,"balance":"5"`),
 	)
 	Test(t,
 		Description("Update account's balance: decrease balance"),
 		Put(basePath+"/account/1?amount=-5"),
 		Expect().Status().Equal(http.StatusOK),
-		Expect().Body().String().Contains(`{"data":{"id":1,"balance":30`),
+		Expect().Body().String().Contains(`{"data":{"id":1,"balance":"30"`),
 	)
 	Test(t,
 		Description("Update account's balance: not enough money for decrease balance"),
 		Put(basePath+"/account/1?amount=-105"),
-		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
 		Expect().Body().String().Contains(`not enough money`),
 	)
 }
@@ -126,7 +140,7 @@ func TestHttp_UpdateBalance(t *testing.T) {
 // HTTP PUT:  /account/amount/:redeemId/transfer/:accrId?amount=.
 func TestHttp_TransferAmount(t *testing.T) {
 	var accrualId, redeemId int
-	var accrualBalance, redeemBalance float64
+	var accrualBalance, redeemBalance string
 
 	Test(t,
 		Description("Transfer amount between accounts: case of correct work"),
@@ -140,13 +154,13 @@ func TestHttp_TransferAmount(t *testing.T) {
 
 	require.Equal(t, 1, accrualId)
 	require.Equal(t, 2, redeemId)
-	require.Equal(t, 31.0, accrualBalance)
-	require.Equal(t, 4.0, redeemBalance)
+	require.Equal(t, "31", accrualBalance)
+	require.Equal(t, "4", redeemBalance)
 
 	Test(t,
 		Description("Transfer amount between accounts: case of not enough money"),
 		Put(basePath+"/account/amount/2/transfer/1?amount=150"),
-		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
 		Expect().Body().String().Contains(`not enough money`),
 	)
 
@@ -162,9 +176,9 @@ func TestHttp_TransferAmount(t *testing.T) {
 func TestHttp_GetHistory(t *testing.T) {
 	var transactions *[]entity.Transaction
 	var expectedTransactions []entity.Transaction = []entity.Transaction{
-		{Id: 1, AccountId: 1, DocNum: -999, Type: "accrual", Amount: 35},
-		{Id: 3, AccountId: 1, DocNum: -999, Type: "redeem", Amount: -5},
-		{Id: 5, AccountId: 1, DocNum: -999, Type: "accrual", Amount: 1},
+		{Id: 1, AccountId: 1, DocNum: -101, Type: "accrual", Amount: decimal.NewFromInt(35)},
+		{Id: 3, AccountId: 1, DocNum: -201, Type: "redeem", Amount: decimal.NewFromInt(-5)},
+		{Id: 5, AccountId: 1, DocNum: -101, Type: "accrual", Amount: decimal.NewFromInt(1)},
 	}
 
 	Test(t,
@@ -178,7 +192,7 @@ func TestHttp_GetHistory(t *testing.T) {
 
 	for i, transaction := range *transactions {
 		require.Equal(t, expectedTransactions[i].Type, transaction.Type)
-		require.Equal(t, expectedTransactions[i].Amount, transaction.Amount)
+		require.True(t, expectedTransactions[i].Amount.Equal(transaction.Amount))
 	}
 
 	Test(t,
@@ -208,11 +222,11 @@ func TestHttp_GetHistory(t *testing.T) {
 	require.Equal(t, 3, len(*transactions))
 
 	sort.Slice(expectedTransactions, func(i, j int) bool {
-		return expectedTransactions[i].Amount < expectedTransactions[j].Amount
+		return expectedTransactions[i].Amount.LessThan(expectedTransactions[j].Amount)
 	})
 
 	for i, transaction := range *transactions {
-		require.Equal(t, expectedTransactions[i].Amount, transaction.Amount)
+		require.True(t, expectedTransactions[i].Amount.Equal(transaction.Amount))
 	}
 
 	Test(t,
@@ -225,11 +239,11 @@ func TestHttp_GetHistory(t *testing.T) {
 	require.Equal(t, 3, len(*transactions))
 
 	sort.Slice(expectedTransactions, func(i, j int) bool {
-		return expectedTransactions[i].Amount > expectedTransactions[j].Amount
+		return expectedTransactions[i].Amount.GreaterThan(expectedTransactions[j].Amount)
 	})
 
 	for i, transaction := range *transactions {
-		require.Equal(t, expectedTransactions[i].Amount, transaction.Amount)
+		require.True(t, expectedTransactions[i].Amount.Equal(transaction.Amount))
 	}
 
 	Test(t,
@@ -245,3 +259,1535 @@ func TestHttp_GetHistory(t *testing.T) {
 		Expect().Body().String().Contains(`incorrect limit value`),
 	)
 }
+
+// HTTP POST: /admin/rate, GET: /admin/rate/:base/:quote?at=.
+func TestHttp_Rate(t *testing.T) {
+	Test(t,
+		Description("Add exchange rate: case of correct work"),
+		Post(basePath+"/admin/rate?base=USD&quote=RUB&rate=70"),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"rate":"70"`),
+	)
+	Test(t,
+		Description("Add exchange rate: start of validity in the past"),
+		Post(basePath+"/admin/rate?base=USD&quote=RUB&rate=71&validFrom=2022-08-01T00:00:00Z"),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`start of validity of rate is in the past`),
+	)
+	Test(t,
+		Description("Get exchange rate: rate valid in the past is not changed"),
+		Get(basePath+"/admin/rate/USD/RUB?at=2022-08-01T00:00:00Z"),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"rate":"62.5"`),
+		Expect().Body().String().Contains(`"valid_from":"2022-07-11T00:00:00Z"`),
+	)
+
+	validFrom := time.Now().Add(24 * time.Hour).UTC()
+	Test(t,
+		Description("Add exchange rate: rate valid from tomorrow"),
+		Post(fmt.Sprintf("%s/admin/rate?base=EUR&quote=RUB&rate=64&validFrom=%s", basePath, validFrom.Format(time.RFC3339))),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Add exchange rate: start of validity before the latest rate"),
+		Post(fmt.Sprintf("%s/admin/rate?base=EUR&quote=RUB&rate=65&validFrom=%s", basePath, validFrom.Add(-time.Hour).Format(time.RFC3339))),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`same or later start of validity already exists`),
+	)
+	Test(t,
+		Description("Get exchange rate: rate before history"),
+		Get(basePath+"/admin/rate/USD/RUB?at=2021-07-20T00:00:00Z"),
+		Expect().Status().Equal(http.StatusNotFound),
+		Expect().Body().String().Contains(`exchange rate not found`),
+	)
+}
+
+// HTTP PUT:  /account/amount/:redeemId/transfer/:accrId?amount=&convert=.
+func TestHttp_TransferAmountConversion(t *testing.T) {
+	var usdId int64
+	var accrualBalance, redeemBalance string
+
+	Test(t,
+		Description("Create account in USD"),
+		Post(basePath+"/account?currency=USD"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&usdId),
+	)
+	Test(t,
+		Description("Update account's balance in USD"),
+		Put(fmt.Sprintf("%s/account/%d?amount=10.5", basePath, usdId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Transfer amount between accounts: conversion is not requested"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/2?amount=1", basePath, usdId)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`accounts have different currencies`),
+	)
+	Test(t,
+		Description("Transfer amount between accounts: case of conversion"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/2?amount=1&convert=true", basePath, usdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.accrualAccount.balance").In(&accrualBalance),
+		Store().Response().Body().JSON().JQ(".data.redeemAccount.balance").In(&redeemBalance),
+	)
+
+	require.Equal(t, "74", accrualBalance)
+	require.Equal(t, "9.5", redeemBalance)
+}
+
+// HTTP PUT: /admin/account/:id/freeze, /admin/account/:id/unfreeze, /admin/account/:id/close.
+func TestHttp_AccountLifecycle(t *testing.T) {
+	var id int64
+
+	Test(t,
+		Description("Create account for lifecycle"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	Test(t,
+		Description("Update account's balance before freeze"),
+		Put(fmt.Sprintf("%s/account/%d?amount=10", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Freeze account: reason is empty"),
+		Put(fmt.Sprintf("%s/admin/account/%d/freeze", basePath, id)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`reason is empty`),
+	)
+	Test(t,
+		Description("Freeze account: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/account/%d/freeze?reason=check", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"frozen"`),
+	)
+	Test(t,
+		Description("Frozen account rejects debit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-1", basePath, id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`account is frozen`),
+	)
+	Test(t,
+		Description("Frozen account accepts credit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Close account: balance is not zero"),
+		Put(fmt.Sprintf("%s/admin/account/%d/close?reason=request", basePath, id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`account balance is not zero`),
+	)
+	Test(t,
+		Description("Close account: sweep balance to account with ID=2"),
+		Put(fmt.Sprintf("%s/admin/account/%d/close?reason=request&sweepTo=2", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"0","currency":"RUB","status":"closed"`),
+	)
+	Test(t,
+		Description("Closed account rejects credit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1", basePath, id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`account is closed`),
+	)
+	Test(t,
+		Description("Closed account can not be unfrozen"),
+		Put(fmt.Sprintf("%s/admin/account/%d/unfreeze?reason=request", basePath, id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`transition is not allowed`),
+	)
+}
+
+// HTTP PUT: /account/:id?idType=owner.
+func TestHttp_Owner(t *testing.T) {
+	owner := fmt.Sprintf("user-%d", time.Now().UnixNano())
+	Test(t,
+		Description("Redeem from unknown owner"),
+		Put(fmt.Sprintf("%s/account/%s?idType=owner&amount=-5", basePath, owner)),
+		Expect().Status().Equal(http.StatusNotFound),
+		Expect().Body().String().Contains(`account not found`),
+	)
+	Test(t,
+		Description("First accrual to unknown owner creates account"),
+		Put(fmt.Sprintf("%s/account/%s?idType=owner&amount=10", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(fmt.Sprintf(`"owner_id":"%s","balance":"10","currency":"RUB"`, owner)),
+	)
+	Test(t,
+		Description("Get account by owner ID"),
+		Get(fmt.Sprintf("%s/account/%s?idType=owner", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"10"`),
+	)
+	Test(t,
+		Description("Create account for existing owner"),
+		Post(fmt.Sprintf("%s/account?ownerId=%s", basePath, owner)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+	)
+	Test(t,
+		Description("Transfer to unknown owner creates account"),
+		Put(fmt.Sprintf("%s/account/amount/%s/transfer/%s-2?idType=owner&amount=4", basePath, owner, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"6"`),
+		Expect().Body().String().Contains(`"balance":"4"`),
+	)
+	Test(t,
+		Description("Transaction history by owner ID"),
+		Get(fmt.Sprintf("%s/account/history/%s?idType=owner&limit=5&offset=0", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"type":"redeem"`),
+	)
+}
+
+// HTTP PUT: /admin/account/:id/limit.
+func TestHttp_CreditLimit(t *testing.T) {
+	var id int64
+	Test(t,
+		Description("Create account with credit limit"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	Test(t,
+		Description("Redeem from account without credit limit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-10", basePath, id)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`not enough money, available amount is 0`),
+		Expect().Body().String().Contains(`"code":"not_enough_money","available":"0"`),
+	)
+	Test(t,
+		Description("Set credit limit: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/account/%d/limit?limit=100", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"credit_limit":"100"`),
+	)
+	Test(t,
+		Description("Redeem within credit limit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-60", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"-60"`),
+	)
+	Test(t,
+		Description("Redeem over credit limit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-50", basePath, id)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`credit limit exceeded, available amount is 40`),
+		Expect().Body().String().Contains(`"code":"credit_limit_exceeded","available":"40"`),
+	)
+	Test(t,
+		Description("Set credit limit less than debt"),
+		Put(fmt.Sprintf("%s/admin/account/%d/limit?limit=50", basePath, id)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`credit limit is less than account debt`),
+	)
+}
+
+// HTTP GET: /account/wallets/:ownerId.
+func TestHttp_Wallets(t *testing.T) {
+	owner := fmt.Sprintf("wallets-%d", time.Now().UnixNano())
+	Test(t,
+		Description("Accrual to main wallet of unknown owner"),
+		Put(fmt.Sprintf("%s/account/%s?idType=owner&amount=100", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"wallet":"main"`),
+	)
+	Test(t,
+		Description("Accrual to bonus wallet of the owner"),
+		Put(fmt.Sprintf("%s/account/%s?idType=owner&wallet=bonus&amount=15", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"wallet":"bonus"`),
+	)
+	Test(t,
+		Description("Unknown wallet"),
+		Put(fmt.Sprintf("%s/account/%s?idType=owner&wallet=savings&amount=15", basePath, owner)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`unknown wallet`),
+	)
+	Test(t,
+		Description("Transfer from main to bonus wallet"),
+		Put(fmt.Sprintf("%s/account/amount/%s/transfer/%s?idType=owner&accrWallet=bonus&amount=5", basePath, owner, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"20"`),
+		Expect().Body().String().Contains(`"balance":"95"`),
+	)
+	Test(t,
+		Description("All wallets of owner"),
+		Get(fmt.Sprintf("%s/account/wallets/%s", basePath, owner)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".data | length").Equal(2),
+	)
+}
+
+// HTTP GET: /accounts.
+func TestHttp_List(t *testing.T) {
+	var cursor string
+	Test(t,
+		Description("Search accounts: first page"),
+		Get(basePath+"/accounts?currency=RUB&sort=balance&isDecreasing=true&limit=1"),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".data.accounts | length").Equal(1),
+		Store().Response().Body().JSON().JQ(".data.next_cursor").In(&cursor),
+	)
+	Test(t,
+		Description("Search accounts: next page"),
+		Get(fmt.Sprintf("%s/accounts?currency=RUB&sort=balance&isDecreasing=true&limit=1&cursor=%s", basePath, cursor)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".data.accounts | length").Equal(1),
+	)
+	Test(t,
+		Description("Search accounts: malformed cursor"),
+		Get(basePath+"/accounts?sort=created_dt&cursor=not-a-cursor"),
+		Expect().Status().Equal(http.StatusBadRequest),
+		Expect().Body().String().Contains(`incorrect cursor`),
+	)
+	Test(t,
+		Description("Search accounts: cursor of another sort column"),
+		Get(fmt.Sprintf("%s/accounts?currency=RUB&sort=created_dt&cursor=%s", basePath, cursor)),
+		Expect().Status().Equal(http.StatusBadRequest),
+		Expect().Body().String().Contains(`incorrect cursor`),
+	)
+	Test(t,
+		Description("Search accounts: unknown sort column"),
+		Get(basePath+"/accounts?sort=reason"),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`unknown sort column`),
+	)
+	Test(t,
+		Description("Search accounts: incorrect balance"),
+		Get(basePath+"/accounts?minBalance=ten"),
+		Expect().Status().Equal(http.StatusBadRequest),
+	)
+}
+
+// HTTP PUT: /account/:id with Idempotency-Key header.
+func TestHttp_Idempotency(t *testing.T) {
+	key := fmt.Sprintf("idempotency-%d", time.Now().UnixNano())
+	var id int64
+	Test(t,
+		Description("Create account for idempotent requests"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	for i := 0; i < 2; i++ {
+		Test(t,
+			Description("Update balance with idempotency key, retry returns the original response"),
+			Put(fmt.Sprintf("%s/account/%d?amount=10", basePath, id)),
+			Send().Headers("Idempotency-Key").Add(key),
+			Expect().Status().Equal(http.StatusOK),
+			Expect().Body().String().Contains(`"balance":"10"`),
+		)
+	}
+	Test(t,
+		Description("Same idempotency key with another amount"),
+		Put(fmt.Sprintf("%s/account/%d?amount=20", basePath, id)),
+		Send().Headers("Idempotency-Key").Add(key),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`idempotency key is already used`),
+	)
+	Test(t,
+		Description("Balance is updated once"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"10"`),
+	)
+
+	var otherId int64
+	Test(t,
+		Description("Create another account for idempotent requests"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&otherId),
+	)
+	Test(t,
+		Description("Same idempotency key of another account is not a conflict"),
+		Put(fmt.Sprintf("%s/account/%d?amount=20", basePath, otherId)),
+		Send().Headers("Idempotency-Key").Add(key),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(fmt.Sprintf(`"id":%d`, otherId)),
+		Expect().Body().String().Contains(`"balance":"20"`),
+	)
+}
+
+func TestHttp_Hold(t *testing.T) {
+	var id, holdId int64
+	Test(t,
+		Description("Create account for holds"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	Test(t,
+		Description("Accrual to account for holds"),
+		Put(fmt.Sprintf("%s/account/%d?amount=100", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Create hold: case of correct work"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=order-1&amount=70", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"reserved":"70","available":"30"`),
+		Store().Response().Body().JSON().JQ(".data.hold.id").In(&holdId),
+	)
+	Test(t,
+		Description("Create hold for the same order"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=order-1&amount=10", basePath, id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`hold for the order already exists`),
+	)
+	Test(t,
+		Description("Redeem reserved money"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-50", basePath, id)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`not enough money, available amount is 30`),
+	)
+	Test(t,
+		Description("Capture hold partially"),
+		Put(fmt.Sprintf("%s/hold/%d/capture?amount=60", basePath, holdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"captured":"60"`),
+		Expect().Body().String().Contains(`"balance":"40"`),
+		Expect().Body().String().Contains(`"reserved":"0","available":"40"`),
+	)
+	Test(t,
+		Description("Release captured hold"),
+		Put(fmt.Sprintf("%s/hold/%d/release", basePath, holdId)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`hold is not active`),
+	)
+	Test(t,
+		Description("Create and release hold"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=order-2&amount=40", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.hold.id").In(&holdId),
+	)
+	Test(t,
+		Description("Release hold: case of correct work"),
+		Put(fmt.Sprintf("%s/hold/%d/release", basePath, holdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"released"`),
+		Expect().Body().String().Contains(`"reserved":"0","available":"40"`),
+	)
+	Test(t,
+		Description("Create hold with short TTL"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=order-3&amount=10&ttl=1s", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.hold.id").In(&holdId),
+	)
+	time.Sleep(2 * time.Second)
+	Test(t,
+		Description("Capture expired hold"),
+		Put(fmt.Sprintf("%s/hold/%d/capture", basePath, holdId)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`hold is expired`),
+	)
+
+	// Worker releases expired holds every expire interval.
+	var hold entity.Hold
+	for i := 0; i < 30 && hold.Status != entity.HoldExpired; i++ {
+		time.Sleep(time.Second)
+		Test(t,
+			Description("Get expired hold"),
+			Get(fmt.Sprintf("%s/hold/%d", basePath, holdId)),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data").In(&hold),
+		)
+	}
+	require.Equal(t, entity.HoldExpired, hold.Status)
+
+	var transactions []entity.Transaction
+	Test(t,
+		Description("History of account with holds has postings and memo of expired hold, its sum is balance"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=10&offset=0&sort=id", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+	sum := decimal.Zero
+	expired := 0
+	for _, trn := range transactions {
+		if trn.Type == "hold_expired" {
+			expired++
+			require.Nil(t, trn.JournalId)
+			require.True(t, trn.Amount.IsZero())
+			require.NotNil(t, trn.HoldId)
+			require.Equal(t, holdId, *trn.HoldId)
+		} else {
+			require.NotNil(t, trn.JournalId)
+		}
+		sum = sum.Add(trn.Amount)
+	}
+	require.Equal(t, 1, expired)
+	require.True(t, decimal.NewFromInt(40).Equal(sum))
+}
+
+func TestHttp_Reversal(t *testing.T) {
+	var redeemId, accrId, transId int64
+	var transactions *[]entity.Transaction
+	Test(t,
+		Description("Create redeem account for reversal"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&redeemId),
+	)
+	Test(t,
+		Description("Create accrual account for reversal"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&accrId),
+	)
+	Test(t,
+		Description("Accrual to redeem account"),
+		Put(fmt.Sprintf("%s/account/%d?amount=100", basePath, redeemId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Transfer to be reversed"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=40", basePath, redeemId, accrId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Find redeem leg of transfer"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=1&offset=0", basePath, redeemId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+
+	require.Equal(t, "redeem", (*transactions)[0].Type)
+	transId = (*transactions)[0].Id
+
+	Test(t,
+		Description("Reverse transfer partially"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse?amount=10", basePath, transId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(fmt.Sprintf(`"reversal_of":%d`, transId)),
+	)
+	Test(t,
+		Description("Accrual account after partial reversal"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, accrId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"30"`),
+	)
+	Test(t,
+		Description("Reverse more than remaining amount"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse?amount=50", basePath, transId)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`reversal amount is greater than remaining amount of transaction`),
+	)
+	Test(t,
+		Description("Reverse the rest of transfer"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, transId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Redeem account after full reversal"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, redeemId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"100"`),
+	)
+	Test(t,
+		Description("Reverse fully reversed transfer"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, transId)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`reversal amount is greater than remaining amount of transaction`),
+	)
+}
+
+func TestHttp_TransactionMeta(t *testing.T) {
+	var id int64
+	var transactions *[]entity.Transaction
+	Test(t,
+		Description("Create account for transaction meta"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	Test(t,
+		Description("Accrual with meta"),
+		Put(fmt.Sprintf("%s/account/%d?amount=100&description=Top%%20up%%20by%%20card&purpose=top_up&source=payments", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Redeem with meta"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-30&description=Order%%2015&purpose=order_payment&source=shop", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Redeem with incorrect purpose"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-30&purpose=Order%%20payment", basePath, id)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`incorrect purpose code`),
+	)
+	Test(t,
+		Description("History filtered by purpose"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=5&offset=0&purpose=order_payment", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+
+	require.Equal(t, 1, len(*transactions))
+	require.Equal(t, "Order 15", (*transactions)[0].Description)
+	require.Equal(t, "shop", (*transactions)[0].Source)
+
+	Test(t,
+		Description("History filtered by description"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=5&offset=0&description=card", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+
+	require.Equal(t, 1, len(*transactions))
+	require.Equal(t, "top_up", (*transactions)[0].Purpose)
+}
+
+func TestHttp_Ledger(t *testing.T) {
+	var redeemId, accrId int64
+	var redeemTransactions, accrTransactions *[]entity.Transaction
+	var balanced bool
+	Test(t,
+		Description("Create redeem account for ledger"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&redeemId),
+	)
+	Test(t,
+		Description("Create accrual account for ledger"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&accrId),
+	)
+	Test(t,
+		Description("Accrual from external system account"),
+		Put(fmt.Sprintf("%s/account/%d?amount=50", basePath, redeemId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Transfer between accounts"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=20", basePath, redeemId, accrId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Postings of redeem account"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=5&offset=0&sort=id&isDecreasing=false", basePath, redeemId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&redeemTransactions),
+	)
+	Test(t,
+		Description("Postings of accrual account"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=5&offset=0", basePath, accrId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&accrTransactions),
+	)
+
+	require.Equal(t, 2, len(*redeemTransactions))
+	require.Equal(t, int64(-101), (*redeemTransactions)[0].DocNum)
+	require.Equal(t, 1, len(*accrTransactions))
+	require.NotNil(t, (*accrTransactions)[0].JournalId)
+	require.Equal(t, *(*redeemTransactions)[1].JournalId, *(*accrTransactions)[0].JournalId)
+
+	Test(t,
+		Description("Write off through system account"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-5&system=write_off", basePath, accrId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"15"`),
+	)
+	Test(t,
+		Description("Exchange system account can not be chosen"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-5&system=exchange", basePath, accrId)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`unknown system account`),
+	)
+	Test(t,
+		Description("Chart of system accounts"),
+		Get(basePath+"/admin/ledger/accounts"),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`{"id":-501,"code":"write_off","currency":"RUB"`),
+	)
+	Test(t,
+		Description("Sum of all postings is zero"),
+		Get(basePath+"/admin/ledger/check"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.balanced").In(&balanced),
+	)
+
+	require.True(t, balanced)
+}
+
+func TestHttp_BatchTransfer(t *testing.T) {
+	var buyerId, sellerId, courierId, platformId int64
+	var accounts *[]entity.Account
+	var transactions *[]entity.Transaction
+	for _, id := range []*int64{&buyerId, &sellerId, &courierId, &platformId} {
+		Test(t,
+			Description("Create account for batch transfer"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+	Test(t,
+		Description("Accrual to buyer"),
+		Put(fmt.Sprintf("%s/account/%d?amount=100", basePath, buyerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Batch transfer: case of correct work"),
+		Post(basePath+"/transfers/batch"),
+		Send().Body().JSON(map[string]interface{}{"legs": []map[string]interface{}{
+			{"redeem_id": buyerId, "accrual_id": sellerId, "amount": "70", "purpose": "order_payment"},
+			{"redeem_id": buyerId, "accrual_id": courierId, "amount": "20", "purpose": "delivery"},
+			{"redeem_id": buyerId, "accrual_id": platformId, "amount": "5", "purpose": "commission"},
+		}}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&accounts),
+	)
+
+	require.Equal(t, 4, len(*accounts))
+	require.Equal(t, "5", (*accounts)[0].Balance.String())
+	require.Equal(t, "70", (*accounts)[1].Balance.String())
+	require.Equal(t, "20", (*accounts)[2].Balance.String())
+	require.Equal(t, "5", (*accounts)[3].Balance.String())
+
+	Test(t,
+		Description("Legs of batch transfer"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=3&offset=1&sort=id&isDecreasing=false", basePath, buyerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+
+	require.Equal(t, 3, len(*transactions))
+	for _, trn := range *transactions {
+		require.Equal(t, "redeem", trn.Type)
+		require.NotNil(t, trn.BatchId)
+		require.Equal(t, *(*transactions)[0].BatchId, *trn.BatchId)
+	}
+	require.NotEqual(t, *(*transactions)[0].JournalId, *(*transactions)[1].JournalId)
+	require.NotEqual(t, *(*transactions)[1].JournalId, *(*transactions)[2].JournalId)
+	require.Equal(t, sellerId, (*transactions)[0].DocNum)
+
+	Test(t,
+		Description("Reverse seller leg of batch transfer"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, (*transactions)[0].Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	for _, acc := range []struct {
+		id      int64
+		balance string
+	}{{buyerId, "75"}, {sellerId, "0"}, {courierId, "20"}, {platformId, "5"}} {
+		Test(t,
+			Description("Balance after reversal of one leg of batch transfer"),
+			Get(fmt.Sprintf("%s/account/%d", basePath, acc.id)),
+			Expect().Status().Equal(http.StatusOK),
+			Expect().Body().String().Contains(fmt.Sprintf(`"balance":"%s"`, acc.balance)),
+		)
+	}
+
+	Test(t,
+		Description("Batch transfer: not enough money for the last leg, no leg is made"),
+		Post(basePath+"/transfers/batch"),
+		Send().Body().JSON(map[string]interface{}{"legs": []map[string]interface{}{
+			{"redeem_id": buyerId, "accrual_id": sellerId, "amount": "5"},
+			{"redeem_id": buyerId, "accrual_id": courierId, "amount": "80"},
+		}}),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`not enough money`),
+	)
+	Test(t,
+		Description("Seller after failed batch transfer"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, sellerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"0"`),
+	)
+	Test(t,
+		Description("Batch transfer: batch has no legs"),
+		Post(basePath+"/transfers/batch"),
+		Send().Body().JSON(map[string]interface{}{"legs": []map[string]interface{}{}}),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`batch has no legs`),
+	)
+}
+
+func TestHttp_SplitTransfer(t *testing.T) {
+	var payerId, firstId, secondId, thirdId int64
+	for _, id := range []*int64{&payerId, &firstId, &secondId, &thirdId} {
+		Test(t,
+			Description("Create account for split transfer"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+	Test(t,
+		Description("Accrual to payer"),
+		Put(fmt.Sprintf("%s/account/%d?amount=200", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+
+	var split entity.SplitTransfer
+	Test(t,
+		Description("Split transfer by percents: case of correct work"),
+		Post(basePath+"/transfers/split"),
+		Send().Body().JSON(map[string]interface{}{"redeem_id": payerId, "amount": "100", "purpose": "order_payment", "shares": []map[string]interface{}{
+			{"account_id": firstId, "percent": "33.3333"},
+			{"account_id": secondId, "percent": "33.3333"},
+			{"account_id": thirdId, "percent": "33.3334"},
+		}}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&split),
+	)
+
+	require.Equal(t, "100", split.RedeemAccount.Balance.String())
+	require.Equal(t, 3, len(split.AccrualAccounts))
+	require.Equal(t, "33.33", split.AccrualAccounts[0].Balance.String())
+	require.Equal(t, "33.33", split.AccrualAccounts[1].Balance.String())
+	require.Equal(t, "33.34", split.AccrualAccounts[2].Balance.String())
+
+	Test(t,
+		Description("Split transfer by fixed amounts: case of correct work"),
+		Post(basePath+"/transfers/split"),
+		Send().Body().JSON(map[string]interface{}{"redeem_id": payerId, "shares": []map[string]interface{}{
+			{"account_id": firstId, "amount": "60"},
+			{"account_id": secondId, "amount": "40"},
+		}}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&split),
+	)
+
+	require.Equal(t, "0", split.RedeemAccount.Balance.String())
+	require.Equal(t, "93.33", split.AccrualAccounts[0].Balance.String())
+	require.Equal(t, "73.33", split.AccrualAccounts[1].Balance.String())
+
+	var transactions *[]entity.Transaction
+	Test(t,
+		Description("Shares of split transfer"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=2&offset=0", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+
+	require.Equal(t, 2, len(*transactions))
+	require.Equal(t, secondId, (*transactions)[0].DocNum)
+	require.Equal(t, firstId, (*transactions)[1].DocNum)
+	require.NotNil(t, (*transactions)[0].BatchId)
+	require.Equal(t, *(*transactions)[0].BatchId, *(*transactions)[1].BatchId)
+	require.NotEqual(t, *(*transactions)[0].JournalId, *(*transactions)[1].JournalId)
+
+	Test(t,
+		Description("Reverse one share of split transfer"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, (*transactions)[0].Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	for _, acc := range []struct {
+		id      int64
+		balance string
+	}{{payerId, "40"}, {firstId, "93.33"}, {secondId, "33.33"}, {thirdId, "33.34"}} {
+		Test(t,
+			Description("Balance after reversal of one share of split transfer"),
+			Get(fmt.Sprintf("%s/account/%d", basePath, acc.id)),
+			Expect().Status().Equal(http.StatusOK),
+			Expect().Body().String().Contains(fmt.Sprintf(`"balance":"%s"`, acc.balance)),
+		)
+	}
+
+	Test(t,
+		Description("Split transfer: not enough money, no share is paid"),
+		Post(basePath+"/transfers/split"),
+		Send().Body().JSON(map[string]interface{}{"redeem_id": firstId, "amount": "100", "shares": []map[string]interface{}{
+			{"account_id": secondId, "percent": "50"},
+			{"account_id": thirdId, "percent": "50"},
+		}}),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`not enough money`),
+	)
+	Test(t,
+		Description("Payee after failed split transfer"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, secondId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"33.33"`),
+	)
+	Test(t,
+		Description("Split transfer: percents do not add up to 100"),
+		Post(basePath+"/transfers/split"),
+		Send().Body().JSON(map[string]interface{}{"redeem_id": firstId, "amount": "10", "shares": []map[string]interface{}{
+			{"account_id": secondId, "percent": "50"},
+			{"account_id": thirdId, "percent": "40"},
+		}}),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`share percents do not add up to 100`),
+	)
+}
+
+func TestHttp_ScheduledTransfer(t *testing.T) {
+	var payerId, payeeId int64
+	for _, id := range []*int64{&payerId, &payeeId} {
+		Test(t,
+			Description("Create account for scheduled transfer"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+	Test(t,
+		Description("Accrual to payer"),
+		Put(fmt.Sprintf("%s/account/%d?amount=100", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+
+	runAt := time.Now().Add(2 * time.Second).UTC().Format(time.RFC3339)
+	var executed, failed, cancelled entity.ScheduledTransfer
+	Test(t,
+		Description("Create scheduled transfer: case of correct work"),
+		Post(fmt.Sprintf("%s/transfers/scheduled?redeemId=%d&accrId=%d&amount=30&runAt=%s&purpose=rent", basePath, payerId, payeeId, runAt)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"pending"`),
+		Store().Response().Body().JSON().JQ(".data").In(&executed),
+	)
+	Test(t,
+		Description("Create scheduled transfer: not enough money at run time"),
+		Post(fmt.Sprintf("%s/transfers/scheduled?redeemId=%d&accrId=%d&amount=500&runAt=%s", basePath, payerId, payeeId, runAt)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&failed),
+	)
+	Test(t,
+		Description("Create scheduled transfer: run time is in the past"),
+		Post(fmt.Sprintf("%s/transfers/scheduled?redeemId=%d&accrId=%d&amount=30&runAt=2020-01-01T00:00:00Z", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`run time is not in the future`),
+	)
+	Test(t,
+		Description("Create scheduled transfer to cancel"),
+		Post(fmt.Sprintf("%s/transfers/scheduled?redeemId=%d&accrId=%d&amount=10&runAt=%s", basePath, payerId, payeeId, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&cancelled),
+	)
+	Test(t,
+		Description("Update scheduled transfer: case of correct work"),
+		Put(fmt.Sprintf("%s/transfers/scheduled/%d?amount=15", basePath, cancelled.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"amount":"15"`),
+	)
+	Test(t,
+		Description("Cancel scheduled transfer: case of correct work"),
+		Put(fmt.Sprintf("%s/transfers/scheduled/%d/cancel", basePath, cancelled.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"cancelled"`),
+	)
+	Test(t,
+		Description("Cancel scheduled transfer: transfer is not pending"),
+		Put(fmt.Sprintf("%s/transfers/scheduled/%d/cancel", basePath, cancelled.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`scheduled transfer is not pending`),
+	)
+	Test(t,
+		Description("List cancelled scheduled transfers of payee"),
+		Get(fmt.Sprintf("%s/transfers/scheduled?accountId=%d&status=cancelled", basePath, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(fmt.Sprintf(`"id":%d`, cancelled.Id)),
+	)
+
+	// Worker makes due transfers every execute interval.
+	for i := 0; i < 30 && executed.Status == entity.ScheduledPending; i++ {
+		time.Sleep(time.Second)
+		Test(t,
+			Description("Get scheduled transfer"),
+			Get(fmt.Sprintf("%s/transfers/scheduled/%d", basePath, executed.Id)),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data").In(&executed),
+		)
+	}
+	require.Equal(t, entity.ScheduledExecuted, executed.Status)
+	require.NotNil(t, executed.JournalId)
+
+	Test(t,
+		Description("Scheduled transfer with failed attempt"),
+		Get(fmt.Sprintf("%s/transfers/scheduled/%d", basePath, failed.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"attempts":1`),
+		Expect().Body().String().Contains(`not enough money`),
+		Expect().Body().String().Contains(`"failure_code":"insufficient_funds"`),
+	)
+	Test(t,
+		Description("Payee after scheduled transfer"),
+		Get(fmt.Sprintf("%s/account/%d", basePath, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"30"`),
+	)
+}
+
+func TestHttp_RecurringPlan(t *testing.T) {
+	var userId, merchantId int64
+	for _, id := range []*int64{&userId, &merchantId} {
+		Test(t,
+			Description("Create account for recurring payment"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+
+	var plan entity.RecurringPlan
+	Test(t,
+		Description("Create recurring plan: case of correct work"),
+		Post(fmt.Sprintf("%s/recurring/?redeemId=%d&accrId=%d&amount=299&schedule=0+10+1+*+*&startAt=2030-01-15T00:00:00Z&maxCharges=12&purpose=subscription",
+			basePath, userId, merchantId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"active"`),
+		Expect().Body().String().Contains(`"next_charge_dt":"2030-02-01T10:00:00Z"`),
+		Store().Response().Body().JSON().JQ(".data").In(&plan),
+	)
+	Test(t,
+		Description("Create recurring plan: incorrect schedule"),
+		Post(fmt.Sprintf("%s/recurring/?redeemId=%d&accrId=%d&amount=299&schedule=monthly", basePath, userId, merchantId)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`incorrect schedule`),
+	)
+	Test(t,
+		Description("List active recurring plans of merchant"),
+		Get(fmt.Sprintf("%s/recurring/?accountId=%d&status=active", basePath, merchantId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(fmt.Sprintf(`"id":%d`, plan.Id)),
+	)
+	Test(t,
+		Description("Attempts of plan without charges"),
+		Get(fmt.Sprintf("%s/recurring/%d/attempts", basePath, plan.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().NotContains(`"status":"succeeded"`),
+	)
+	Test(t,
+		Description("Resume recurring plan: plan is not suspended"),
+		Put(fmt.Sprintf("%s/recurring/%d/resume", basePath, plan.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`recurring plan is not suspended`),
+	)
+	Test(t,
+		Description("Cancel recurring plan: case of correct work"),
+		Put(fmt.Sprintf("%s/recurring/%d/cancel", basePath, plan.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"cancelled"`),
+	)
+	Test(t,
+		Description("Cancel recurring plan: plan is cancelled"),
+		Put(fmt.Sprintf("%s/recurring/%d/cancel", basePath, plan.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`recurring plan is completed or cancelled`),
+	)
+}
+
+func TestHttp_Fee(t *testing.T) {
+	var payerId, payeeId int64
+	for _, id := range []*int64{&payerId, &payeeId} {
+		Test(t,
+			Description("Create account for fee"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+
+	Test(t,
+		Description("Top up account for fee"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1000", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Set account tier: unknown tier"),
+		Put(fmt.Sprintf("%s/admin/account/%d/tier?tier=gold", basePath, payerId)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`unknown account tier`),
+	)
+	Test(t,
+		Description("Set account tier: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/account/%d/tier?tier=business", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"tier":"business"`),
+	)
+	Test(t,
+		Description("Create fee rule: percent is out of range"),
+		Post(basePath+"/admin/fees/"),
+		Send().Body().JSON(map[string]interface{}{"operation": "transfer", "currency": "RUB", "tier": "business", "kind": "percent", "percent": "100"}),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`percent is out of range`),
+	)
+
+	var rule entity.FeeRule
+	Test(t,
+		Description("Create fee rule: case of correct work"),
+		Post(basePath+"/admin/fees/"),
+		Send().Body().JSON(map[string]interface{}{"operation": "transfer", "currency": "RUB", "tier": "business", "kind": "percent", "percent": "1", "min_fee": "5"}),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"active":true`),
+		Store().Response().Body().JSON().JQ(".data").In(&rule),
+	)
+
+	var redeemBalance string
+	Test(t,
+		Description("Transfer with fee: min fee is charged"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=100", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.redeemAccount.balance").In(&redeemBalance),
+	)
+	require.Equal(t, "895", redeemBalance)
+
+	var transactions []entity.Transaction
+	Test(t,
+		Description("Fee is own row of history with link to transfer"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=5&offset=0&sort=id", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+	require.Equal(t, 3, len(transactions))
+	require.Equal(t, "fee", transactions[2].Type)
+	require.True(t, decimal.NewFromInt(-5).Equal(transactions[2].Amount))
+	require.NotNil(t, transactions[2].FeeOf)
+	require.Equal(t, transactions[1].Id, *transactions[2].FeeOf)
+
+	Test(t,
+		Description("Disable fee rule: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/fees/%d/disable", basePath, rule.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"active":false`),
+	)
+	Test(t,
+		Description("Disable fee rule: rule is disabled"),
+		Put(fmt.Sprintf("%s/admin/fees/%d/disable", basePath, rule.Id)),
+		Expect().Status().Equal(http.StatusNotFound),
+		Expect().Body().String().Contains(`active fee rule not found`),
+	)
+	Test(t,
+		Description("Transfer without fee after rule is disabled"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=100", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.redeemAccount.balance").In(&redeemBalance),
+	)
+	require.Equal(t, "795", redeemBalance)
+
+	Test(t,
+		Description("Create fee rule of withdrawal"),
+		Post(basePath+"/admin/fees/"),
+		Send().Body().JSON(map[string]interface{}{"operation": "withdrawal", "currency": "RUB", "tier": "business", "kind": "flat", "flat": "3"}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&rule),
+	)
+
+	var holdId int64
+	Test(t,
+		Description("Create hold to capture with fee"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=fee-order-1&amount=100", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.hold.id").In(&holdId),
+	)
+	Test(t,
+		Description("Capture of hold is withdrawal and is charged with fee"),
+		Put(fmt.Sprintf("%s/hold/%d/capture", basePath, holdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"692"`),
+	)
+	Test(t,
+		Description("Disable fee rule of withdrawal"),
+		Put(fmt.Sprintf("%s/admin/fees/%d/disable", basePath, rule.Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+}
+
+func TestHttp_Campaign(t *testing.T) {
+	var id int64
+	Test(t,
+		Description("Create account for cashback"),
+		Post(basePath+"/account"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&id),
+	)
+	Test(t,
+		Description("Top up account for cashback"),
+		Put(fmt.Sprintf("%s/account/%d?amount=10000", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Create campaign: percent is zero"),
+		Post(basePath+"/admin/campaigns/"),
+		Send().Body().JSON(map[string]interface{}{"name": "Cashback", "purpose": "cashback_test", "currency": "RUB", "percent": "0", "budget": "60"}),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`percent is out of range`),
+	)
+
+	var campaign entity.Campaign
+	Test(t,
+		Description("Create campaign: case of correct work"),
+		Post(basePath+"/admin/campaigns/"),
+		Send().Body().JSON(map[string]interface{}{"name": "Cashback", "purpose": "cashback_test", "currency": "RUB", "percent": "5", "monthly_cap": "500", "budget": "60"}),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"active"`),
+		Store().Response().Body().JSON().JQ(".data").In(&campaign),
+	)
+
+	var balance string
+	Test(t,
+		Description("Redeem with cashback"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-200&purpose=cashback_test", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.balance").In(&balance),
+	)
+	require.Equal(t, "9810", balance)
+
+	Test(t,
+		Description("Redeem with cashback limited by budget left"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-2000&purpose=cashback_test", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.balance").In(&balance),
+	)
+	require.Equal(t, "7860", balance)
+
+	Test(t,
+		Description("Campaign is exhausted"),
+		Get(fmt.Sprintf("%s/admin/campaigns/%d", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"exhausted"`),
+		Expect().Body().String().Contains(`"spent":"60"`),
+	)
+	Test(t,
+		Description("Redeem without cashback of exhausted campaign"),
+		Put(fmt.Sprintf("%s/account/%d?amount=-100&purpose=cashback_test", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.balance").In(&balance),
+	)
+	require.Equal(t, "7760", balance)
+
+	Test(t,
+		Description("Cashback is own row of history with link to campaign"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=10&offset=0&sort=id&purpose=cashback_test", basePath, id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"type":"cashback"`),
+		Expect().Body().String().Contains(fmt.Sprintf(`"campaign_id":%d`, campaign.Id)),
+	)
+	Test(t,
+		Description("Add budget of campaign: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/campaigns/%d/budget?amount=100", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"active"`),
+	)
+	Test(t,
+		Description("Pause campaign: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/campaigns/%d/pause", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"paused"`),
+	)
+	Test(t,
+		Description("Pause campaign: campaign is paused"),
+		Put(fmt.Sprintf("%s/admin/campaigns/%d/pause", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`campaign is paused`),
+	)
+	Test(t,
+		Description("Resume campaign: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/campaigns/%d/resume", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"active"`),
+	)
+
+	var usdId, holdId int64
+	Test(t,
+		Description("Create account for cashback of hold capture"),
+		Post(basePath+"/account?currency=USD"),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.id").In(&usdId),
+	)
+	Test(t,
+		Description("Top up account for cashback of hold capture"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1000&currency=USD", basePath, usdId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Create campaign of any redeem"),
+		Post(basePath+"/admin/campaigns/"),
+		Send().Body().JSON(map[string]interface{}{"name": "Cashback of orders", "currency": "USD", "percent": "10", "budget": "100"}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&campaign),
+	)
+	Test(t,
+		Description("Create hold to capture with cashback"),
+		Post(fmt.Sprintf("%s/hold/?accountId=%d&orderId=cashback-order-1&amount=200", basePath, usdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.hold.id").In(&holdId),
+	)
+	Test(t,
+		Description("Capture of hold gets cashback"),
+		Put(fmt.Sprintf("%s/hold/%d/capture", basePath, holdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"balance":"820"`),
+	)
+	Test(t,
+		Description("Cashback is spent from budget of campaign"),
+		Get(fmt.Sprintf("%s/admin/campaigns/%d", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"spent":"20"`),
+	)
+
+	var transactions []entity.Transaction
+	Test(t,
+		Description("History of account with captured hold"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=10&offset=0&sort=id", basePath, usdId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+	require.Equal(t, 3, len(transactions))
+	require.Equal(t, "redeem", transactions[1].Type)
+
+	Test(t,
+		Description("Reverse capture of hold"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, transactions[1].Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Cashback taken back returns to budget of campaign"),
+		Get(fmt.Sprintf("%s/admin/campaigns/%d", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"spent":"0"`),
+	)
+	Test(t,
+		Description("Pause campaign of any redeem"),
+		Put(fmt.Sprintf("%s/admin/campaigns/%d/pause", basePath, campaign.Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+}
+
+func TestHttp_Escrow(t *testing.T) {
+	var buyerId, sellerId int64
+	for _, id := range []*int64{&buyerId, &sellerId} {
+		Test(t,
+			Description("Create account for safe deal"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+	Test(t,
+		Description("Accrual to buyer"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1000", basePath, buyerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+
+	var deal entity.Escrow
+	Test(t,
+		Description("Fund safe deal: case of correct work"),
+		Post(fmt.Sprintf("%s/escrows/?buyerId=%d&sellerId=%d&orderId=deal-1&amount=600&ttl=72h&purpose=order_payment", basePath, buyerId, sellerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"funded"`),
+		Expect().Body().String().Contains(`"balance":"400"`),
+		Store().Response().Body().JSON().JQ(".data.escrow").In(&deal),
+	)
+	Test(t,
+		Description("Fund safe deal: deal for the order already exists"),
+		Post(fmt.Sprintf("%s/escrows/?buyerId=%d&sellerId=%d&orderId=deal-1&amount=100", basePath, buyerId, sellerId)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`safe deal for the order already exists`),
+	)
+	Test(t,
+		Description("Get safe deal: deal not found"),
+		Get(fmt.Sprintf("%s/escrows/%d", basePath, 999999999)),
+		Expect().Status().Equal(http.StatusNotFound),
+		Expect().Body().String().Contains(`safe deal not found`),
+	)
+	Test(t,
+		Description("Fund safe deal: not enough money"),
+		Post(fmt.Sprintf("%s/escrows/?buyerId=%d&sellerId=%d&orderId=deal-2&amount=500", basePath, buyerId, sellerId)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`not enough money`),
+	)
+	Test(t,
+		Description("Dispute safe deal: reason is empty"),
+		Put(fmt.Sprintf("%s/escrows/%d/dispute", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`reason is empty`),
+	)
+	Test(t,
+		Description("Dispute safe deal: case of correct work"),
+		Put(fmt.Sprintf("%s/escrows/%d/dispute?reason=item_is_broken", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"disputed"`),
+	)
+	Test(t,
+		Description("Release disputed safe deal: case of correct work"),
+		Put(fmt.Sprintf("%s/escrows/%d/release?reason=resolved", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"released"`),
+		Expect().Body().String().Contains(`"balance":"600"`),
+	)
+	Test(t,
+		Description("Refund safe deal: deal is released"),
+		Put(fmt.Sprintf("%s/escrows/%d/refund", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`safe deal status transition is not allowed`),
+	)
+
+	var changes []entity.EscrowChange
+	Test(t,
+		Description("Safe deal history"),
+		Get(fmt.Sprintf("%s/escrows/%d/history", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&changes),
+	)
+
+	require.Equal(t, 3, len(changes))
+	require.Nil(t, changes[0].FromStatus)
+	require.Equal(t, entity.EscrowFunded, changes[0].ToStatus)
+	require.Equal(t, "item_is_broken", changes[1].Reason)
+	require.Nil(t, changes[1].JournalId)
+	require.Equal(t, entity.EscrowReleased, changes[2].ToStatus)
+	require.NotNil(t, changes[2].JournalId)
+
+	var trns []*entity.Transaction
+	Test(t,
+		Description("History of buyer with funding of safe deal"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=10&offset=0&sort=id", basePath, buyerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&trns),
+	)
+
+	var fundId int64
+	for _, trn := range trns {
+		if trn.EscrowId != nil {
+			fundId = trn.Id
+		}
+	}
+	require.NotZero(t, fundId)
+
+	Test(t,
+		Description("Reverse transaction: money of safe deal is moved by its status only"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, fundId)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`transaction can not be reversed`),
+	)
+
+	Test(t,
+		Description("Fund safe deal which expires soon"),
+		Post(fmt.Sprintf("%s/escrows/?buyerId=%d&sellerId=%d&orderId=deal-3&amount=100&ttl=1s", basePath, buyerId, sellerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.escrow").In(&deal),
+	)
+
+	time.Sleep(2 * time.Second)
+
+	Test(t,
+		Description("Release safe deal: deal is expired"),
+		Put(fmt.Sprintf("%s/escrows/%d/release", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusConflict),
+		Expect().Body().String().Contains(`safe deal is expired`),
+	)
+	Test(t,
+		Description("Refund expired safe deal: case of correct work"),
+		Put(fmt.Sprintf("%s/escrows/%d/refund?reason=no_delivery", basePath, deal.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"status":"refunded"`),
+		Expect().Body().String().Contains(`"balance":"400"`),
+	)
+}
+
+func TestHttp_VelocityLimit(t *testing.T) {
+	var payerId, payeeId int64
+	for _, id := range []*int64{&payerId, &payeeId} {
+		Test(t,
+			Description("Create account for velocity limit"),
+			Post(basePath+"/account"),
+			Expect().Status().Equal(http.StatusOK),
+			Store().Response().Body().JSON().JQ(".data.id").In(id),
+		)
+	}
+
+	Test(t,
+		Description("Top up account for velocity limit"),
+		Put(fmt.Sprintf("%s/account/%d?amount=1000", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Create velocity limit: neither max amount nor max count"),
+		Post(basePath+"/admin/limits/"),
+		Send().Body().JSON(map[string]interface{}{"account_id": payerId, "currency": "RUB", "period": "day"}),
+		Expect().Status().Equal(http.StatusInternalServerError),
+		Expect().Body().String().Contains(`velocity limit has neither max amount nor max number of debits`),
+	)
+	Test(t,
+		Description("Create velocity limit: case of correct work"),
+		Post(basePath+"/admin/limits/"),
+		Send().Body().JSON(map[string]interface{}{"account_id": payerId, "currency": "RUB", "period": "day", "max_amount": "1000", "max_count": 2}),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"active":true`),
+	)
+
+	for _, amount := range []int{200, 150} {
+		Test(t,
+			Description("Transfer within velocity limit"),
+			Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=%d", basePath, payerId, payeeId, amount)),
+			Expect().Status().Equal(http.StatusOK),
+		)
+	}
+
+	Test(t,
+		Description("Transfer over velocity limit: number of debits"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=1", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`"period":"day","kind":"count","max":"2","remaining":"0"`),
+	)
+
+	var limit entity.VelocityLimit
+	Test(t,
+		Description("Create velocity limit: limit of the same period is replaced"),
+		Post(basePath+"/admin/limits/"),
+		Send().Body().JSON(map[string]interface{}{"account_id": payerId, "currency": "RUB", "period": "day", "max_amount": "400"}),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&limit),
+	)
+
+	var limits []entity.VelocityLimit
+	Test(t,
+		Description("List velocity limits of account"),
+		Get(fmt.Sprintf("%s/admin/limits/?accountId=%d", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&limits),
+	)
+	require.Equal(t, 1, len(limits))
+	require.Equal(t, limit.Id, limits[0].Id)
+
+	Test(t,
+		Description("Transfer over velocity limit: total amount"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=100", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusUnprocessableEntity),
+		Expect().Body().String().Contains(`"period":"day","kind":"amount","max":"400","remaining":"50"`),
+	)
+
+	var transactions []entity.Transaction
+	Test(t,
+		Description("History of account with velocity limit"),
+		Get(fmt.Sprintf("%s/account/history/%d?limit=10&offset=0&sort=id", basePath, payerId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data").In(&transactions),
+	)
+	require.Equal(t, 3, len(transactions))
+	require.Equal(t, "redeem", transactions[2].Type)
+
+	Test(t,
+		Description("Reverse debit counted by velocity limit"),
+		Post(fmt.Sprintf("%s/transactions/%d/reverse", basePath, transactions[2].Id)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Transfer within velocity limit after debit is reversed"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=100", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+	)
+	Test(t,
+		Description("Disable velocity limit: case of correct work"),
+		Put(fmt.Sprintf("%s/admin/limits/%d/disable", basePath, limit.Id)),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().String().Contains(`"active":false`),
+	)
+
+	var redeemBalance string
+	Test(t,
+		Description("Transfer after velocity limit is disabled"),
+		Put(fmt.Sprintf("%s/account/amount/%d/transfer/%d?amount=100", basePath, payerId, payeeId)),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".data.redeemAccount.balance").In(&redeemBalance),
+	)
+	require.Equal(t, "600", redeemBalance)
+}
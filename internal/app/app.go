@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +10,7 @@ import (
 	v1 "github.com/cut4cut/avito-test-work/internal/controller/http/v1"
 	"github.com/cut4cut/avito-test-work/internal/usecase"
 	"github.com/cut4cut/avito-test-work/internal/usecase/repo"
+	"github.com/cut4cut/avito-test-work/internal/usecase/webapi"
 	"github.com/cut4cut/avito-test-work/pkg/logger"
 	"github.com/cut4cut/avito-test-work/pkg/postgres"
 )
@@ -24,13 +26,38 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
+	// Exchange rates
+	rateRepo := repo.NewRateRepo(pg)
+
+	var rates usecase.RateProvider
+	switch cfg.FX.Provider {
+	case "db":
+		rates = rateRepo
+	case "file":
+		rates = usecase.NewRateHistory(usecase.NewRateCache(repo.NewRateFile(cfg.FX.File), cfg.FX.CacheTTL), rateRepo)
+	case "http":
+		rates = usecase.NewRateHistory(usecase.NewRateCache(webapi.NewRateWebAPI(cfg.FX.URL, cfg.FX.Timeout), cfg.FX.CacheTTL), rateRepo)
+	default:
+		l.Fatal(fmt.Errorf("app - Run - unknown rates provider: %s", cfg.FX.Provider))
+	}
+
 	// Use case
 	r := repo.New(pg)
-	accountUseCase := usecase.New(r)
+	accountUseCase := usecase.New(r, rates)
+	rateUseCase := usecase.NewRate(rateRepo)
+
+	// Hold expiry, scheduled transfers, recurring payments and refund of expired safe deals
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go expireHolds(ctx, accountUseCase, l, cfg.Hold.ExpireInterval, cfg.Hold.ExpireBatch)
+	go executeScheduledTransfers(ctx, accountUseCase, l, cfg.Schedule)
+	go chargeRecurringPlans(ctx, accountUseCase, l, cfg.Recurring)
+	go refundExpiredEscrows(ctx, accountUseCase, l, cfg.Escrow)
 
 	// HTTP Server
 	handler := gin.Default()
-	v1.NewRouter(handler, l, *accountUseCase)
+	v1.NewRouter(handler, l, *accountUseCase, *rateUseCase)
 
 	handler.Run()
 }
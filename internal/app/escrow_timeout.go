@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cut4cut/avito-test-work/config"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+// refundExpiredEscrows - refund expired safe deals every interval until context is done.
+func refundExpiredEscrows(ctx context.Context, uc *usecase.AccountUseCase, l logger.Interface, cfg config.Escrow) {
+	runEvery(ctx, l, cfg.RefundInterval, "refundExpiredEscrows", func(ctx context.Context) error {
+		count, err := uc.RefundExpiredEscrows(ctx, cfg.RefundBatch)
+		if count > 0 {
+			l.Info("app - refundExpiredEscrows - refunded %d expired safe deals", count)
+		}
+
+		if err != nil {
+			return fmt.Errorf("uc.RefundExpiredEscrows: %w", err)
+		}
+
+		return nil
+	})
+}
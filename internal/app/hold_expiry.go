@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+// expireHolds - release expired holds every interval until context is done.
+func expireHolds(ctx context.Context, uc *usecase.AccountUseCase, l logger.Interface, interval time.Duration, batchSize uint64) {
+	runEvery(ctx, l, interval, "expireHolds", func(ctx context.Context) error {
+		count, err := uc.ExpireHolds(ctx, batchSize)
+		if count > 0 {
+			l.Info("app - expireHolds - released %d expired holds", count)
+		}
+
+		if err != nil {
+			return fmt.Errorf("uc.ExpireHolds: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cut4cut/avito-test-work/config"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+// chargeRecurringPlans - make due charges of recurring payment plans every interval until context is done.
+// Charge has idempotency key and its attempt is recorded once, so concurrent workers do not charge twice.
+func chargeRecurringPlans(ctx context.Context, uc *usecase.AccountUseCase, l logger.Interface, cfg config.Recurring) {
+	runEvery(ctx, l, cfg.ChargeInterval, "chargeRecurringPlans", func(ctx context.Context) error {
+		count, err := uc.ChargeRecurringPlans(ctx, cfg.ChargeBatch, cfg.DunningRetries, cfg.DunningInterval)
+		if count > 0 {
+			l.Info("app - chargeRecurringPlans - made %d recurring charges", count)
+		}
+
+		if err != nil {
+			return fmt.Errorf("uc.ChargeRecurringPlans: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cut4cut/avito-test-work/config"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+// executeScheduledTransfers - make due scheduled transfers every interval until context is done.
+func executeScheduledTransfers(ctx context.Context, uc *usecase.AccountUseCase, l logger.Interface, cfg config.Schedule) {
+	runEvery(ctx, l, cfg.ExecuteInterval, "executeScheduledTransfers", func(ctx context.Context) error {
+		count, err := uc.ExecuteScheduledTransfers(ctx, cfg.ExecuteBatch, cfg.MaxAttempts, cfg.RetryInterval)
+		if count > 0 {
+			l.Info("app - executeScheduledTransfers - executed %d scheduled transfers", count)
+		}
+
+		if err != nil {
+			return fmt.Errorf("uc.ExecuteScheduledTransfers: %w", err)
+		}
+
+		return nil
+	})
+}
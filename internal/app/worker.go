@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+// runEvery - run work of background worker every interval until context is done, error of run is logged
+// and the worker waits for the next run. Workers lock rows which they process, so they are run
+// in each instance of service.
+func runEvery(ctx context.Context, l logger.Interface, interval time.Duration, name string, work func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := work(ctx); err != nil {
+				l.Error(fmt.Errorf("app - %s - %w", name, err))
+			}
+		}
+	}
+}
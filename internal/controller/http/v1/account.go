@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 
 	"github.com/cut4cut/avito-test-work/internal/entity"
 	"github.com/cut4cut/avito-test-work/internal/usecase"
@@ -21,9 +23,12 @@ type accountRoutes struct {
 func newAccountRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
 	r := &accountRoutes{u, l}
 
+	handler.GET("/accounts", r.list)
+
 	h := handler.Group("/account")
 	{
 		h.GET("/history/:id", r.getHistory)
+		h.GET("/wallets/:ownerId", r.getWallets)
 		h.POST("/", r.create)
 		h.GET("/:id", r.getById)
 		h.PUT("/:id", r.updBalance)
@@ -35,22 +40,74 @@ type correctResponse struct {
 	Data interface{} `json:"data"`
 }
 
+type convertedAccount struct {
+	entity.Account
+	Conversion entity.Conversion `json:"conversion"`
+}
+
 type transferAccountPair struct {
 	AccrAcc   entity.Account `json:"accrualAccount"`
 	RedeemAcc entity.Account `json:"redeemAccount"`
 }
 
+// accountRef - read reference to account from path parameter, it is external owner ID when idType is owner,
+// then wallet is read from walletParam query parameter, main wallet by default.
+func accountRef(c *gin.Context, param, walletParam string) (ref entity.AccountRef, err error) {
+	if c.Request.URL.Query().Get("idType") == "owner" {
+		ref.OwnerId = c.Param(param)
+		ref.Wallet = entity.WalletMain
+
+		if wallet := c.Request.URL.Query().Get(walletParam); wallet != "" {
+			ref.Wallet = entity.Wallet(strings.ToLower(wallet))
+		}
+
+		return
+	}
+
+	ref.Id, err = strconv.ParseInt(c.Param(param), 10, 64)
+
+	return
+}
+
+// transactionMeta - read description, purpose code and source service of money operation from query.
+func transactionMeta(c *gin.Context) entity.TransactionMeta {
+	return entity.TransactionMeta{
+		Description: c.Request.URL.Query().Get("description"),
+		Purpose:     c.Request.URL.Query().Get("purpose"),
+		Source:      c.Request.URL.Query().Get("source"),
+	}
+}
+
+// parseNullDecimal - parse optional decimal query parameter, empty value is null.
+func parseNullDecimal(value string) (d decimal.NullDecimal, err error) {
+	if value == "" {
+		return
+	}
+
+	d.Decimal, err = decimal.NewFromString(value)
+	d.Valid = err == nil
+
+	return
+}
+
 // @Summary     Create new account
 // @Description Create a new account with default fields and return in the response
 // @ID          create
 // @Tags  	    account
 // @Accept      json
 // @Produce     json
+// @Param       currency    query     string  false  "ISO 4217 currency code, RUB by default"
+// @Param       ownerId    query     string  false  "External owner ID"
+// @Param       wallet    query     string  false  "Wallet of owner: main (by default), bonus or escrow"
 // @Success     200 {object} correctResponse
 // @Failure     500 {object} response
 // @Router      /account [post]
 func (r *accountRoutes) create(c *gin.Context) {
-	account, err := r.u.Create(c.Request.Context())
+	currency := c.Request.URL.Query().Get("currency")
+	ownerId := c.Request.URL.Query().Get("ownerId")
+	wallet := c.Request.URL.Query().Get("wallet")
+
+	account, err := r.u.Create(c.Request.Context(), currency, ownerId, wallet)
 	if err != nil {
 		r.l.Error(err, "http - v1 - create")
 		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
@@ -63,17 +120,20 @@ func (r *accountRoutes) create(c *gin.Context) {
 }
 
 // @Summary     Get account by ID
-// @Description Returns account fields by ID in the response
+// @Description Returns account fields by ID in the response, the balance is converted when currency is passed
 // @ID          getById
 // @Tags  	    account
 // @Accept      json
 // @Produce     json
-// @Param       id   path      int  true  "Account ID"
+// @Param       id   path      string  true  "Account ID or external owner ID"
+// @Param       idType    query     string  false  "Type of ID in path: owner for external owner ID"
+// @Param       wallet    query     string  false  "Wallet of owner: main (by default), bonus or escrow"
+// @Param       currency    query     string  false  "ISO 4217 currency code to convert balance"
 // @Success     200 {object} correctResponse
 // @Failure     500 {object} response
 // @Router      /account/{id} [get]
 func (r *accountRoutes) getById(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	ref, err := accountRef(c, "id", "wallet")
 	if err != nil {
 		r.l.Error(err, "http - v1 - getById")
 		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
@@ -81,7 +141,22 @@ func (r *accountRoutes) getById(c *gin.Context) {
 		return
 	}
 
-	account, err := r.u.GetById(c.Request.Context(), id)
+	if currency := c.Request.URL.Query().Get("currency"); currency != "" {
+		account, conversion, err := r.u.ConvertBalance(c.Request.Context(), ref, currency)
+		if err != nil {
+			r.l.Error(err, "http - v1 - getById")
+			errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+			errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, correctResponse{convertedAccount{account, conversion}})
+
+		return
+	}
+
+	account, err := r.u.GetById(c.Request.Context(), ref)
 	if err != nil {
 		r.l.Error(err, "http - v1 - getById")
 		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
@@ -94,18 +169,28 @@ func (r *accountRoutes) getById(c *gin.Context) {
 }
 
 // @Summary     Update balance
-// @Description Changing the account balance to the amount passed in the parameter
+// @Description Changing the account balance to the amount passed in the parameter, the first accrual to unknown owner creates the account
 // @ID          updBalance
 // @Tags  	    account
 // @Accept      json
 // @Produce     json
-// @Param       id   path      int  true  "Account ID"
+// @Param       id   path      string  true  "Account ID or external owner ID"
+// @Param       idType    query     string  false  "Type of ID in path: owner for external owner ID"
+// @Param       wallet    query     string  false  "Wallet of owner: main (by default), bonus or escrow"
 // @Param       amount    query     number  true  "The value by which the balance changes"
+// @Param       currency    query     string  false  "ISO 4217 currency code of the amount, must match the account"
+// @Param       system    query     string  false  "System account on the other side: cash_in (by default for accrual), cash_out (by default for redeem), fees, suspense or write_off"
+// @Param       description    query     string  false  "Description of operation"
+// @Param       purpose    query     string  false  "Purpose code of operation, e.g. order_payment"
+// @Param       source    query     string  false  "Name of service which makes the operation"
+// @Param       Idempotency-Key    header     string  false  "Key of request, retry with the same key returns the original response"
 // @Success     200 {object} correctResponse
+// @Failure     409 {object} response
+// @Failure     422 {object} velocityLimitResponse
 // @Failure     500 {object} response
 // @Router      /account/{id} [put]
 func (r *accountRoutes) updBalance(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	ref, err := accountRef(c, "id", "wallet")
 	if err != nil {
 		r.l.Error(err, "http - v1 - updBalance")
 		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
@@ -113,7 +198,7 @@ func (r *accountRoutes) updBalance(c *gin.Context) {
 		return
 	}
 
-	amount, err := strconv.ParseFloat(c.Request.URL.Query().Get("amount"), 10)
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
 	if err != nil {
 		r.l.Error(err, "http - v1 - updBalance")
 		errorResponse(c, http.StatusBadRequest, "incorrect amount")
@@ -121,11 +206,14 @@ func (r *accountRoutes) updBalance(c *gin.Context) {
 		return
 	}
 
-	account, err := r.u.UpdBalance(c.Request.Context(), id, amount)
+	currency := c.Request.URL.Query().Get("currency")
+	system := c.Request.URL.Query().Get("system")
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+
+	account, err := r.u.UpdBalance(c.Request.Context(), ref, amount, currency, system, transactionMeta(c), idempotencyKey)
 	if err != nil {
 		r.l.Error(err, "http - v1 - updBalance")
-		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
-		errorResponse(c, http.StatusInternalServerError, errorMassage)
+		operationErrorResponse(c, err)
 
 		return
 	}
@@ -134,20 +222,29 @@ func (r *accountRoutes) updBalance(c *gin.Context) {
 }
 
 // @Summary     Money transaction
-// @Description Transferring amount of money between accounts
+// @Description Transferring amount of money between accounts, accrual account of unknown owner is created
 // @ID          transferAmount
 // @Tags  	    account
 // @Accept      json
 // @Produce     json
-// @Param       redeemId   path      int  true  "Account ID for redeem funds"
-// @Param       accrId   path      int  true  "Account ID for accrual funds"
+// @Param       redeemId   path      string  true  "Account ID or external owner ID for redeem funds"
+// @Param       accrId   path      string  true  "Account ID or external owner ID for accrual funds"
+// @Param       idType    query     string  false  "Type of IDs in path: owner for external owner ID"
+// @Param       redeemWallet    query     string  false  "Wallet of redeem owner, main by default"
+// @Param       accrWallet    query     string  false  "Wallet of accrual owner, main by default"
 // @Param       amount    query     number  true  "Amount of money to transfer"
+// @Param       convert    query     bool  false  "Allow transfer between accounts in different currencies"
+// @Param       description    query     string  false  "Description of operation"
+// @Param       purpose    query     string  false  "Purpose code of operation, e.g. order_payment"
+// @Param       source    query     string  false  "Name of service which makes the operation"
+// @Param       Idempotency-Key    header     string  false  "Key of request, retry with the same key returns the original response"
 // @Success     200 {object} transferAccountPair
+// @Failure     409 {object} response
+// @Failure     422 {object} velocityLimitResponse
 // @Failure     500 {object} response
 // @Router      /account/amount/{redeemId}/transfer/{accrId} [put]
 func (r *accountRoutes) transferAmount(c *gin.Context) {
-	// redeemId, accrId int64, amount
-	redeemId, err := strconv.ParseInt(c.Param("redeemId"), 10, 64)
+	redeemRef, err := accountRef(c, "redeemId", "redeemWallet")
 	if err != nil {
 		r.l.Error(err, "http - v1 - transferAmount")
 		errorResponse(c, http.StatusBadRequest, "incorrect redeem's ID")
@@ -155,7 +252,7 @@ func (r *accountRoutes) transferAmount(c *gin.Context) {
 		return
 	}
 
-	accrId, err := strconv.ParseInt(c.Param("accrId"), 10, 64)
+	accrRef, err := accountRef(c, "accrId", "accrWallet")
 	if err != nil {
 		r.l.Error(err, "http - v1 - transferAmount")
 		errorResponse(c, http.StatusBadRequest, "incorrect accrual's ID")
@@ -163,7 +260,7 @@ func (r *accountRoutes) transferAmount(c *gin.Context) {
 		return
 	}
 
-	amount, err := strconv.ParseFloat(c.Request.URL.Query().Get("amount"), 10)
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
 	if err != nil {
 		r.l.Error(err, "http - v1 - transferAmount")
 		errorResponse(c, http.StatusBadRequest, "incorrect amount")
@@ -171,11 +268,13 @@ func (r *accountRoutes) transferAmount(c *gin.Context) {
 		return
 	}
 
-	accrAcc, redeemAcc, err := r.u.TransferAmount(c.Request.Context(), redeemId, accrId, amount)
+	convert := c.Request.URL.Query().Get("convert")
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+
+	accrAcc, redeemAcc, err := r.u.TransferAmount(c.Request.Context(), redeemRef, accrRef, amount, convert, transactionMeta(c), idempotencyKey)
 	if err != nil {
 		r.l.Error(err, "http - v1 - transferAmount")
-		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
-		errorResponse(c, http.StatusInternalServerError, errorMassage)
+		operationErrorResponse(c, err)
 
 		return
 	}
@@ -191,16 +290,21 @@ func (r *accountRoutes) transferAmount(c *gin.Context) {
 // @Tags  	    account
 // @Accept      json
 // @Produce     json
-// @Param       id   path      int  true  "Account ID"
+// @Param       id   path      string  true  "Account ID or external owner ID"
+// @Param       idType    query     string  false  "Type of ID in path: owner for external owner ID"
+// @Param       wallet    query     string  false  "Wallet of owner: main (by default), bonus or escrow"
 // @Param       limit    query     int  true  "The value of limit in pagination"
 // @Param       offset    query     int  true  "The value of offset in pagination"
 // @Param       sort    query     string  false  "Column name to sort"
 // @Param       isDecreasing    query     bool  false  "Descending sort flag"
+// @Param       description    query     string  false  "Substring of description"
+// @Param       purpose    query     string  false  "Purpose code"
+// @Param       source    query     string  false  "Name of service which made the operation"
 // @Success     200 {object} correctResponse
 // @Failure     500 {object} response
 // @Router      /account/history/{id} [get]
 func (r *accountRoutes) getHistory(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	ref, err := accountRef(c, "id", "wallet")
 	if err != nil {
 		r.l.Error(err, "http - v1 - getHistory")
 		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
@@ -227,7 +331,13 @@ func (r *accountRoutes) getHistory(c *gin.Context) {
 	isDecreasing := c.Request.URL.Query().Get("isDecreasing")
 	sort := c.Request.URL.Query().Get("sort")
 
-	transactions, err := r.u.GetHistory(c.Request.Context(), id, limit, offset, sort, isDecreasing)
+	filter := entity.TransactionFilter{
+		Description: c.Request.URL.Query().Get("description"),
+		Purpose:     c.Request.URL.Query().Get("purpose"),
+		Source:      c.Request.URL.Query().Get("source"),
+	}
+
+	transactions, err := r.u.GetHistory(c.Request.Context(), ref, limit, offset, sort, isDecreasing, filter)
 	if err != nil {
 		r.l.Error(err, "http - v1 - history")
 		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
@@ -238,3 +348,114 @@ func (r *accountRoutes) getHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, correctResponse{transactions})
 }
+
+// @Summary     Wallets of owner
+// @Description Return all wallets of owner, balances are read at the same moment
+// @ID          wallets
+// @Tags  	    account
+// @Accept      json
+// @Produce     json
+// @Param       ownerId   path      string  true  "External owner ID"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /account/wallets/{ownerId} [get]
+func (r *accountRoutes) getWallets(c *gin.Context) {
+	accounts, err := r.u.GetWallets(c.Request.Context(), c.Param("ownerId"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - wallets")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{accounts})
+}
+
+// @Summary     Search accounts
+// @Description Return page of accounts found by filters with total count, next page is requested with cursor of previous page
+// @ID          list
+// @Tags  	    account
+// @Accept      json
+// @Produce     json
+// @Param       minBalance    query     number  false  "Min balance"
+// @Param       maxBalance    query     number  false  "Max balance"
+// @Param       createdFrom    query     string  false  "Start of creation date range in RFC3339"
+// @Param       createdTo    query     string  false  "End of creation date range in RFC3339, not included"
+// @Param       status    query     string  false  "Account status"
+// @Param       ownerId    query     string  false  "External owner ID"
+// @Param       currency    query     string  false  "ISO 4217 currency code"
+// @Param       sort    query     string  false  "Column to sort: id (by default), balance or created_dt"
+// @Param       isDecreasing    query     bool  false  "Descending sort flag"
+// @Param       limit    query     int  false  "Page size, 50 by default"
+// @Param       cursor    query     string  false  "Cursor of next page from previous page"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /accounts [get]
+func (r *accountRoutes) list(c *gin.Context) {
+	query := c.Request.URL.Query()
+
+	f := entity.AccountFilter{
+		Status:       entity.AccountStatus(query.Get("status")),
+		OwnerId:      query.Get("ownerId"),
+		Currency:     query.Get("currency"),
+		Sort:         query.Get("sort"),
+		IsDecreasing: strings.ToLower(query.Get("isDecreasing")) == "true",
+	}
+
+	var err error
+	if f.MinBalance, err = parseNullDecimal(query.Get("minBalance")); err != nil {
+		r.l.Error(err, "http - v1 - list")
+		errorResponse(c, http.StatusBadRequest, "incorrect minBalance value")
+
+		return
+	}
+
+	if f.MaxBalance, err = parseNullDecimal(query.Get("maxBalance")); err != nil {
+		r.l.Error(err, "http - v1 - list")
+		errorResponse(c, http.StatusBadRequest, "incorrect maxBalance value")
+
+		return
+	}
+
+	if f.CreatedFrom, err = parseTime(query.Get("createdFrom")); err != nil {
+		r.l.Error(err, "http - v1 - list")
+		errorResponse(c, http.StatusBadRequest, "incorrect createdFrom value")
+
+		return
+	}
+
+	if f.CreatedTo, err = parseTime(query.Get("createdTo")); err != nil {
+		r.l.Error(err, "http - v1 - list")
+		errorResponse(c, http.StatusBadRequest, "incorrect createdTo value")
+
+		return
+	}
+
+	if value := query.Get("limit"); value != "" {
+		f.Limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - list")
+			errorResponse(c, http.StatusBadRequest, "incorrect limit value")
+
+			return
+		}
+	}
+
+	page, err := r.u.List(c.Request.Context(), f, query.Get("cursor"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - list")
+		if errors.Is(err, usecase.ErrorBadCursor) {
+			errorResponse(c, http.StatusBadRequest, "incorrect cursor")
+
+			return
+		}
+
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{page})
+}
@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type accountAdminRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newAccountAdminRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &accountAdminRoutes{u, l}
+
+	h := handler.Group("/admin/account")
+	{
+		h.PUT("/:id/freeze", r.freeze)
+		h.PUT("/:id/unfreeze", r.unfreeze)
+		h.PUT("/:id/close", r.close)
+		h.GET("/:id/status/history", r.getStatusHistory)
+		h.PUT("/:id/limit", r.setCreditLimit)
+		h.PUT("/:id/tier", r.setTier)
+	}
+}
+
+// @Summary     Freeze account
+// @Description Freeze account, frozen account accepts credits only
+// @ID          freeze
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Param       reason    query     string  true  "Reason of status change"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/freeze [put]
+func (r *accountAdminRoutes) freeze(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - freeze")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	account, err := r.u.Freeze(c.Request.Context(), id, c.Request.URL.Query().Get("reason"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - freeze")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{account})
+}
+
+// @Summary     Unfreeze account
+// @Description Make frozen account active
+// @ID          unfreeze
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Param       reason    query     string  true  "Reason of status change"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/unfreeze [put]
+func (r *accountAdminRoutes) unfreeze(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - unfreeze")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	account, err := r.u.Unfreeze(c.Request.Context(), id, c.Request.URL.Query().Get("reason"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - unfreeze")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{account})
+}
+
+// @Summary     Close account
+// @Description Close account, account with non-zero balance is closed only with sweep of the balance to another account
+// @ID          close
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Param       reason    query     string  true  "Reason of status change"
+// @Param       sweepTo    query     int  false  "Account ID to sweep the balance to"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/close [put]
+func (r *accountAdminRoutes) close(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - close")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	var sweepId int64
+	if value := c.Request.URL.Query().Get("sweepTo"); value != "" {
+		sweepId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - close")
+			errorResponse(c, http.StatusBadRequest, "incorrect sweep account ID")
+
+			return
+		}
+	}
+
+	account, err := r.u.Close(c.Request.Context(), id, c.Request.URL.Query().Get("reason"), sweepId)
+	if err != nil {
+		r.l.Error(err, "http - v1 - close")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{account})
+}
+
+// @Summary     Account status history
+// @Description Return history of account's status changes with reasons
+// @ID          statusHistory
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/status/history [get]
+func (r *accountAdminRoutes) getStatusHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - statusHistory")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	changes, err := r.u.GetStatusHistory(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - statusHistory")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{changes})
+}
+
+// @Summary     Set credit limit
+// @Description Set credit limit of account, account can go negative up to the limit
+// @ID          setCreditLimit
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Param       limit    query     number  true  "Credit limit in currency of account, 0 means no credit"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     422 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/limit [put]
+func (r *accountAdminRoutes) setCreditLimit(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setCreditLimit")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	limit, err := decimal.NewFromString(c.Request.URL.Query().Get("limit"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - setCreditLimit")
+		errorResponse(c, http.StatusBadRequest, "incorrect limit")
+
+		return
+	}
+
+	account, err := r.u.SetCreditLimit(c.Request.Context(), id, limit)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setCreditLimit")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{account})
+}
+
+// @Summary     Set account tier
+// @Description Set tier of account, fee rules of the tier apply to operations of account
+// @ID          setTier
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Account ID"
+// @Param       tier    query     string  true  "Tier of account: standard, premium or business"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/account/{id}/tier [put]
+func (r *accountAdminRoutes) setTier(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setTier")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	account, err := r.u.SetTier(c.Request.Context(), id, entity.AccountTier(c.Request.URL.Query().Get("tier")))
+	if err != nil {
+		r.l.Error(err, "http - v1 - setTier")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{account})
+}
@@ -0,0 +1,247 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type campaignRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newCampaignRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &campaignRoutes{u, l}
+
+	h := handler.Group("/admin/campaigns")
+	{
+		h.POST("/", r.create)
+		h.GET("/", r.list)
+		h.GET("/:id", r.getById)
+		h.PUT("/:id/budget", r.addBudget)
+		h.PUT("/:id/pause", r.pause)
+		h.PUT("/:id/resume", r.resume)
+	}
+}
+
+type campaignRequest struct {
+	Name       string              `json:"name" example:"Cashback on groceries"`
+	Purpose    string              `json:"purpose,omitempty" example:"groceries"`
+	Currency   string              `json:"currency" example:"RUB"`
+	Percent    decimal.Decimal     `json:"percent" swaggertype:"string" example:"5"`
+	MonthlyCap decimal.NullDecimal `json:"monthly_cap" swaggertype:"string" example:"500"`
+	Budget     decimal.Decimal     `json:"budget" swaggertype:"string" example:"100000"`
+	StartDt    time.Time           `json:"start_dt"`
+	EndDt      *time.Time          `json:"end_dt,omitempty"`
+}
+
+// @Summary     Create cashback campaign
+// @Description Campaign pays percent of redeems with purpose as cashback from promo system account, capped per account in calendar month, until its budget is spent
+// @ID          createCampaign
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       request    body     campaignRequest  true  "Campaign, start is now by default"
+// @Success     200 {object} entity.Campaign
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/ [post]
+func (r *campaignRoutes) create(c *gin.Context) {
+	var request campaignRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createCampaign")
+		errorResponse(c, http.StatusBadRequest, "incorrect campaign")
+
+		return
+	}
+
+	campaign, err := r.u.CreateCampaign(c.Request.Context(), entity.Campaign{
+		Name:       request.Name,
+		Purpose:    request.Purpose,
+		Currency:   request.Currency,
+		Percent:    request.Percent,
+		MonthlyCap: request.MonthlyCap,
+		Budget:     request.Budget,
+		StartDt:    request.StartDt,
+		EndDt:      request.EndDt,
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - createCampaign")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaign})
+}
+
+// @Summary     List cashback campaigns
+// @Description Return cashback campaigns with spent budget in order of ID
+// @ID          listCampaigns
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       status    query     string  false  "Status of campaign: active, paused or exhausted"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/ [get]
+func (r *campaignRoutes) list(c *gin.Context) {
+	campaigns, err := r.u.ListCampaigns(c.Request.Context(), c.Request.URL.Query().Get("status"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - listCampaigns")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaigns})
+}
+
+// @Summary     Get cashback campaign
+// @Description Return cashback campaign with spent budget
+// @ID          getCampaign
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Campaign ID"
+// @Success     200 {object} entity.Campaign
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/{id} [get]
+func (r *campaignRoutes) getById(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCampaign")
+		errorResponse(c, http.StatusBadRequest, "incorrect campaign ID")
+
+		return
+	}
+
+	campaign, err := r.u.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getCampaign")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaign})
+}
+
+// @Summary     Add budget of cashback campaign
+// @Description Add amount to budget of campaign, exhausted campaign pays cashback again
+// @ID          addCampaignBudget
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Campaign ID"
+// @Param       amount    query     number  true  "Amount to add in currency of campaign"
+// @Success     200 {object} entity.Campaign
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/{id}/budget [put]
+func (r *campaignRoutes) addBudget(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - addCampaignBudget")
+		errorResponse(c, http.StatusBadRequest, "incorrect campaign ID")
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - addCampaignBudget")
+		errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+		return
+	}
+
+	campaign, err := r.u.AddCampaignBudget(c.Request.Context(), id, amount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - addCampaignBudget")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaign})
+}
+
+// @Summary     Pause cashback campaign
+// @Description Pause active or exhausted campaign, it does not pay cashback until it is resumed
+// @ID          pauseCampaign
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Campaign ID"
+// @Success     200 {object} entity.Campaign
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/{id}/pause [put]
+func (r *campaignRoutes) pause(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - pauseCampaign")
+		errorResponse(c, http.StatusBadRequest, "incorrect campaign ID")
+
+		return
+	}
+
+	campaign, err := r.u.PauseCampaign(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - pauseCampaign")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaign})
+}
+
+// @Summary     Resume cashback campaign
+// @Description Resume paused campaign, it is exhausted when its budget is spent
+// @ID          resumeCampaign
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Campaign ID"
+// @Success     200 {object} entity.Campaign
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/campaigns/{id}/resume [put]
+func (r *campaignRoutes) resume(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeCampaign")
+		errorResponse(c, http.StatusBadRequest, "incorrect campaign ID")
+
+		return
+	}
+
+	campaign, err := r.u.ResumeCampaign(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeCampaign")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{campaign})
+}
@@ -1,13 +1,95 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
 )
 
 type response struct {
 	Error string `json:"error" example:"message"`
 }
 
+// velocityLimitResponse - error of debit over velocity limit with the limit and allowance left in its period.
+type velocityLimitResponse struct {
+	Error     string             `json:"error" example:"velocity limit exceeded"`
+	Period    entity.LimitPeriod `json:"period" example:"day"`
+	Kind      entity.LimitKind   `json:"kind" example:"amount"`
+	Max       decimal.Decimal    `json:"max" swaggertype:"string" example:"50000"`
+	Remaining decimal.Decimal    `json:"remaining" swaggertype:"string" example:"1500"`
+}
+
+// insufficientFundsResponse - error of redeem over available amount of account, code tells
+// whether money is not enough (account has no credit limit) or credit limit is exceeded.
+type insufficientFundsResponse struct {
+	Error     string          `json:"error" example:"not enough money, available amount is 30"`
+	Code      string          `json:"code" enums:"not_enough_money,credit_limit_exceeded" example:"not_enough_money"`
+	Available decimal.Decimal `json:"available" swaggertype:"string" example:"30"`
+}
+
 func errorResponse(c *gin.Context, code int, msg string) {
 	c.AbortWithStatusJSON(code, response{msg})
 }
+
+// operationErrorStatus - HTTP status of failed money operation.
+func operationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrAccountNotFound), errors.Is(err, entity.ErrSystemAccountNotFound),
+		errors.Is(err, entity.ErrTransactionNotFound), errors.Is(err, entity.ErrHoldNotFound),
+		errors.Is(err, entity.ErrEscrowNotFound), errors.Is(err, entity.ErrScheduledNotFound),
+		errors.Is(err, entity.ErrRecurringNotFound), errors.Is(err, entity.ErrFeeRuleNotFound),
+		errors.Is(err, entity.ErrCampaignNotFound), errors.Is(err, entity.ErrVelocityLimitNotFound),
+		errors.Is(err, entity.ErrRateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrorIdempotencyConflict), errors.Is(err, entity.ErrNotReversible),
+		errors.Is(err, entity.ErrAccountFrozen), errors.Is(err, entity.ErrAccountClosed),
+		errors.Is(err, entity.ErrStatusTransition), errors.Is(err, entity.ErrBalanceNotZero),
+		errors.Is(err, entity.ErrHoldExists), errors.Is(err, entity.ErrHoldsActive),
+		errors.Is(err, entity.ErrHoldNotActive), errors.Is(err, entity.ErrHoldExpired),
+		errors.Is(err, entity.ErrEscrowExists), errors.Is(err, entity.ErrEscrowTransition),
+		errors.Is(err, entity.ErrEscrowExpired), errors.Is(err, entity.ErrEscrowsOpen),
+		errors.Is(err, entity.ErrScheduledNotPending), errors.Is(err, entity.ErrRecurringClosed),
+		errors.Is(err, entity.ErrRecurringNotSuspended), errors.Is(err, entity.ErrCampaignPaused),
+		errors.Is(err, entity.ErrCampaignNotPaused), errors.Is(err, entity.ErrRateConflict):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrNotEnoughMoney), errors.Is(err, entity.ErrLimitExceeded),
+		errors.Is(err, entity.ErrReversalExceeded), errors.Is(err, entity.ErrReversalTooSmall),
+		errors.Is(err, entity.ErrVelocityLimit), errors.Is(err, entity.ErrCurrencyMismatch),
+		errors.Is(err, entity.ErrHoldExceeded), errors.Is(err, entity.ErrLimitBelowDebt):
+		return http.StatusUnprocessableEntity
+	}
+
+	return http.StatusInternalServerError
+}
+
+// operationErrorResponse - abort failed money operation with status of its error,
+// redeem over available amount and debit over velocity limit are answered with details of the limit.
+func operationErrorResponse(c *gin.Context, err error) {
+	var funds *entity.InsufficientFundsError
+	if errors.As(err, &funds) {
+		code := "not_enough_money"
+		if errors.Is(funds.Err, entity.ErrLimitExceeded) {
+			code = "credit_limit_exceeded"
+		}
+
+		c.AbortWithStatusJSON(operationErrorStatus(err), insufficientFundsResponse{funds.Error(), code, funds.Available})
+
+		return
+	}
+
+	var velocity *entity.VelocityLimitError
+	if errors.As(err, &velocity) {
+		c.AbortWithStatusJSON(operationErrorStatus(err), velocityLimitResponse{velocity.Error(), velocity.Period, velocity.Kind, velocity.Max, velocity.Remaining})
+
+		return
+	}
+
+	errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+	errorResponse(c, operationErrorStatus(err), errorMassage)
+}
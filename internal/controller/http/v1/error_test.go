@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+func TestOperationErrorResponse_InsufficientFunds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantAvailable string
+		wantError     string
+	}{
+		{
+			name:          "Case of correct work: not enough money",
+			err:           fmt.Errorf("AccountUseCase - UpdBalance - uc.repo.UpdBalance: %w", &entity.InsufficientFundsError{Available: decimal.NewFromInt(30), Err: entity.ErrNotEnoughMoney}),
+			wantCode:      "not_enough_money",
+			wantAvailable: "30",
+			wantError:     "not enough money, available amount is 30",
+		},
+		{
+			name:          "Case of correct work: credit limit exceeded",
+			err:           fmt.Errorf("AccountUseCase - TransferAmount - uc.repo.TransferAmount: %w", &entity.InsufficientFundsError{Available: decimal.RequireFromString("40.5"), Err: entity.ErrLimitExceeded}),
+			wantCode:      "credit_limit_exceeded",
+			wantAvailable: "40.5",
+			wantError:     "credit limit exceeded, available amount is 40.5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			operationErrorResponse(c, tt.err)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("operationErrorResponse() status = %v, want %v", w.Code, http.StatusUnprocessableEntity)
+			}
+
+			var got insufficientFundsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("operationErrorResponse() body = %s, error = %v", w.Body.String(), err)
+			}
+			if got.Code != tt.wantCode || got.Available.String() != tt.wantAvailable || got.Error != tt.wantError {
+				t.Errorf("operationErrorResponse() body = %+v, want code %v, available %v, error %v", got, tt.wantCode, tt.wantAvailable, tt.wantError)
+			}
+		})
+	}
+}
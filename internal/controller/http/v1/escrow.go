@@ -0,0 +1,265 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type escrowRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newEscrowRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &escrowRoutes{u, l}
+
+	h := handler.Group("/escrows")
+	{
+		h.POST("/", r.fund)
+		h.GET("/:id", r.getById)
+		h.GET("/:id/history", r.getHistory)
+		h.PUT("/:id/release", r.release)
+		h.PUT("/:id/refund", r.refund)
+		h.PUT("/:id/dispute", r.dispute)
+	}
+}
+
+type escrowAccount struct {
+	Escrow  entity.Escrow  `json:"escrow"`
+	Account entity.Account `json:"account"`
+}
+
+// @Summary     Fund safe deal
+// @Description Move amount of buyer to escrow until it is released to seller or refunded to buyer, deal which is not released for TTL is refunded. Returns deal and buyer account
+// @ID          fundEscrow
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       buyerId    query     int  true  "Account ID of buyer"
+// @Param       sellerId    query     int  true  "Account ID of seller"
+// @Param       orderId    query     string  true  "Order ID, one order of buyer has one safe deal"
+// @Param       amount    query     number  true  "Amount of deal"
+// @Param       ttl    query     string  false  "Time to release deal, e.g. 72h, 336h by default"
+// @Param       description    query     string  false  "Description of deal"
+// @Param       purpose    query     string  false  "Purpose code of deal"
+// @Param       source    query     string  false  "Source service of deal"
+// @Success     200 {object} correctResponse
+// @Failure     409 {object} response
+// @Failure     422 {object} velocityLimitResponse
+// @Failure     500 {object} response
+// @Router      /escrows/ [post]
+func (r *escrowRoutes) fund(c *gin.Context) {
+	buyerId, err := strconv.ParseInt(c.Request.URL.Query().Get("buyerId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - fundEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect buyer ID")
+
+		return
+	}
+
+	sellerId, err := strconv.ParseInt(c.Request.URL.Query().Get("sellerId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - fundEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect seller ID")
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - fundEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+		return
+	}
+
+	var ttl time.Duration
+	if value := c.Request.URL.Query().Get("ttl"); value != "" {
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - fundEscrow")
+			errorResponse(c, http.StatusBadRequest, "incorrect TTL")
+
+			return
+		}
+	}
+
+	escrow, account, err := r.u.FundEscrow(c.Request.Context(), buyerId, sellerId, c.Request.URL.Query().Get("orderId"), amount, ttl, transactionMeta(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - fundEscrow")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{escrowAccount{escrow, account}})
+}
+
+// @Summary     Get safe deal
+// @Description Return safe deal by ID
+// @ID          getEscrow
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Safe deal ID"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /escrows/{id} [get]
+func (r *escrowRoutes) getById(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect safe deal ID")
+
+		return
+	}
+
+	escrow, err := r.u.GetEscrow(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getEscrow")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{escrow})
+}
+
+// @Summary     Safe deal history
+// @Description Return history of safe deal's status changes with reasons and journal entries of moved money
+// @ID          escrowHistory
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Safe deal ID"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /escrows/{id}/history [get]
+func (r *escrowRoutes) getHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - escrowHistory")
+		errorResponse(c, http.StatusBadRequest, "incorrect safe deal ID")
+
+		return
+	}
+
+	changes, err := r.u.GetEscrowHistory(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - escrowHistory")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{changes})
+}
+
+// @Summary     Release safe deal
+// @Description Pay amount of funded or disputed safe deal to seller, e.g. delivery is confirmed. Returns deal and seller account
+// @ID          releaseEscrow
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Safe deal ID"
+// @Param       reason    query     string  false  "Reason of release"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /escrows/{id}/release [put]
+func (r *escrowRoutes) release(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - releaseEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect safe deal ID")
+
+		return
+	}
+
+	escrow, account, err := r.u.ReleaseEscrow(c.Request.Context(), id, c.Request.URL.Query().Get("reason"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - releaseEscrow")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{escrowAccount{escrow, account}})
+}
+
+// @Summary     Refund safe deal
+// @Description Return amount of funded or disputed safe deal to buyer. Returns deal and buyer account
+// @ID          refundEscrow
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Safe deal ID"
+// @Param       reason    query     string  false  "Reason of refund"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /escrows/{id}/refund [put]
+func (r *escrowRoutes) refund(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - refundEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect safe deal ID")
+
+		return
+	}
+
+	escrow, account, err := r.u.RefundEscrow(c.Request.Context(), id, c.Request.URL.Query().Get("reason"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - refundEscrow")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{escrowAccount{escrow, account}})
+}
+
+// @Summary     Dispute safe deal
+// @Description Dispute funded safe deal, money is held until the dispute is resolved by release or refund
+// @ID          disputeEscrow
+// @Tags  	    escrow
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Safe deal ID"
+// @Param       reason    query     string  true  "Reason of dispute"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /escrows/{id}/dispute [put]
+func (r *escrowRoutes) dispute(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disputeEscrow")
+		errorResponse(c, http.StatusBadRequest, "incorrect safe deal ID")
+
+		return
+	}
+
+	escrow, err := r.u.DisputeEscrow(c.Request.Context(), id, c.Request.URL.Query().Get("reason"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - disputeEscrow")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{escrow})
+}
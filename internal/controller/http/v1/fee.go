@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type feeRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newFeeRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &feeRoutes{u, l}
+
+	h := handler.Group("/admin/fees")
+	{
+		h.POST("/", r.create)
+		h.GET("/", r.list)
+		h.PUT("/:id/disable", r.disable)
+	}
+}
+
+type feeRuleRequest struct {
+	Operation entity.FeeOperation `json:"operation" example:"transfer"`
+	Currency  string              `json:"currency" example:"RUB"`
+	Tier      *entity.AccountTier `json:"tier,omitempty"`
+	Kind      entity.FeeKind      `json:"kind" example:"percent"`
+	Flat      decimal.Decimal     `json:"flat" swaggertype:"string" example:"0"`
+	Percent   decimal.Decimal     `json:"percent" swaggertype:"string" example:"1.5"`
+	Tiers     []entity.FeeTier    `json:"tiers,omitempty"`
+	MinFee    decimal.NullDecimal `json:"min_fee" swaggertype:"string" example:"10"`
+	MaxFee    decimal.NullDecimal `json:"max_fee" swaggertype:"string" example:"500"`
+}
+
+// @Summary     Create fee rule
+// @Description Fee of operation in currency for accounts of tier: flat, percent or tiered with min and max fee. Rule without tier applies to any tier, the rule replaces active rule of the same operation, currency and tier
+// @ID          createFeeRule
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       request    body     feeRuleRequest  true  "Fee rule"
+// @Success     200 {object} entity.FeeRule
+// @Failure     500 {object} response
+// @Router      /admin/fees/ [post]
+func (r *feeRoutes) create(c *gin.Context) {
+	var request feeRuleRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createFeeRule")
+		errorResponse(c, http.StatusBadRequest, "incorrect fee rule")
+
+		return
+	}
+
+	rule, err := r.u.CreateFeeRule(c.Request.Context(), entity.FeeRule{
+		Operation: request.Operation,
+		Currency:  request.Currency,
+		Tier:      request.Tier,
+		Kind:      request.Kind,
+		Flat:      request.Flat,
+		Percent:   request.Percent,
+		Tiers:     request.Tiers,
+		MinFee:    request.MinFee,
+		MaxFee:    request.MaxFee,
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - createFeeRule")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rule})
+}
+
+// @Summary     List fee rules
+// @Description Return active fee rules in order of operation, currency and tier
+// @ID          listFeeRules
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/fees/ [get]
+func (r *feeRoutes) list(c *gin.Context) {
+	rules, err := r.u.ListFeeRules(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - listFeeRules")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rules})
+}
+
+// @Summary     Disable fee rule
+// @Description Disable active fee rule, operations are not charged by it anymore
+// @ID          disableFeeRule
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Fee rule ID"
+// @Success     200 {object} entity.FeeRule
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/fees/{id}/disable [put]
+func (r *feeRoutes) disable(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disableFeeRule")
+		errorResponse(c, http.StatusBadRequest, "incorrect fee rule ID")
+
+		return
+	}
+
+	rule, err := r.u.DisableFeeRule(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disableFeeRule")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rule})
+}
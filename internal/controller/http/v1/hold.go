@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type holdRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newHoldRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &holdRoutes{u, l}
+
+	h := handler.Group("/hold")
+	{
+		h.POST("/", r.create)
+		h.GET("/:id", r.getById)
+		h.PUT("/:id/capture", r.capture)
+		h.PUT("/:id/release", r.release)
+	}
+}
+
+type holdAccount struct {
+	Hold    entity.Hold    `json:"hold"`
+	Account entity.Account `json:"account"`
+}
+
+// @Summary     Create hold
+// @Description Reserve amount on account for order, reserved amount is not available for redeem
+// @ID          createHold
+// @Tags  	    hold
+// @Accept      json
+// @Produce     json
+// @Param       accountId    query     int  true  "Account ID"
+// @Param       orderId    query     string  true  "Order ID, one order has one hold on account"
+// @Param       amount    query     number  true  "Amount to reserve"
+// @Param       ttl    query     string  false  "Time to live of hold, e.g. 15m or 2h, 24h by default"
+// @Success     200 {object} holdAccount
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     422 {object} insufficientFundsResponse
+// @Failure     500 {object} response
+// @Router      /hold/ [post]
+func (r *holdRoutes) create(c *gin.Context) {
+	accountId, err := strconv.ParseInt(c.Request.URL.Query().Get("accountId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createHold")
+		errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createHold")
+		errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+		return
+	}
+
+	var ttl time.Duration
+	if value := c.Request.URL.Query().Get("ttl"); value != "" {
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - createHold")
+			errorResponse(c, http.StatusBadRequest, "incorrect TTL")
+
+			return
+		}
+	}
+
+	hold, account, err := r.u.CreateHold(c.Request.Context(), accountId, c.Request.URL.Query().Get("orderId"), amount, ttl)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createHold")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{holdAccount{hold, account}})
+}
+
+// @Summary     Get hold
+// @Description Return hold by ID
+// @ID          getHold
+// @Tags  	    hold
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Hold ID"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /hold/{id} [get]
+func (r *holdRoutes) getById(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getHold")
+		errorResponse(c, http.StatusBadRequest, "incorrect hold ID")
+
+		return
+	}
+
+	hold, err := r.u.GetHold(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getHold")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{hold})
+}
+
+// @Summary     Capture hold
+// @Description Charge amount of hold from account, the rest of hold is released
+// @ID          captureHold
+// @Tags  	    hold
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Hold ID"
+// @Param       amount    query     number  false  "Amount to charge, whole hold by default"
+// @Success     200 {object} holdAccount
+// @Failure     422 {object} velocityLimitResponse
+// @Failure     500 {object} response
+// @Router      /hold/{id}/capture [put]
+func (r *holdRoutes) capture(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - captureHold")
+		errorResponse(c, http.StatusBadRequest, "incorrect hold ID")
+
+		return
+	}
+
+	amount := decimal.Zero
+	if value := c.Request.URL.Query().Get("amount"); value != "" {
+		amount, err = decimal.NewFromString(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - captureHold")
+			errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+			return
+		}
+	}
+
+	hold, account, err := r.u.CaptureHold(c.Request.Context(), id, amount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - captureHold")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{holdAccount{hold, account}})
+}
+
+// @Summary     Release hold
+// @Description Return reserved amount of hold to available balance of account
+// @ID          releaseHold
+// @Tags  	    hold
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Hold ID"
+// @Success     200 {object} holdAccount
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /hold/{id}/release [put]
+func (r *holdRoutes) release(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - releaseHold")
+		errorResponse(c, http.StatusBadRequest, "incorrect hold ID")
+
+		return
+	}
+
+	hold, account, err := r.u.ReleaseHold(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - releaseHold")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{holdAccount{hold, account}})
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type ledgerRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newLedgerRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &ledgerRoutes{u, l}
+
+	h := handler.Group("/admin/ledger")
+	{
+		h.GET("/check", r.check)
+		h.GET("/accounts", r.systemAccounts)
+	}
+}
+
+// @Summary     Check ledger
+// @Description Check that sum of all postings is zero in every currency and in every journal entry
+// @ID          checkLedger
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} entity.LedgerCheck
+// @Failure     500 {object} response
+// @Router      /admin/ledger/check [get]
+func (r *ledgerRoutes) check(c *gin.Context) {
+	check, err := r.u.CheckLedger(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - checkLedger")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{check})
+}
+
+// @Summary     System accounts
+// @Description Return chart of system accounts in every currency with their balances, which are sums of their postings
+// @ID          systemAccounts
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/ledger/accounts [get]
+func (r *ledgerRoutes) systemAccounts(c *gin.Context) {
+	accounts, err := r.u.SystemAccounts(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - systemAccounts")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{accounts})
+}
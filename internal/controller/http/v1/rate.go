@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type rateRoutes struct {
+	u usecase.RateUseCase
+	l logger.Interface
+}
+
+func newRateRoutes(handler *gin.RouterGroup, u usecase.RateUseCase, l logger.Interface) {
+	r := &rateRoutes{u, l}
+
+	h := handler.Group("/admin/rate")
+	{
+		h.POST("/", r.create)
+		h.GET("/:base/:quote", r.getAt)
+		h.GET("/history/:base/:quote", r.getHistory)
+	}
+}
+
+// parseTime - parse optional RFC 3339 time, zero time is returned for empty value.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// @Summary     Add exchange rate
+// @Description Add rate of currency pair to the rate history, the rate is valid till the next rate of the pair
+// @ID          createRate
+// @Tags  	    rate
+// @Accept      json
+// @Produce     json
+// @Param       base    query     string  true  "Base currency code"
+// @Param       quote    query     string  true  "Quote currency code"
+// @Param       rate    query     number  true  "Price of one unit of base currency in quote currency"
+// @Param       spread    query     number  false  "Share of converted amount kept by the service, 0 by default"
+// @Param       validFrom    query     string  false  "Start of validity in RFC 3339, not in the past and after the latest rate of the pair, current time by default"
+// @Success     200 {object} correctResponse
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/rate [post]
+func (r *rateRoutes) create(c *gin.Context) {
+	value, err := decimal.NewFromString(c.Request.URL.Query().Get("rate"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRate")
+		errorResponse(c, http.StatusBadRequest, "incorrect rate")
+
+		return
+	}
+
+	spread := decimal.Zero
+	if value := c.Request.URL.Query().Get("spread"); value != "" {
+		spread, err = decimal.NewFromString(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - createRate")
+			errorResponse(c, http.StatusBadRequest, "incorrect spread")
+
+			return
+		}
+	}
+
+	validFrom, err := parseTime(c.Request.URL.Query().Get("validFrom"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRate")
+		errorResponse(c, http.StatusBadRequest, "incorrect validFrom value")
+
+		return
+	}
+
+	base := c.Request.URL.Query().Get("base")
+	quote := c.Request.URL.Query().Get("quote")
+
+	rate, err := r.u.Create(c.Request.Context(), base, quote, value, spread, validFrom)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRate")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rate})
+}
+
+// @Summary     Get exchange rate
+// @Description Returns rate of currency pair which was valid at the moment
+// @ID          getRate
+// @Tags  	    rate
+// @Accept      json
+// @Produce     json
+// @Param       base   path      string  true  "Base currency code"
+// @Param       quote   path      string  true  "Quote currency code"
+// @Param       at    query     string  false  "Moment in RFC 3339, current time by default"
+// @Success     200 {object} correctResponse
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/rate/{base}/{quote} [get]
+func (r *rateRoutes) getAt(c *gin.Context) {
+	at, err := parseTime(c.Request.URL.Query().Get("at"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRate")
+		errorResponse(c, http.StatusBadRequest, "incorrect at value")
+
+		return
+	}
+
+	rate, err := r.u.GetAt(c.Request.Context(), c.Param("base"), c.Param("quote"), at)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRate")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rate})
+}
+
+// @Summary     Exchange rate history
+// @Description Return history of currency pair's rates
+// @ID          rateHistory
+// @Tags  	    rate
+// @Accept      json
+// @Produce     json
+// @Param       base   path      string  true  "Base currency code"
+// @Param       quote   path      string  true  "Quote currency code"
+// @Param       limit    query     int  true  "The value of limit in pagination"
+// @Param       offset    query     int  true  "The value of offset in pagination"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/rate/history/{base}/{quote} [get]
+func (r *rateRoutes) getHistory(c *gin.Context) {
+	limit, err := strconv.ParseUint(c.Request.URL.Query().Get("limit"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rateHistory")
+		errorResponse(c, http.StatusBadRequest, "incorrect limit value")
+
+		return
+	}
+
+	offset, err := strconv.ParseUint(c.Request.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rateHistory")
+		errorResponse(c, http.StatusBadRequest, "incorrect offset value")
+
+		return
+	}
+
+	rates, err := r.u.GetHistory(c.Request.Context(), c.Param("base"), c.Param("quote"), limit, offset)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rateHistory")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{rates})
+}
@@ -0,0 +1,338 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type recurringRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newRecurringRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &recurringRoutes{u, l}
+
+	h := handler.Group("/recurring")
+	{
+		h.POST("/", r.create)
+		h.GET("/", r.list)
+		h.GET("/:id", r.getById)
+		h.GET("/:id/attempts", r.attempts)
+		h.PUT("/:id/cancel", r.cancel)
+		h.PUT("/:id/resume", r.resume)
+	}
+}
+
+// @Summary     Create recurring payment plan
+// @Description Periodic transfer from redeem account to accrual account by interval or cron schedule, e.g. subscription. Failed charge is retried, then plan is suspended
+// @ID          createRecurringPlan
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       redeemId    query     int  true  "Account ID for redeem funds"
+// @Param       accrId    query     int  true  "Account ID for accrual funds"
+// @Param       amount    query     number  true  "Amount of charge"
+// @Param       schedule    query     string  true  "Interval like @every 720h or cron expression in UTC like 0 10 1 * *"
+// @Param       startAt    query     string  false  "Start of plan in RFC3339, now by default"
+// @Param       maxCharges    query     int  false  "Max number of charges, no limit by default"
+// @Param       endAt    query     string  false  "End of plan in RFC3339"
+// @Param       description    query     string  false  "Description of charges"
+// @Param       purpose    query     string  false  "Purpose code of charges"
+// @Param       source    query     string  false  "Source service of charges"
+// @Success     200 {object} entity.RecurringPlan
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     422 {object} response
+// @Failure     500 {object} response
+// @Router      /recurring/ [post]
+func (r *recurringRoutes) create(c *gin.Context) {
+	redeemId, err := strconv.ParseInt(c.Request.URL.Query().Get("redeemId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect redeem ID")
+
+		return
+	}
+
+	accrId, err := strconv.ParseInt(c.Request.URL.Query().Get("accrId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect accrual ID")
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+		return
+	}
+
+	var startAt, endAt time.Time
+	if value := c.Request.URL.Query().Get("startAt"); value != "" {
+		startAt, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - createRecurringPlan")
+			errorResponse(c, http.StatusBadRequest, "incorrect start time")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("endAt"); value != "" {
+		endAt, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - createRecurringPlan")
+			errorResponse(c, http.StatusBadRequest, "incorrect end time")
+
+			return
+		}
+	}
+
+	var maxCharges int
+	if value := c.Request.URL.Query().Get("maxCharges"); value != "" {
+		maxCharges, err = strconv.Atoi(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - createRecurringPlan")
+			errorResponse(c, http.StatusBadRequest, "incorrect max number of charges")
+
+			return
+		}
+	}
+
+	plan, err := r.u.CreateRecurringPlan(c.Request.Context(), redeemId, accrId, amount, c.Request.URL.Query().Get("schedule"),
+		startAt, maxCharges, endAt, transactionMeta(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createRecurringPlan")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{plan})
+}
+
+// @Summary     List recurring payment plans
+// @Description Return recurring payment plans in order of ID
+// @ID          listRecurringPlans
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       accountId    query     int  false  "Account ID on any side of plan"
+// @Param       status    query     string  false  "Status of plan: active, suspended, completed or cancelled"
+// @Param       limit    query     int  false  "Limit of page, 50 by default"
+// @Param       offset    query     int  false  "Offset of page"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /recurring/ [get]
+func (r *recurringRoutes) list(c *gin.Context) {
+	var (
+		f   entity.RecurringPlanFilter
+		err error
+	)
+
+	if value := c.Request.URL.Query().Get("accountId"); value != "" {
+		f.AccountId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listRecurringPlans")
+			errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("limit"); value != "" {
+		f.Limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listRecurringPlans")
+			errorResponse(c, http.StatusBadRequest, "incorrect limit")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("offset"); value != "" {
+		f.Offset, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listRecurringPlans")
+			errorResponse(c, http.StatusBadRequest, "incorrect offset")
+
+			return
+		}
+	}
+
+	f.Status = entity.RecurringStatus(c.Request.URL.Query().Get("status"))
+
+	plans, err := r.u.ListRecurringPlans(c.Request.Context(), f)
+	if err != nil {
+		r.l.Error(err, "http - v1 - listRecurringPlans")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{plans})
+}
+
+// @Summary     Get recurring payment plan
+// @Description Return recurring payment plan by ID with number of charges and failed attempts of the next charge
+// @ID          getRecurringPlan
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Recurring plan ID"
+// @Success     200 {object} entity.RecurringPlan
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /recurring/{id} [get]
+func (r *recurringRoutes) getById(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect recurring plan ID")
+
+		return
+	}
+
+	plan, err := r.u.GetRecurringPlan(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRecurringPlan")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{plan})
+}
+
+// @Summary     Get attempts of recurring charges
+// @Description Return history of attempts of charges of plan, successful and failed ones with reason
+// @ID          getRecurringAttempts
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Recurring plan ID"
+// @Param       limit    query     int  false  "Limit of page, 50 by default"
+// @Param       offset    query     int  false  "Offset of page"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /recurring/{id}/attempts [get]
+func (r *recurringRoutes) attempts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRecurringAttempts")
+		errorResponse(c, http.StatusBadRequest, "incorrect recurring plan ID")
+
+		return
+	}
+
+	var limit, offset uint64
+	if value := c.Request.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - getRecurringAttempts")
+			errorResponse(c, http.StatusBadRequest, "incorrect limit")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("offset"); value != "" {
+		offset, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - getRecurringAttempts")
+			errorResponse(c, http.StatusBadRequest, "incorrect offset")
+
+			return
+		}
+	}
+
+	attempts, err := r.u.GetRecurringAttempts(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getRecurringAttempts")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{attempts})
+}
+
+// @Summary     Cancel recurring payment plan
+// @Description Cancel active or suspended plan
+// @ID          cancelRecurringPlan
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Recurring plan ID"
+// @Success     200 {object} entity.RecurringPlan
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /recurring/{id}/cancel [put]
+func (r *recurringRoutes) cancel(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect recurring plan ID")
+
+		return
+	}
+
+	plan, err := r.u.CancelRecurringPlan(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelRecurringPlan")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{plan})
+}
+
+// @Summary     Resume recurring payment plan
+// @Description Activate plan which is suspended after failed retries, the failed charge is attempted at once
+// @ID          resumeRecurringPlan
+// @Tags  	    recurring
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Recurring plan ID"
+// @Success     200 {object} entity.RecurringPlan
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /recurring/{id}/resume [put]
+func (r *recurringRoutes) resume(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeRecurringPlan")
+		errorResponse(c, http.StatusBadRequest, "incorrect recurring plan ID")
+
+		return
+	}
+
+	plan, err := r.u.ResumeRecurringPlan(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - resumeRecurringPlan")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{plan})
+}
@@ -35,7 +35,7 @@ func CORSMiddleware() gin.HandlerFunc {
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /v1
-func NewRouter(handler *gin.Engine, l logger.Interface, u usecase.AccountUseCase) {
+func NewRouter(handler *gin.Engine, l logger.Interface, u usecase.AccountUseCase, ru usecase.RateUseCase) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
@@ -50,5 +50,16 @@ func NewRouter(handler *gin.Engine, l logger.Interface, u usecase.AccountUseCase
 	h2 := handler.Group("/v1")
 	{
 		newAccountRoutes(h2, u, l)
+		newAccountAdminRoutes(h2, u, l)
+		newFeeRoutes(h2, u, l)
+		newLimitRoutes(h2, u, l)
+		newCampaignRoutes(h2, u, l)
+		newLedgerRoutes(h2, u, l)
+		newHoldRoutes(h2, u, l)
+		newTransactionRoutes(h2, u, l)
+		newTransferRoutes(h2, u, l)
+		newEscrowRoutes(h2, u, l)
+		newRecurringRoutes(h2, u, l)
+		newRateRoutes(h2, ru, l)
 	}
 }
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type transactionRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newTransactionRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &transactionRoutes{u, l}
+
+	h := handler.Group("/transactions")
+	{
+		h.POST("/:id/reverse", r.reverse)
+	}
+}
+
+// @Summary     Reverse transaction
+// @Description Reverse accrual or redeem fully or partially with compensating transactions, transfer is reversed on both accounts
+// @ID          reverse
+// @Tags  	    transaction
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Transaction ID"
+// @Param       amount    query     number  false  "Amount to reverse in currency of transaction, the whole remaining amount by default"
+// @Success     200 {object} correctResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     422 {object} response
+// @Failure     500 {object} response
+// @Router      /transactions/{id}/reverse [post]
+func (r *transactionRoutes) reverse(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reverse")
+		errorResponse(c, http.StatusBadRequest, "incorrect transaction ID")
+
+		return
+	}
+
+	amount := decimal.Zero
+	if value := c.Request.URL.Query().Get("amount"); value != "" {
+		amount, err = decimal.NewFromString(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - reverse")
+			errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+			return
+		}
+	}
+
+	transactions, err := r.u.ReverseTransaction(c.Request.Context(), id, amount)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reverse")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{transactions})
+}
@@ -0,0 +1,363 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type transferRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newTransferRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &transferRoutes{u, l}
+
+	h := handler.Group("/transfers")
+	{
+		h.POST("/batch", r.batch)
+		h.POST("/split", r.split)
+		h.POST("/scheduled", r.createScheduled)
+		h.GET("/scheduled", r.listScheduled)
+		h.GET("/scheduled/:id", r.getScheduled)
+		h.PUT("/scheduled/:id", r.updateScheduled)
+		h.PUT("/scheduled/:id/cancel", r.cancelScheduled)
+	}
+}
+
+type batchTransferRequest struct {
+	Legs []entity.TransferLeg `json:"legs"`
+}
+
+// @Summary     Batch transfer
+// @Description Make all legs of transfer at once or none of them, e.g. buyer pays seller, platform fee and courier. Returns all accounts of legs
+// @ID          batchTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       request    body     batchTransferRequest  true  "Legs of transfer"
+// @Param       Idempotency-Key    header     string  false  "Key of request, retry with the same key returns the original response"
+// @Success     200 {object} correctResponse
+// @Failure     409 {object} response
+// @Failure     422 {object} velocityLimitResponse
+// @Failure     500 {object} response
+// @Router      /transfers/batch [post]
+func (r *transferRoutes) batch(c *gin.Context) {
+	var request batchTransferRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - batchTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect legs of transfer")
+
+		return
+	}
+
+	accounts, err := r.u.BatchTransfer(c.Request.Context(), request.Legs, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - batchTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{accounts})
+}
+
+type splitTransferRequest struct {
+	RedeemId int64               `json:"redeem_id"`
+	Amount   decimal.Decimal     `json:"amount" swaggertype:"string" example:"1000"`
+	Shares   []entity.SplitShare `json:"shares"`
+	entity.TransactionMeta
+}
+
+// @Summary     Split transfer
+// @Description Split payment of one payer between many payees by percents or fixed amounts at once, e.g. order of several sellers. Unit left by rounding of percents goes to share with the largest remainder, zero amount of fixed shares means their sum
+// @ID          splitTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       request    body     splitTransferRequest  true  "Payer, amount and shares of payees"
+// @Param       Idempotency-Key    header     string  false  "Key of request, retry with the same key returns the original response"
+// @Success     200 {object} correctResponse
+// @Failure     409 {object} response
+// @Failure     422 {object} velocityLimitResponse
+// @Failure     500 {object} response
+// @Router      /transfers/split [post]
+func (r *transferRoutes) split(c *gin.Context) {
+	var request splitTransferRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - splitTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect shares of transfer")
+
+		return
+	}
+
+	split, err := r.u.SplitTransfer(c.Request.Context(), request.RedeemId, request.Amount, request.Shares,
+		request.TransactionMeta, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - splitTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{split})
+}
+
+// @Summary     Create scheduled transfer
+// @Description Schedule transfer between accounts of the same currency at run time, failed attempt is retried later
+// @ID          createScheduledTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       redeemId    query     int  true  "Account ID for redeem funds"
+// @Param       accrId    query     int  true  "Account ID for accrual funds"
+// @Param       amount    query     number  true  "Amount of transfer"
+// @Param       runAt    query     string  true  "Run time of transfer in RFC3339, e.g. 2022-11-01T10:00:00Z"
+// @Param       description    query     string  false  "Description of transfer"
+// @Param       purpose    query     string  false  "Purpose code of transfer"
+// @Param       source    query     string  false  "Source service of transfer"
+// @Success     200 {object} entity.ScheduledTransfer
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     422 {object} response
+// @Failure     500 {object} response
+// @Router      /transfers/scheduled [post]
+func (r *transferRoutes) createScheduled(c *gin.Context) {
+	redeemId, err := strconv.ParseInt(c.Request.URL.Query().Get("redeemId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect redeem ID")
+
+		return
+	}
+
+	accrId, err := strconv.ParseInt(c.Request.URL.Query().Get("accrId"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect accrual ID")
+
+		return
+	}
+
+	amount, err := decimal.NewFromString(c.Request.URL.Query().Get("amount"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+		return
+	}
+
+	runAt, err := time.Parse(time.RFC3339, c.Request.URL.Query().Get("runAt"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect run time")
+
+		return
+	}
+
+	st, err := r.u.CreateScheduledTransfer(c.Request.Context(), redeemId, accrId, amount, runAt, transactionMeta(c))
+	if err != nil {
+		r.l.Error(err, "http - v1 - createScheduledTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{st})
+}
+
+// @Summary     List scheduled transfers
+// @Description Return scheduled transfers in order of run time
+// @ID          listScheduledTransfers
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       accountId    query     int  false  "Account ID on any side of transfer"
+// @Param       status    query     string  false  "Status of transfer: pending, executed, failed or cancelled"
+// @Param       limit    query     int  false  "Limit of page, 50 by default"
+// @Param       offset    query     int  false  "Offset of page"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /transfers/scheduled [get]
+func (r *transferRoutes) listScheduled(c *gin.Context) {
+	var (
+		f   entity.ScheduledTransferFilter
+		err error
+	)
+
+	if value := c.Request.URL.Query().Get("accountId"); value != "" {
+		f.AccountId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listScheduledTransfers")
+			errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("limit"); value != "" {
+		f.Limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listScheduledTransfers")
+			errorResponse(c, http.StatusBadRequest, "incorrect limit")
+
+			return
+		}
+	}
+
+	if value := c.Request.URL.Query().Get("offset"); value != "" {
+		f.Offset, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listScheduledTransfers")
+			errorResponse(c, http.StatusBadRequest, "incorrect offset")
+
+			return
+		}
+	}
+
+	f.Status = entity.ScheduledStatus(c.Request.URL.Query().Get("status"))
+
+	sts, err := r.u.ListScheduledTransfers(c.Request.Context(), f)
+	if err != nil {
+		r.l.Error(err, "http - v1 - listScheduledTransfers")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{sts})
+}
+
+// @Summary     Get scheduled transfer
+// @Description Return scheduled transfer by ID with its status, attempts and reason of the last failure
+// @ID          getScheduledTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Scheduled transfer ID"
+// @Success     200 {object} entity.ScheduledTransfer
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /transfers/scheduled/{id} [get]
+func (r *transferRoutes) getScheduled(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect scheduled transfer ID")
+
+		return
+	}
+
+	st, err := r.u.GetScheduledTransfer(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getScheduledTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{st})
+}
+
+// @Summary     Update scheduled transfer
+// @Description Change amount or run time of pending transfer, failed attempts of transfer are forgotten
+// @ID          updateScheduledTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Scheduled transfer ID"
+// @Param       amount    query     number  false  "New amount of transfer"
+// @Param       runAt    query     string  false  "New run time of transfer in RFC3339"
+// @Success     200 {object} entity.ScheduledTransfer
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /transfers/scheduled/{id} [put]
+func (r *transferRoutes) updateScheduled(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect scheduled transfer ID")
+
+		return
+	}
+
+	amount := decimal.Zero
+	if value := c.Request.URL.Query().Get("amount"); value != "" {
+		amount, err = decimal.NewFromString(value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - updateScheduledTransfer")
+			errorResponse(c, http.StatusBadRequest, "incorrect amount")
+
+			return
+		}
+	}
+
+	var runAt time.Time
+	if value := c.Request.URL.Query().Get("runAt"); value != "" {
+		runAt, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			r.l.Error(err, "http - v1 - updateScheduledTransfer")
+			errorResponse(c, http.StatusBadRequest, "incorrect run time")
+
+			return
+		}
+	}
+
+	st, err := r.u.UpdateScheduledTransfer(c.Request.Context(), id, amount, runAt)
+	if err != nil {
+		r.l.Error(err, "http - v1 - updateScheduledTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{st})
+}
+
+// @Summary     Cancel scheduled transfer
+// @Description Cancel pending transfer
+// @ID          cancelScheduledTransfer
+// @Tags  	    transfer
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Scheduled transfer ID"
+// @Success     200 {object} entity.ScheduledTransfer
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /transfers/scheduled/{id}/cancel [put]
+func (r *transferRoutes) cancelScheduled(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelScheduledTransfer")
+		errorResponse(c, http.StatusBadRequest, "incorrect scheduled transfer ID")
+
+		return
+	}
+
+	st, err := r.u.CancelScheduledTransfer(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelScheduledTransfer")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{st})
+}
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/cut4cut/avito-test-work/pkg/logger"
+)
+
+type limitRoutes struct {
+	u usecase.AccountUseCase
+	l logger.Interface
+}
+
+func newLimitRoutes(handler *gin.RouterGroup, u usecase.AccountUseCase, l logger.Interface) {
+	r := &limitRoutes{u, l}
+
+	h := handler.Group("/admin/limits")
+	{
+		h.POST("/", r.create)
+		h.GET("/", r.list)
+		h.PUT("/:id/disable", r.disable)
+	}
+}
+
+type velocityLimitRequest struct {
+	AccountId *int64              `json:"account_id,omitempty" example:"1"`
+	Currency  string              `json:"currency" example:"RUB"`
+	Period    entity.LimitPeriod  `json:"period" example:"day"`
+	MaxAmount decimal.NullDecimal `json:"max_amount" swaggertype:"string" example:"50000"`
+	MaxCount  *int64              `json:"max_count,omitempty" example:"20"`
+}
+
+// @Summary     Create velocity limit
+// @Description Limit of total amount and number of debits of account in currency per calendar day, week or month (UTC). Limit without account applies to all accounts, the limit replaces active limit of the same account, currency and period
+// @ID          createVelocityLimit
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       request    body     velocityLimitRequest  true  "Velocity limit"
+// @Success     200 {object} entity.VelocityLimit
+// @Failure     500 {object} response
+// @Router      /admin/limits/ [post]
+func (r *limitRoutes) create(c *gin.Context) {
+	var request velocityLimitRequest
+
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		r.l.Error(err, "http - v1 - createVelocityLimit")
+		errorResponse(c, http.StatusBadRequest, "incorrect velocity limit")
+
+		return
+	}
+
+	limit, err := r.u.CreateVelocityLimit(c.Request.Context(), entity.VelocityLimit{
+		AccountId: request.AccountId,
+		Currency:  request.Currency,
+		Period:    request.Period,
+		MaxAmount: request.MaxAmount,
+		MaxCount:  request.MaxCount,
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - createVelocityLimit")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{limit})
+}
+
+// @Summary     List velocity limits
+// @Description Return active velocity limits of account and default ones in order of currency, period and account
+// @ID          listVelocityLimits
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       accountId    query     int  false  "Account ID, limits of all accounts by default"
+// @Success     200 {object} correctResponse
+// @Failure     500 {object} response
+// @Router      /admin/limits/ [get]
+func (r *limitRoutes) list(c *gin.Context) {
+	var accountId int64
+	var err error
+
+	if value := c.Request.URL.Query().Get("accountId"); value != "" {
+		accountId, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.l.Error(err, "http - v1 - listVelocityLimits")
+			errorResponse(c, http.StatusBadRequest, "incorrect account ID")
+
+			return
+		}
+	}
+
+	limits, err := r.u.ListVelocityLimits(c.Request.Context(), accountId)
+	if err != nil {
+		r.l.Error(err, "http - v1 - listVelocityLimits")
+		errorMassage := fmt.Sprint("internal Error: ", errors.Unwrap(err))
+		errorResponse(c, http.StatusInternalServerError, errorMassage)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{limits})
+}
+
+// @Summary     Disable velocity limit
+// @Description Disable active velocity limit, debits are not limited by it anymore
+// @ID          disableVelocityLimit
+// @Tags  	    admin
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Velocity limit ID"
+// @Success     200 {object} entity.VelocityLimit
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/limits/{id}/disable [put]
+func (r *limitRoutes) disable(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disableVelocityLimit")
+		errorResponse(c, http.StatusBadRequest, "incorrect velocity limit ID")
+
+		return
+	}
+
+	limit, err := r.u.DisableVelocityLimit(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error(err, "http - v1 - disableVelocityLimit")
+		operationErrorResponse(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, correctResponse{limit})
+}
@@ -1,11 +1,137 @@
 package entity
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Account struct {
-	Id        int64     `json:"id"`
-	Balance   float64   `json:"balance"`
-	CreatedDt time.Time `json:"created_dt"`
+	Id          int64           `json:"id"`
+	OwnerId     *string         `json:"owner_id,omitempty"`
+	Balance     decimal.Decimal `json:"balance" swaggertype:"string" example:"56.5"`
+	Currency    string          `json:"currency"`
+	Status      AccountStatus   `json:"status"`
+	StatusDt    time.Time       `json:"status_dt"`
+	CreditLimit decimal.Decimal `json:"credit_limit" swaggertype:"string" example:"0"`
+	Reserved    decimal.Decimal `json:"reserved" swaggertype:"string" example:"0"`
+	Available   decimal.Decimal `json:"available" swaggertype:"string" example:"56.5"`
+	Wallet      Wallet          `json:"wallet"`
+	Tier        AccountTier     `json:"tier"`
+	CreatedDt   time.Time       `json:"created_dt"`
+}
+
+// Wallet - kind of owner's balance, every owner has at most one account of each wallet.
+type Wallet string
+
+const (
+	// WalletMain - real money, wallet of accounts by default.
+	WalletMain Wallet = "main"
+	// WalletBonus - bonus points.
+	WalletBonus Wallet = "bonus"
+	// WalletEscrow - money held for safe deals.
+	WalletEscrow Wallet = "escrow"
+)
+
+// IsKnown - check that wallet is one of supported wallets.
+func (w Wallet) IsKnown() bool {
+	switch w {
+	case WalletMain, WalletBonus, WalletEscrow:
+		return true
+	}
+
+	return false
+}
+
+// AccountTier - service level of account, fees of operations depend on it.
+type AccountTier string
+
+const (
+	// TierStandard - tier of accounts by default.
+	TierStandard AccountTier = "standard"
+	// TierPremium - tier of premium users.
+	TierPremium AccountTier = "premium"
+	// TierBusiness - tier of merchants and companies.
+	TierBusiness AccountTier = "business"
+)
+
+// IsKnown - check that tier is one of account tiers.
+func (t AccountTier) IsKnown() bool {
+	switch t {
+	case TierStandard, TierPremium, TierBusiness:
+		return true
+	}
+
+	return false
+}
+
+// AccountRef - reference to account either by ID or by external owner ID and wallet.
+type AccountRef struct {
+	Id      int64
+	OwnerId string
+	Wallet  Wallet
+}
+
+// IsOwner - check that account is referenced by external owner ID.
+func (r AccountRef) IsOwner() bool {
+	return r.OwnerId != ""
+}
+
+// String - reference as ID or as owner ID and wallet.
+func (r AccountRef) String() string {
+	if r.IsOwner() {
+		return fmt.Sprintf("owner:%s:%s", r.OwnerId, r.Wallet)
+	}
+
+	return fmt.Sprintf("id:%d", r.Id)
+}
+
+// AccountStatus - lifecycle state of account.
+type AccountStatus string
+
+const (
+	// AccountActive - account accepts credits and debits.
+	AccountActive AccountStatus = "active"
+	// AccountFrozen - account accepts credits only.
+	AccountFrozen AccountStatus = "frozen"
+	// AccountClosed - account rejects any operation, the state is final.
+	AccountClosed AccountStatus = "closed"
+)
+
+var accountTransitions = map[AccountStatus][]AccountStatus{
+	AccountActive: {AccountFrozen, AccountClosed},
+	AccountFrozen: {AccountActive, AccountClosed},
+}
+
+// IsKnown - check that status is one of account statuses.
+func (s AccountStatus) IsKnown() bool {
+	switch s {
+	case AccountActive, AccountFrozen, AccountClosed:
+		return true
+	}
+
+	return false
+}
+
+// CanChangeTo - check that account in status s can be moved to status to.
+func (s AccountStatus) CanChangeTo(to AccountStatus) bool {
+	for _, next := range accountTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StatusChange - record of account's status change.
+type StatusChange struct {
+	Id         int64         `json:"id"`
+	AccountId  int64         `json:"account_id"`
+	FromStatus AccountStatus `json:"from_status"`
+	ToStatus   AccountStatus `json:"to_status"`
+	Reason     string        `json:"reason"`
+	SweepId    *int64        `json:"sweep_id,omitempty"`
+	ChangedDt  time.Time     `json:"changed_dt"`
 }
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountFilter - conditions, order and page of account search. Zero values mean no condition.
+type AccountFilter struct {
+	MinBalance   decimal.NullDecimal
+	MaxBalance   decimal.NullDecimal
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	Status       AccountStatus
+	OwnerId      string
+	Currency     string
+	Sort         string
+	IsDecreasing bool
+	Limit        uint64
+	After        *AccountCursor
+}
+
+// AccountCursor - position of keyset pagination, value of sort column and ID of the last account of page.
+type AccountCursor struct {
+	Value string `json:"v"`
+	Id    int64  `json:"id"`
+}
+
+// Encode - encode cursor to opaque string.
+func (c AccountCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeAccountCursor - decode cursor from string made by Encode.
+func DecodeAccountCursor(value string) (c AccountCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &c)
+
+	return
+}
+
+// SortValue - value of cursor in type of sort column.
+func (c AccountCursor) SortValue(sort string) (interface{}, error) {
+	switch sort {
+	case "balance":
+		return decimal.NewFromString(c.Value)
+	case "created_dt":
+		return time.Parse(time.RFC3339Nano, c.Value)
+	}
+
+	return strconv.ParseInt(c.Value, 10, 64)
+}
+
+// AccountPage - page of accounts found by filter with total count of found accounts.
+type AccountPage struct {
+	Accounts   []Account `json:"accounts"`
+	Total      int64     `json:"total"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CampaignStatus - state of cashback campaign.
+type CampaignStatus string
+
+const (
+	// CampaignActive - campaign pays cashback.
+	CampaignActive CampaignStatus = "active"
+	// CampaignPaused - campaign is paused by admin.
+	CampaignPaused CampaignStatus = "paused"
+	// CampaignExhausted - budget of campaign is spent, it is active again when budget is added.
+	CampaignExhausted CampaignStatus = "exhausted"
+)
+
+// IsKnown - check that status is one of statuses of cashback campaign.
+func (s CampaignStatus) IsKnown() bool {
+	switch s {
+	case CampaignActive, CampaignPaused, CampaignExhausted:
+		return true
+	}
+
+	return false
+}
+
+// Campaign - cashback of percent of redeems with purpose, empty purpose means any redeem. Cashback is paid
+// from promo system account while spent amount is less than budget, MonthlyCap limits cashback of account
+// in calendar month (UTC). Spent is cashback paid by campaign, reversed cashback is returned to budget.
+type Campaign struct {
+	Id         int64               `json:"id"`
+	Name       string              `json:"name"`
+	Purpose    string              `json:"purpose,omitempty"`
+	Currency   string              `json:"currency"`
+	Percent    decimal.Decimal     `json:"percent" swaggertype:"string" example:"5"`
+	MonthlyCap decimal.NullDecimal `json:"monthly_cap" swaggertype:"string" example:"500"`
+	Budget     decimal.Decimal     `json:"budget" swaggertype:"string" example:"100000"`
+	Spent      decimal.Decimal     `json:"spent" swaggertype:"string" example:"1500"`
+	Status     CampaignStatus      `json:"status"`
+	StartDt    time.Time           `json:"start_dt"`
+	EndDt      *time.Time          `json:"end_dt,omitempty"`
+	CreatedDt  time.Time           `json:"created_dt"`
+	UpdatedDt  time.Time           `json:"updated_dt"`
+}
+
+// Cashback - cashback of redeem amount before rounding to currency and caps.
+func (c Campaign) Cashback(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(c.Percent).Div(_hundred)
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency - currency of accounts created without explicit currency.
+const DefaultCurrency = "RUB"
+
+// Rounding - rounding rule applied to amounts of a currency.
+type Rounding int
+
+const (
+	// RoundHalfUp - round half away from zero.
+	RoundHalfUp Rounding = iota
+	// RoundHalfEven - banker's rounding.
+	RoundHalfEven
+	// RoundDown - truncate towards zero.
+	RoundDown
+)
+
+// Currency - ISO 4217 currency with its minor unit precision.
+type Currency struct {
+	Code     string
+	Exponent int32
+	Rounding Rounding
+}
+
+var currencies = map[string]Currency{
+	"RUB": {Code: "RUB", Exponent: 2, Rounding: RoundHalfUp},
+	"USD": {Code: "USD", Exponent: 2, Rounding: RoundHalfEven},
+	"EUR": {Code: "EUR", Exponent: 2, Rounding: RoundHalfEven},
+	"GBP": {Code: "GBP", Exponent: 2, Rounding: RoundHalfEven},
+	"CNY": {Code: "CNY", Exponent: 2, Rounding: RoundHalfUp},
+	"KZT": {Code: "KZT", Exponent: 2, Rounding: RoundHalfUp},
+	"JPY": {Code: "JPY", Exponent: 0, Rounding: RoundDown},
+}
+
+// CurrencyByCode - find currency by ISO 4217 code, the code is case insensitive.
+func CurrencyByCode(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Round - round amount to the minor unit of currency.
+func (c Currency) Round(amount decimal.Decimal) decimal.Decimal {
+	switch c.Rounding {
+	case RoundHalfEven:
+		return amount.RoundBank(c.Exponent)
+	case RoundDown:
+		return amount.Truncate(c.Exponent)
+	default:
+		return amount.Round(c.Exponent)
+	}
+}
+
+// Fits - check that amount has no digits below the minor unit of currency.
+func (c Currency) Fits(amount decimal.Decimal) bool {
+	return amount.Equal(amount.Truncate(c.Exponent))
+}
+
+// Split - split positive amount into parts in proportion to positive weights by largest remainder method:
+// every part is rounded down to the minor unit and the units left go one by one to parts with the largest
+// remainders, the first parts win ties. Parts add up to amount exactly.
+func (c Currency) Split(amount decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	total := decimal.Zero
+	for _, w := range weights {
+		total = total.Add(w)
+	}
+
+	parts := make([]decimal.Decimal, len(weights))
+	remainders := make([]decimal.Decimal, len(weights))
+	left := amount
+
+	for i, w := range weights {
+		exact := amount.Mul(w).Div(total)
+		parts[i] = exact.Truncate(c.Exponent)
+		remainders[i] = exact.Sub(parts[i])
+		left = left.Sub(parts[i])
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].GreaterThan(remainders[order[j]])
+	})
+
+	unit := decimal.New(1, -c.Exponent)
+	for i := 0; left.IsPositive() && i < len(order); i++ {
+		parts[order[i]] = parts[order[i]].Add(unit)
+		left = left.Sub(unit)
+	}
+
+	return parts
+}
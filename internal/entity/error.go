@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNotEnoughMoney        error = errors.New("not enough money")
+	ErrLimitExceeded         error = errors.New("credit limit exceeded")
+	ErrLimitBelowDebt        error = errors.New("credit limit is less than account debt")
+	ErrHoldNotFound          error = errors.New("hold not found")
+	ErrHoldExists            error = errors.New("hold for the order already exists")
+	ErrHoldExceeded          error = errors.New("capture amount is greater than hold amount")
+	ErrTransactionNotFound   error = errors.New("transaction not found")
+	ErrNotReversible         error = errors.New("transaction can not be reversed")
+	ErrReversalExceeded      error = errors.New("reversal amount is greater than remaining amount of transaction")
+	ErrReversalTooSmall      error = errors.New("reversal amount is too small for the other postings of journal entry")
+	ErrAccountNotFound       error = errors.New("account not found")
+	ErrScheduledNotPending   error = errors.New("scheduled transfer is not pending")
+	ErrScheduledNotDue       error = errors.New("scheduled transfer is not due or is executed by another worker")
+	ErrRecurringNotDue       error = errors.New("recurring charge is not due or is recorded by another worker")
+	ErrCampaignNotFound      error = errors.New("campaign not found")
+	ErrEscrowNotFound        error = errors.New("safe deal not found")
+	ErrEscrowExists          error = errors.New("safe deal for the order already exists")
+	ErrEscrowTransition      error = errors.New("safe deal status transition is not allowed")
+	ErrEscrowExpired         error = errors.New("safe deal is expired")
+	ErrVelocityLimit         error = errors.New("velocity limit exceeded")
+	ErrVelocityLimitNotFound error = errors.New("active velocity limit not found")
+	ErrIdempotencyConflict   error = errors.New("idempotency key is already used with another request")
+	ErrConcurrentUpdate      error = errors.New("operation conflicts with concurrent one and can be retried")
+	ErrHoldsActive           error = errors.New("account has active holds")
+	ErrHoldNotActive         error = errors.New("hold is not active")
+	ErrHoldExpired           error = errors.New("hold is expired")
+	ErrSystemAccountNotFound error = errors.New("system account not found")
+	ErrCurrencyMismatch      error = errors.New("currency of account does not match the operation")
+	ErrAccountFrozen         error = errors.New("account is frozen")
+	ErrAccountClosed         error = errors.New("account is closed")
+	ErrStatusTransition      error = errors.New("account status transition is not allowed")
+	ErrBalanceNotZero        error = errors.New("account balance is not zero")
+	ErrScheduledNotFound     error = errors.New("scheduled transfer not found")
+	ErrRecurringNotFound     error = errors.New("recurring plan not found")
+	ErrRecurringClosed       error = errors.New("recurring plan is completed or cancelled")
+	ErrRecurringNotSuspended error = errors.New("recurring plan is not suspended")
+	ErrFeeRuleNotFound       error = errors.New("active fee rule not found")
+	ErrCampaignPaused        error = errors.New("campaign is paused")
+	ErrCampaignNotPaused     error = errors.New("campaign is not paused")
+	ErrEscrowsOpen           error = errors.New("account has safe deals in progress")
+	ErrRateNotFound          error = errors.New("exchange rate not found")
+	ErrRateConflict          error = errors.New("exchange rate with the same or later start of validity already exists")
+)
+
+// InsufficientFundsError - redeem is more than available amount of account, which is balance plus credit limit.
+// Err is ErrNotEnoughMoney for account without credit limit and ErrLimitExceeded otherwise.
+type InsufficientFundsError struct {
+	Available decimal.Decimal
+	Err       error
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%s, available amount is %s", e.Err, e.Available)
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return e.Err
+}
+
+// VelocityLimitError - debit is more than allowance of velocity limit of account in calendar period, Kind tells
+// whether total amount or number of debits is exceeded. Remaining is amount or number of debits left in period.
+type VelocityLimitError struct {
+	Period    LimitPeriod
+	Kind      LimitKind
+	Max       decimal.Decimal
+	Remaining decimal.Decimal
+}
+
+func (e *VelocityLimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit per %s of %s is reached, remaining allowance is %s", ErrVelocityLimit, e.Kind, e.Period, e.Max, e.Remaining)
+}
+
+func (e *VelocityLimitError) Unwrap() error {
+	return ErrVelocityLimit
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// EscrowStatus - state of safe deal.
+type EscrowStatus string
+
+const (
+	// EscrowFunded - money of buyer is held until delivery is confirmed.
+	EscrowFunded EscrowStatus = "funded"
+	// EscrowDisputed - deal is disputed, money is held until dispute is resolved by release or refund.
+	EscrowDisputed EscrowStatus = "disputed"
+	// EscrowReleased - money is paid to seller, the state is final.
+	EscrowReleased EscrowStatus = "released"
+	// EscrowRefunded - money is returned to buyer, the state is final.
+	EscrowRefunded EscrowStatus = "refunded"
+)
+
+var escrowTransitions = map[EscrowStatus][]EscrowStatus{
+	EscrowFunded:   {EscrowReleased, EscrowRefunded, EscrowDisputed},
+	EscrowDisputed: {EscrowReleased, EscrowRefunded},
+}
+
+// IsKnown - check that status is one of statuses of safe deal.
+func (s EscrowStatus) IsKnown() bool {
+	switch s {
+	case EscrowFunded, EscrowDisputed, EscrowReleased, EscrowRefunded:
+		return true
+	}
+
+	return false
+}
+
+// CanChangeTo - check that safe deal in status s can be moved to status to.
+func (s EscrowStatus) CanChangeTo(to EscrowStatus) bool {
+	for _, next := range escrowTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Escrow - safe deal: amount of buyer is held on escrow system account until it is released to seller
+// or refunded to buyer. Funded deal which is not released until expiry is refunded, disputed deal does not expire.
+type Escrow struct {
+	Id        int64           `json:"id"`
+	BuyerId   int64           `json:"buyer_id"`
+	SellerId  int64           `json:"seller_id"`
+	OrderId   string          `json:"order_id"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"1500"`
+	Currency  string          `json:"currency"`
+	Status    EscrowStatus    `json:"status"`
+	ExpiresDt time.Time       `json:"expires_dt"`
+	CreatedDt time.Time       `json:"created_dt"`
+	UpdatedDt time.Time       `json:"updated_dt"`
+	TransactionMeta
+}
+
+// EscrowChange - record of safe deal's status change, FromStatus is nil for funding.
+// JournalId is journal entry of money moved by the change.
+type EscrowChange struct {
+	Id         int64         `json:"id"`
+	EscrowId   int64         `json:"escrow_id"`
+	FromStatus *EscrowStatus `json:"from_status,omitempty"`
+	ToStatus   EscrowStatus  `json:"to_status"`
+	Reason     string        `json:"reason"`
+	JournalId  *int64        `json:"journal_id,omitempty"`
+	ChangedDt  time.Time     `json:"changed_dt"`
+}
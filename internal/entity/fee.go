@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FeeOperation - kind of operation which is charged with fee.
+type FeeOperation string
+
+const (
+	// FeeTransfer - transfer between accounts, including scheduled and recurring ones.
+	FeeTransfer FeeOperation = "transfer"
+	// FeeBatchTransfer - leg of batch transfer.
+	FeeBatchTransfer FeeOperation = "batch_transfer"
+	// FeeWithdrawal - redeem of account to cash-out system account.
+	FeeWithdrawal FeeOperation = "withdrawal"
+)
+
+// IsKnown - check that operation is one of operations with fee.
+func (o FeeOperation) IsKnown() bool {
+	switch o {
+	case FeeTransfer, FeeBatchTransfer, FeeWithdrawal:
+		return true
+	}
+
+	return false
+}
+
+// FeeKind - way of fee calculation.
+type FeeKind string
+
+const (
+	// FeeFlat - fixed fee.
+	FeeFlat FeeKind = "flat"
+	// FeePercent - percent of amount plus fixed fee.
+	FeePercent FeeKind = "percent"
+	// FeeTiered - percent and fixed fee of tier the amount falls into.
+	FeeTiered FeeKind = "tiered"
+)
+
+// IsKnown - check that kind is one of fee kinds.
+func (k FeeKind) IsKnown() bool {
+	switch k {
+	case FeeFlat, FeePercent, FeeTiered:
+		return true
+	}
+
+	return false
+}
+
+// FeeTier - fee of amounts from From up to From of the next tier.
+type FeeTier struct {
+	From    decimal.Decimal `json:"from" swaggertype:"string" example:"10000"`
+	Flat    decimal.Decimal `json:"flat" swaggertype:"string" example:"0"`
+	Percent decimal.Decimal `json:"percent" swaggertype:"string" example:"0.5"`
+}
+
+// FeeRule - fee of operation in currency for accounts of tier, nil tier means any tier and the rule
+// of exact tier wins. Fee is limited by min and max fee when they are set, percent is in percents.
+type FeeRule struct {
+	Id        int64               `json:"id"`
+	Operation FeeOperation        `json:"operation"`
+	Currency  string              `json:"currency"`
+	Tier      *AccountTier        `json:"tier,omitempty"`
+	Kind      FeeKind             `json:"kind"`
+	Flat      decimal.Decimal     `json:"flat" swaggertype:"string" example:"0"`
+	Percent   decimal.Decimal     `json:"percent" swaggertype:"string" example:"1.5"`
+	Tiers     []FeeTier           `json:"tiers,omitempty"`
+	MinFee    decimal.NullDecimal `json:"min_fee" swaggertype:"string" example:"10"`
+	MaxFee    decimal.NullDecimal `json:"max_fee" swaggertype:"string" example:"500"`
+	Active    bool                `json:"active"`
+	CreatedDt time.Time           `json:"created_dt"`
+}
+
+// _hundred - divisor of percents.
+var _hundred = decimal.NewFromInt(100)
+
+// Fee - fee of amount by rule before rounding to currency.
+func (r FeeRule) Fee(amount decimal.Decimal) decimal.Decimal {
+	fee := decimal.Zero
+
+	switch r.Kind {
+	case FeeFlat:
+		fee = r.Flat
+	case FeePercent:
+		fee = r.Flat.Add(amount.Mul(r.Percent).Div(_hundred))
+	case FeeTiered:
+		for _, tier := range r.Tiers {
+			if amount.LessThan(tier.From) {
+				break
+			}
+
+			fee = tier.Flat.Add(amount.Mul(tier.Percent).Div(_hundred))
+		}
+	}
+
+	if r.MinFee.Valid && fee.LessThan(r.MinFee.Decimal) {
+		fee = r.MinFee.Decimal
+	}
+
+	if r.MaxFee.Valid && fee.GreaterThan(r.MaxFee.Decimal) {
+		fee = r.MaxFee.Decimal
+	}
+
+	return fee
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HoldStatus - state of funds reservation.
+type HoldStatus string
+
+const (
+	// HoldActive - amount is reserved on account.
+	HoldActive HoldStatus = "active"
+	// HoldCaptured - reserved amount is charged, the rest of it is released.
+	HoldCaptured HoldStatus = "captured"
+	// HoldReleased - reserved amount is returned to available balance.
+	HoldReleased HoldStatus = "released"
+	// HoldExpired - hold is not captured until expiry, reserved amount is returned to available balance.
+	HoldExpired HoldStatus = "expired"
+)
+
+// Hold - reservation of amount on account for order, the amount is charged by capture.
+type Hold struct {
+	Id        int64           `json:"id"`
+	AccountId int64           `json:"account_id"`
+	OrderId   string          `json:"order_id"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"100"`
+	Captured  decimal.Decimal `json:"captured" swaggertype:"string" example:"0"`
+	Currency  string          `json:"currency"`
+	Status    HoldStatus      `json:"status"`
+	CreatedDt time.Time       `json:"created_dt"`
+	ExpiresDt time.Time       `json:"expires_dt"`
+	UpdatedDt time.Time       `json:"updated_dt"`
+}
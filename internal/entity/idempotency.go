@@ -0,0 +1,10 @@
+package entity
+
+// IdempotencyKey - key of request that can be retried with hash of request payload, key is unique within scope,
+// which is operation and account of caller, so keys of different callers and operations do not collide.
+// Retry with the same key gets the original response, the same key with other payload is a conflict.
+type IdempotencyKey struct {
+	Scope string
+	Key   string
+	Hash  string
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountType - type of account, system accounts are not reachable through customer endpoints.
+type AccountType string
+
+const (
+	// AccountCustomer - account of customer.
+	AccountCustomer AccountType = "customer"
+	// AccountSystem - account of the service itself, the other side of postings of customer accounts.
+	AccountSystem AccountType = "system"
+)
+
+// SystemAccount - code of system account in chart of system accounts. System account is the other side
+// of postings of customer accounts in journal entries, there is one system account of each code in every currency.
+type SystemAccount string
+
+const (
+	// SystemCashIn - money coming into the service, default for accruals.
+	SystemCashIn SystemAccount = "cash_in"
+	// SystemCashOut - money going out of the service, default for redeems and captured holds.
+	SystemCashOut SystemAccount = "cash_out"
+	// SystemFees - fees earned by the service.
+	SystemFees SystemAccount = "fees"
+	// SystemSuspense - money of unclear origin or destination until it is sorted out.
+	SystemSuspense SystemAccount = "suspense"
+	// SystemWriteOff - debts and remains written off.
+	SystemWriteOff SystemAccount = "write_off"
+	// SystemExchange - position of currency exchange, it takes amount in one currency and gives it in another one.
+	// Only conversion goes through it.
+	SystemExchange SystemAccount = "exchange"
+	// SystemPromo - budget of promotions, cashback of campaigns is paid from it.
+	SystemPromo SystemAccount = "promo"
+	// SystemEscrow - money of safe deals held until it is released to seller or refunded to buyer.
+	SystemEscrow SystemAccount = "escrow"
+)
+
+// _selectableSystemAccounts - system accounts which callers can choose for operation.
+var _selectableSystemAccounts = map[SystemAccount]bool{
+	SystemCashIn:   true,
+	SystemCashOut:  true,
+	SystemFees:     true,
+	SystemSuspense: true,
+	SystemWriteOff: true,
+}
+
+// SystemAccountByCode - find system account which callers can choose for operation, the code is case insensitive.
+func SystemAccountByCode(code string) (SystemAccount, bool) {
+	s := SystemAccount(strings.ToLower(code))
+	return s, _selectableSystemAccounts[s]
+}
+
+// LedgerAccount - system account with its balance, which is the sum of its postings.
+type LedgerAccount struct {
+	Id       int64           `json:"id"`
+	Code     SystemAccount   `json:"code"`
+	Currency string          `json:"currency"`
+	Balance  decimal.Decimal `json:"balance" swaggertype:"string" example:"-1500"`
+}
+
+// LedgerTotal - sum of all postings in currency.
+type LedgerTotal struct {
+	Currency string          `json:"currency"`
+	Sum      decimal.Decimal `json:"sum" swaggertype:"string" example:"0"`
+}
+
+// LedgerCheck - result of ledger check. Ledger is balanced when sum of postings is zero
+// in every currency and in every journal entry.
+type LedgerCheck struct {
+	Balanced           bool          `json:"balanced"`
+	Totals             []LedgerTotal `json:"totals"`
+	UnbalancedJournals []int64       `json:"unbalanced_journals"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate - price of one unit of base currency in quote currency.
+// Rates from rate history have ID and are valid from ValidFrom till ValidTo.
+// Spread is a share of converted amount kept by the service.
+type Rate struct {
+	Id        int64           `json:"id,omitempty"`
+	Base      string          `json:"base"`
+	Quote     string          `json:"quote"`
+	Value     decimal.Decimal `json:"rate" swaggertype:"string" example:"0.0105"`
+	Spread    decimal.Decimal `json:"spread" swaggertype:"string" example:"0.01"`
+	ValidFrom time.Time       `json:"valid_from"`
+	ValidTo   *time.Time      `json:"valid_to,omitempty"`
+}
+
+// Conversion - account balance converted to another currency.
+type Conversion struct {
+	Currency string          `json:"currency"`
+	Balance  decimal.Decimal `json:"balance" swaggertype:"string" example:"0.59"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"0.0105"`
+	RateId   int64           `json:"rate_id,omitempty"`
+	RateDt   time.Time       `json:"rate_dt"`
+}
+
+// Convert - convert amount of base currency to quote currency with spread, the result is not rounded.
+func (r Rate) Convert(amount decimal.Decimal) decimal.Decimal {
+	return amount.Mul(r.Value).Mul(decimal.NewFromInt(1).Sub(r.Spread))
+}
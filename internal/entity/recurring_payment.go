@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RecurringStatus - state of recurring payment plan.
+type RecurringStatus string
+
+const (
+	// RecurringActive - plan charges on schedule.
+	RecurringActive RecurringStatus = "active"
+	// RecurringSuspended - retries of failed charge run out, plan waits for resume.
+	RecurringSuspended RecurringStatus = "suspended"
+	// RecurringCompleted - plan made max number of charges or reached its end.
+	RecurringCompleted RecurringStatus = "completed"
+	// RecurringCancelled - plan is cancelled.
+	RecurringCancelled RecurringStatus = "cancelled"
+)
+
+// IsKnown - check that status is one of statuses of recurring payment plan.
+func (s RecurringStatus) IsKnown() bool {
+	switch s {
+	case RecurringActive, RecurringSuspended, RecurringCompleted, RecurringCancelled:
+		return true
+	}
+
+	return false
+}
+
+// RecurringPlan - periodic transfer from redeem account to accrual account, e.g. subscription.
+// NextChargeDt is scheduled time of the next charge, NextAttemptDt is time of the next attempt of it,
+// which is later than scheduled time while failed charge is retried. Zero MaxCharges means no limit.
+type RecurringPlan struct {
+	Id             int64           `json:"id"`
+	RedeemId       int64           `json:"redeem_id"`
+	AccrualId      int64           `json:"accrual_id"`
+	Amount         decimal.Decimal `json:"amount" swaggertype:"string" example:"299"`
+	Currency       string          `json:"currency"`
+	Schedule       string          `json:"schedule" example:"0 10 1 * *"`
+	Status         RecurringStatus `json:"status"`
+	NextChargeDt   time.Time       `json:"next_charge_dt"`
+	NextAttemptDt  time.Time       `json:"next_attempt_dt"`
+	Charges        int             `json:"charges"`
+	MaxCharges     int             `json:"max_charges,omitempty"`
+	EndDt          *time.Time      `json:"end_dt,omitempty"`
+	FailedAttempts int             `json:"failed_attempts"`
+	CreatedDt      time.Time       `json:"created_dt"`
+	UpdatedDt      time.Time       `json:"updated_dt"`
+	TransactionMeta
+}
+
+// RecurringAttemptStatus - result of attempt of recurring charge.
+type RecurringAttemptStatus string
+
+const (
+	// RecurringAttemptSucceeded - amount is charged.
+	RecurringAttemptSucceeded RecurringAttemptStatus = "succeeded"
+	// RecurringAttemptFailed - charge failed, failure tells the reason.
+	RecurringAttemptFailed RecurringAttemptStatus = "failed"
+)
+
+// RecurringAttempt - attempt of charge of recurring payment plan, Charge is number of charge in plan.
+type RecurringAttempt struct {
+	Id          int64                  `json:"id"`
+	PlanId      int64                  `json:"plan_id"`
+	Charge      int                    `json:"charge"`
+	AttemptDt   time.Time              `json:"attempt_dt"`
+	Amount      decimal.Decimal        `json:"amount" swaggertype:"string" example:"299"`
+	Status      RecurringAttemptStatus `json:"status"`
+	Failure     string                 `json:"failure,omitempty"`
+	FailureCode FailureCode            `json:"failure_code,omitempty"`
+}
+
+// RecurringPlanFilter - conditions of recurring payment plans search, zero values mean no condition.
+// Account is matched on both sides of plan.
+type RecurringPlanFilter struct {
+	AccountId int64
+	Status    RecurringStatus
+	Limit     uint64
+	Offset    uint64
+}
@@ -0,0 +1,192 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// _everyPrefix - prefix of interval schedule, e.g. @every 720h.
+	_everyPrefix = "@every "
+	// _minScheduleInterval - min interval of schedule.
+	_minScheduleInterval = time.Minute
+	// _maxScheduleSearch - period to search the next time of cron schedule in, e.g. 30 of February is never.
+	_maxScheduleSearch = 5 * 366 * 24 * time.Hour
+)
+
+// _scheduleAliases - short names of common cron schedules.
+var _scheduleAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// cronField - bounds of field of cron expression.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var _cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Schedule - times of periodic operation: interval (@every 720h) or cron expression of minute, hour,
+// day of month, month and day of week (0 10 1 * *), cron times are in UTC.
+type Schedule struct {
+	every time.Duration
+	// bits of allowed values of each cron field.
+	fields [5]uint64
+	// day of month and day of week are both restricted, then any of them matches as in cron.
+	anyDay bool
+}
+
+// ParseSchedule - parse interval or cron expression, aliases @hourly, @daily, @weekly, @monthly and @yearly are allowed.
+func ParseSchedule(spec string) (s Schedule, err error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, _everyPrefix) {
+		s.every, err = time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, _everyPrefix)))
+		if err != nil {
+			return s, err
+		}
+
+		if s.every < _minScheduleInterval {
+			return s, fmt.Errorf("interval is less than %s", _minScheduleInterval)
+		}
+
+		return s, nil
+	}
+
+	if alias, ok := _scheduleAliases[spec]; ok {
+		spec = alias
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(_cronFields) {
+		return s, errors.New("cron expression must have 5 fields")
+	}
+
+	for i, part := range parts {
+		s.fields[i], err = parseCronField(part, _cronFields[i])
+		if err != nil {
+			return s, err
+		}
+	}
+
+	s.anyDay = parts[2] != "*" && parts[4] != "*"
+
+	return s, nil
+}
+
+// parseCronField - parse list of values, ranges and steps of cron field, e.g. 1-5,10,*/15.
+func parseCronField(part string, f cronField) (bits uint64, err error) {
+	for _, item := range strings.Split(part, ",") {
+		step := 1
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return bits, fmt.Errorf("incorrect step of %s: %s", f.name, item)
+			}
+
+			item = item[:i]
+		}
+
+		from, to := f.min, f.max
+
+		if item != "*" {
+			bounds := strings.SplitN(item, "-", 2)
+
+			from, err = strconv.Atoi(bounds[0])
+			if err != nil {
+				return bits, fmt.Errorf("incorrect value of %s: %s", f.name, item)
+			}
+
+			to = from
+			if len(bounds) == 2 {
+				to, err = strconv.Atoi(bounds[1])
+				if err != nil {
+					return bits, fmt.Errorf("incorrect value of %s: %s", f.name, item)
+				}
+			} else if step > 1 {
+				to = f.max
+			}
+		}
+
+		if from < f.min || to > f.max || from > to {
+			return bits, fmt.Errorf("%s is out of range [%d, %d]: %s", f.name, f.min, f.max, item)
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// IsInterval - schedule is fixed interval, not cron expression.
+func (s Schedule) IsInterval() bool {
+	return s.every > 0
+}
+
+// has - value is allowed in cron field.
+func (s Schedule) has(field, value int) bool {
+	return s.fields[field]&(1<<uint(value)) != 0
+}
+
+// matchDay - day of month or day of week matches as in cron.
+func (s Schedule) matchDay(t time.Time) bool {
+	dom, dow := s.has(2, t.Day()), s.has(4, int(t.Weekday()))
+	if s.anyDay {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+// Next - the first time of schedule after t, zero time when cron expression has no time in the next 5 years.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.IsInterval() {
+		return t.Add(s.every)
+	}
+
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(_maxScheduleSearch)
+
+	for t.Before(limit) {
+		switch {
+		case !s.has(3, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !s.has(1, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !s.has(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// First - the first time of schedule at or after start, interval schedule starts at start.
+func (s Schedule) First(start time.Time) time.Time {
+	if s.IsInterval() {
+		return start
+	}
+
+	return s.Next(start.Add(-time.Nanosecond))
+}
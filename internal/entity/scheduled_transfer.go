@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ScheduledStatus - state of scheduled transfer.
+type ScheduledStatus string
+
+const (
+	// ScheduledPending - transfer waits for its time or for the next attempt.
+	ScheduledPending ScheduledStatus = "pending"
+	// ScheduledExecuted - transfer is made.
+	ScheduledExecuted ScheduledStatus = "executed"
+	// ScheduledFailed - all attempts of transfer failed, failure tells the reason of the last one.
+	ScheduledFailed ScheduledStatus = "failed"
+	// ScheduledCancelled - transfer is cancelled before execution.
+	ScheduledCancelled ScheduledStatus = "cancelled"
+)
+
+// FailureCode - kind of reason of failed attempt of transfer made by worker.
+type FailureCode string
+
+const (
+	// FailureInsufficientFunds - redeem account has not enough money.
+	FailureInsufficientFunds FailureCode = "insufficient_funds"
+	// FailureVelocityLimit - transfer exceeds velocity limit of redeem account.
+	FailureVelocityLimit FailureCode = "velocity_limit"
+	// FailureOther - any other reason, failure tells it.
+	FailureOther FailureCode = "other"
+)
+
+// IsKnown - check that status is one of statuses of scheduled transfer.
+func (s ScheduledStatus) IsKnown() bool {
+	switch s {
+	case ScheduledPending, ScheduledExecuted, ScheduledFailed, ScheduledCancelled:
+		return true
+	}
+
+	return false
+}
+
+// ScheduledTransfer - transfer from redeem account to accrual account which is made at run time by worker.
+// Failed attempt is retried later until attempts run out, failure code tells the kind of its reason.
+type ScheduledTransfer struct {
+	Id            int64           `json:"id"`
+	RedeemId      int64           `json:"redeem_id"`
+	AccrualId     int64           `json:"accrual_id"`
+	Amount        decimal.Decimal `json:"amount" swaggertype:"string" example:"100"`
+	Currency      string          `json:"currency"`
+	Status        ScheduledStatus `json:"status"`
+	RunDt         time.Time       `json:"run_dt"`
+	NextAttemptDt time.Time       `json:"next_attempt_dt"`
+	Attempts      int             `json:"attempts"`
+	Failure       string          `json:"failure,omitempty"`
+	FailureCode   FailureCode     `json:"failure_code,omitempty"`
+	JournalId     *int64          `json:"journal_id,omitempty"`
+	CreatedDt     time.Time       `json:"created_dt"`
+	UpdatedDt     time.Time       `json:"updated_dt"`
+	TransactionMeta
+}
+
+// ScheduledTransferFilter - conditions of scheduled transfers search, zero values mean no condition.
+// Account is matched on both sides of transfer.
+type ScheduledTransferFilter struct {
+	AccountId int64
+	Status    ScheduledStatus
+	Limit     uint64
+	Offset    uint64
+}
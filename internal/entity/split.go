@@ -0,0 +1,19 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// SplitShare - share of payee in split transfer, it is percent of amount or fixed amount.
+// Amount of percent share is calculated by split.
+type SplitShare struct {
+	AccountId int64           `json:"account_id"`
+	Percent   decimal.Decimal `json:"percent" swaggertype:"string" example:"70"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"700"`
+}
+
+// SplitTransfer - result of split transfer: payer account after redeem and payee accounts with their shares
+// in order of shares.
+type SplitTransfer struct {
+	RedeemAccount   Account      `json:"redeem_account"`
+	AccrualAccounts []Account    `json:"accrual_accounts"`
+	Shares          []SplitShare `json:"shares"`
+}
@@ -2,13 +2,47 @@ package entity
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// TransactionMeta - reason of money movement: description for people, purpose code for grouping
+// and name of service which made the operation.
+type TransactionMeta struct {
+	Description string `json:"description,omitempty"`
+	Purpose     string `json:"purpose,omitempty"`
+	Source      string `json:"source,omitempty"`
+}
+
+// TransactionFilter - conditions of transaction history, zero values mean no condition.
+// Description is matched as case insensitive substring.
+type TransactionFilter struct {
+	Description string
+	Purpose     string
+	Source      string
+}
+
+// Transaction - posting of journal entry to account, memo of expired hold has zero amount and no journal entry.
+// Every leg of batch transfer has its own journal entry, entries of one batch have the same batch ID.
+// Fee of operation is posting of the same journal entry which points to transaction of operation,
+// cashback is posting of the same journal entry which points to campaign.
 type Transaction struct {
-	Id        int64     `json:"id"`
-	TransDt   time.Time `json:"trans_dt"`
-	AccountId int64     `json:"account_id"`
-	DocNum    int64     `json:"doc_num"`
-	Type      string    `json:"type"`
-	Amount    float64   `json:"amount"`
+	Id         int64               `json:"id"`
+	TransDt    time.Time           `json:"trans_dt"`
+	AccountId  int64               `json:"account_id"`
+	DocNum     int64               `json:"doc_num"`
+	Type       string              `json:"type"`
+	Amount     decimal.Decimal     `json:"amount" swaggertype:"string" example:"-16.25"`
+	Currency   string              `json:"currency"`
+	RateId     *int64              `json:"rate_id,omitempty"`
+	Rate       decimal.NullDecimal `json:"rate" swaggertype:"string" example:"0.016"`
+	Spread     decimal.NullDecimal `json:"spread" swaggertype:"string" example:"0.01"`
+	HoldId     *int64              `json:"hold_id,omitempty"`
+	JournalId  *int64              `json:"journal_id,omitempty"`
+	BatchId    *int64              `json:"batch_id,omitempty"`
+	ReversalOf *int64              `json:"reversal_of,omitempty"`
+	FeeOf      *int64              `json:"fee_of,omitempty"`
+	CampaignId *int64              `json:"campaign_id,omitempty"`
+	EscrowId   *int64              `json:"escrow_id,omitempty"`
+	TransactionMeta
 }
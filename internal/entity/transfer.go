@@ -0,0 +1,11 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// TransferLeg - leg of batch transfer, amount moves from redeem account to accrual account in their currency.
+type TransferLeg struct {
+	RedeemId  int64           `json:"redeem_id"`
+	AccrualId int64           `json:"accrual_id"`
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string" example:"100"`
+	TransactionMeta
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LimitPeriod - calendar period of velocity limit in UTC, week starts on Monday.
+type LimitPeriod string
+
+const (
+	// LimitDay - calendar day.
+	LimitDay LimitPeriod = "day"
+	// LimitWeek - calendar week.
+	LimitWeek LimitPeriod = "week"
+	// LimitMonth - calendar month.
+	LimitMonth LimitPeriod = "month"
+)
+
+// IsKnown - check that period is one of periods of velocity limits.
+func (p LimitPeriod) IsKnown() bool {
+	switch p {
+	case LimitDay, LimitWeek, LimitMonth:
+		return true
+	}
+
+	return false
+}
+
+// LimitKind - what is limited by velocity limit.
+type LimitKind string
+
+const (
+	// LimitAmount - total amount of debits in period.
+	LimitAmount LimitKind = "amount"
+	// LimitCount - number of debits in period.
+	LimitCount LimitKind = "count"
+)
+
+// VelocityLimit - limit of debits of account in currency per calendar period: total redeem and transfer-out
+// amount and number of debits, nil max means no limit of it. Nil account means default limit of all accounts
+// in currency, and the limit of exact account wins.
+type VelocityLimit struct {
+	Id        int64               `json:"id"`
+	AccountId *int64              `json:"account_id,omitempty"`
+	Currency  string              `json:"currency"`
+	Period    LimitPeriod         `json:"period"`
+	MaxAmount decimal.NullDecimal `json:"max_amount" swaggertype:"string" example:"50000"`
+	MaxCount  *int64              `json:"max_count,omitempty" example:"20"`
+	Active    bool                `json:"active"`
+	CreatedDt time.Time           `json:"created_dt"`
+}
@@ -2,29 +2,66 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
 
 	"github.com/cut4cut/avito-test-work/internal/entity"
 )
 
+const (
+	// _ownerIdMaxLen - max length of external owner ID.
+	_ownerIdMaxLen = 64
+	// _defaultPageLimit - limit of account search page when limit is not passed.
+	_defaultPageLimit = 50
+	// _maxPageLimit - max limit of account search page.
+	_maxPageLimit = 1000
+	// _idempotencyKeyMaxLen - max length of idempotency key.
+	_idempotencyKeyMaxLen = 128
+	// _descriptionMaxLen - max length of transaction description.
+	_descriptionMaxLen = 255
+	// _sourceMaxLen - max length of name of service which made the operation.
+	_sourceMaxLen = 64
+)
+
+// _purposePattern - format of purpose code of transaction.
+var _purposePattern = regexp.MustCompile(`^[a-z0-9_.-]{1,32}$`)
+
+// _accountSortColumns - columns accounts can be sorted by in search.
+var _accountSortColumns = map[string]bool{"id": true, "balance": true, "created_dt": true}
+
 // AccountUseCase - use case with account.
 type AccountUseCase struct {
-	repo AccountRepo
+	repo  AccountRepo
+	rates RateProvider
 }
 
 // New - create new account use case.
-func New(r AccountRepo) *AccountUseCase {
+func New(r AccountRepo, p RateProvider) *AccountUseCase {
 	return &AccountUseCase{
-		repo: r,
+		repo:  r,
+		rates: p,
 	}
 }
 
-func (uc *AccountUseCase) amountValidation(amount float64) (err error) {
-	if amount < 0 {
+func (uc *AccountUseCase) amountValidation(amount decimal.Decimal, currency string) (err error) {
+	cur, ok := entity.CurrencyByCode(currency)
+	if !ok {
+		err = ErrorUnknownCurrency
+	} else if amount.IsNegative() {
 		err = ErrorAmountIsNegative
-	} else if amount == 0 {
+	} else if amount.IsZero() {
 		err = ErrorAmountIsZero
+	} else if !cur.Fits(amount) {
+		err = ErrorAmountPrecision
 	}
 
 	return
@@ -40,9 +77,116 @@ func (uc *AccountUseCase) idValidation(id int64) (err error) {
 	return
 }
 
-// Create - create new account with default values.
-func (uc *AccountUseCase) Create(ctx context.Context) (acc entity.Account, err error) {
-	acc, err = uc.repo.Create(ctx)
+func (uc *AccountUseCase) ownerIdValidation(ownerId string) (err error) {
+	if len(ownerId) > _ownerIdMaxLen {
+		err = ErrorOwnerIdTooLong
+	}
+
+	return
+}
+
+func (uc *AccountUseCase) refValidation(ref entity.AccountRef) error {
+	if !ref.IsOwner() {
+		return uc.idValidation(ref.Id)
+	}
+
+	if !ref.Wallet.IsKnown() {
+		return ErrorUnknownWallet
+	}
+
+	return uc.ownerIdValidation(ref.OwnerId)
+}
+
+func (uc *AccountUseCase) metaValidation(meta entity.TransactionMeta) (err error) {
+	if utf8.RuneCountInString(meta.Description) > _descriptionMaxLen {
+		err = ErrorDescriptionTooLong
+	} else if meta.Purpose != "" && !_purposePattern.MatchString(meta.Purpose) {
+		err = ErrorIncorrectPurpose
+	} else if utf8.RuneCountInString(meta.Source) > _sourceMaxLen {
+		err = ErrorSourceTooLong
+	}
+
+	return
+}
+
+// systemAccount - system account of operation by code, empty code means cash-in for accrual and cash-out for redeem.
+func (uc *AccountUseCase) systemAccount(code string, amount decimal.Decimal) (entity.SystemAccount, error) {
+	if code == "" {
+		if amount.IsNegative() {
+			return entity.SystemCashOut, nil
+		}
+		return entity.SystemCashIn, nil
+	}
+
+	system, ok := entity.SystemAccountByCode(code)
+	if !ok {
+		return system, ErrorUnknownSystemAccount
+	}
+
+	return system, nil
+}
+
+// idempotencyKey - idempotency key in scope of operation and caller with hash of request payload,
+// nil when key is not passed.
+func (uc *AccountUseCase) idempotencyKey(key, scope string, payload ...interface{}) (*entity.IdempotencyKey, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	if len(key) > _idempotencyKeyMaxLen {
+		return nil, ErrorIdempotencyKeyTooLong
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v", payload)))
+
+	return &entity.IdempotencyKey{Scope: scope, Key: key, Hash: hex.EncodeToString(hash[:])}, nil
+}
+
+// idempotencyError - error of idempotency conflict in terms of use case.
+func idempotencyError(err error) error {
+	if errors.Is(err, entity.ErrIdempotencyConflict) {
+		return ErrorIdempotencyConflict
+	}
+
+	return err
+}
+
+// get - get account by ID or by external owner ID and wallet.
+func (uc *AccountUseCase) get(ctx context.Context, ref entity.AccountRef) (entity.Account, error) {
+	if ref.IsOwner() {
+		return uc.repo.GetByOwner(ctx, ref.OwnerId, ref.Wallet)
+	}
+
+	return uc.repo.GetById(ctx, ref.Id)
+}
+
+// Create - create new account in currency with default values, external owner ID is optional.
+// Owner can have one account of each wallet, main wallet by default.
+func (uc *AccountUseCase) Create(ctx context.Context, currency, ownerId, walletValue string) (acc entity.Account, err error) {
+	err = uc.ownerIdValidation(ownerId)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - Create - uc.ownerIdValidation: %w", err)
+	}
+
+	wallet := entity.WalletMain
+	if walletValue != "" {
+		wallet = entity.Wallet(strings.ToLower(walletValue))
+	}
+
+	if !wallet.IsKnown() {
+		return acc, fmt.Errorf("AccountUseCase - Create - validation: %w", ErrorUnknownWallet)
+	}
+
+	if currency == "" {
+		currency = entity.DefaultCurrency
+	}
+
+	cur, ok := entity.CurrencyByCode(currency)
+	if !ok {
+		return acc, fmt.Errorf("AccountUseCase - Create - entity.CurrencyByCode: %w", ErrorUnknownCurrency)
+	}
+
+	acc, err = uc.repo.Create(ctx, cur.Code, ownerId, wallet)
 	if err != nil {
 		return acc, fmt.Errorf("AccountUseCase - Create - uc.repo.Create: %w", err)
 	}
@@ -50,74 +194,316 @@ func (uc *AccountUseCase) Create(ctx context.Context) (acc entity.Account, err e
 	return
 }
 
-// GetById - get account's values by ID.
-func (uc *AccountUseCase) GetById(ctx context.Context, id int64) (acc entity.Account, err error) {
-	err = uc.idValidation(id)
+// GetById - get account's values by ID or by external owner ID.
+func (uc *AccountUseCase) GetById(ctx context.Context, ref entity.AccountRef) (acc entity.Account, err error) {
+	err = uc.refValidation(ref)
 	if err != nil {
-		return acc, fmt.Errorf("AccountUseCase - GetById - uc.idValidation: %w", err)
+		return acc, fmt.Errorf("AccountUseCase - GetById - uc.refValidation: %w", err)
 	}
 
-	acc, err = uc.repo.GetById(ctx, id)
+	acc, err = uc.get(ctx, ref)
 	if err != nil {
-		return acc, fmt.Errorf("AccountUseCase - GetById - uc.repo.GetById: %w", err)
+		return acc, fmt.Errorf("AccountUseCase - GetById - uc.get: %w", err)
 	}
 
 	return
 }
 
-// UpdBalance - update account's balance.
-func (uc *AccountUseCase) UpdBalance(ctx context.Context, id int64, amount float64) (acc entity.Account, err error) {
-	err = uc.idValidation(id)
+// GetWallets - get all wallets of owner.
+func (uc *AccountUseCase) GetWallets(ctx context.Context, ownerId string) (accs []entity.Account, err error) {
+	if ownerId == "" {
+		return accs, fmt.Errorf("AccountUseCase - GetWallets - validation: %w", ErrorOwnerIdIsEmpty)
+	}
+
+	err = uc.ownerIdValidation(ownerId)
+	if err != nil {
+		return accs, fmt.Errorf("AccountUseCase - GetWallets - uc.ownerIdValidation: %w", err)
+	}
+
+	accs, err = uc.repo.GetWallets(ctx, ownerId)
+	if err != nil {
+		return accs, fmt.Errorf("AccountUseCase - GetWallets - uc.repo.GetWallets: %w", err)
+	}
+
+	return
+}
+
+func (uc *AccountUseCase) filterValidation(f entity.AccountFilter) (err error) {
+	if !_accountSortColumns[f.Sort] {
+		err = ErrorUnknownSort
+	} else if f.Limit > _maxPageLimit {
+		err = ErrorLimitTooLarge
+	} else if f.Status != "" && !f.Status.IsKnown() {
+		err = ErrorUnknownStatus
+	} else if f.MinBalance.Valid && f.MaxBalance.Valid && f.MinBalance.Decimal.GreaterThan(f.MaxBalance.Decimal) {
+		err = ErrorBalanceRange
+	} else if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && f.CreatedFrom.After(f.CreatedTo) {
+		err = ErrorDateRange
+	}
+
+	return
+}
+
+// accountCursor - cursor pointing to account in search sorted by column.
+func accountCursor(acc entity.Account, sort string) entity.AccountCursor {
+	c := entity.AccountCursor{Id: acc.Id}
+
+	switch sort {
+	case "balance":
+		c.Value = acc.Balance.String()
+	case "created_dt":
+		c.Value = acc.CreatedDt.Format(time.RFC3339Nano)
+	default:
+		c.Value = strconv.FormatInt(acc.Id, 10)
+	}
+
+	return c
+}
+
+// List - find accounts by filter. Page starts after cursor of previous page, empty cursor means the first page.
+func (uc *AccountUseCase) List(ctx context.Context, f entity.AccountFilter, cursor string) (page entity.AccountPage, err error) {
+	if f.Sort == "" {
+		f.Sort = "id"
+	}
+
+	if f.Limit == 0 {
+		f.Limit = _defaultPageLimit
+	}
+
+	if f.Currency != "" {
+		cur, ok := entity.CurrencyByCode(f.Currency)
+		if !ok {
+			return page, fmt.Errorf("AccountUseCase - List - entity.CurrencyByCode: %w", ErrorUnknownCurrency)
+		}
+
+		f.Currency = cur.Code
+	}
+
+	err = uc.filterValidation(f)
+	if err != nil {
+		return page, fmt.Errorf("AccountUseCase - List - uc.filterValidation: %w", err)
+	}
+
+	if cursor != "" {
+		after, err := entity.DecodeAccountCursor(cursor)
+		if err != nil {
+			return page, fmt.Errorf("AccountUseCase - List - entity.DecodeAccountCursor: %w", ErrorBadCursor)
+		}
+
+		if _, err = after.SortValue(f.Sort); err != nil {
+			return page, fmt.Errorf("AccountUseCase - List - after.SortValue: %w", ErrorBadCursor)
+		}
+
+		f.After = &after
+	}
+
+	limit := f.Limit
+	f.Limit++
+
+	page.Accounts, page.Total, err = uc.repo.List(ctx, f)
+	if err != nil {
+		return page, fmt.Errorf("AccountUseCase - List - uc.repo.List: %w", err)
+	}
+
+	if uint64(len(page.Accounts)) > limit {
+		page.Accounts = page.Accounts[:limit]
+		page.NextCursor = accountCursor(page.Accounts[limit-1], f.Sort).Encode()
+	}
+
+	return
+}
+
+// ConvertBalance - get account's values with balance converted to currency.
+func (uc *AccountUseCase) ConvertBalance(ctx context.Context, ref entity.AccountRef, currency string) (acc entity.Account, conv entity.Conversion, err error) {
+	cur, ok := entity.CurrencyByCode(currency)
+	if !ok {
+		return acc, conv, fmt.Errorf("AccountUseCase - ConvertBalance - entity.CurrencyByCode: %w", ErrorUnknownCurrency)
+	}
+
+	acc, err = uc.GetById(ctx, ref)
+	if err != nil {
+		return acc, conv, fmt.Errorf("AccountUseCase - ConvertBalance - uc.GetById: %w", err)
+	}
+
+	rate := entity.Rate{Base: acc.Currency, Quote: cur.Code, Value: decimal.NewFromInt(1), ValidFrom: time.Now()}
+	if acc.Currency != cur.Code {
+		rate, err = uc.rates.Rate(ctx, acc.Currency, cur.Code)
+		if err != nil {
+			return acc, conv, fmt.Errorf("AccountUseCase - ConvertBalance - uc.rates.Rate: %w", err)
+		}
+	}
+
+	conv = entity.Conversion{
+		Currency: cur.Code,
+		Balance:  cur.Round(acc.Balance.Mul(rate.Value)),
+		Rate:     rate.Value,
+		RateId:   rate.Id,
+		RateDt:   rate.ValidFrom,
+	}
+
+	return
+}
+
+// UpdBalance - update account's balance. Currency of operation is optional and must match the account,
+// the first accrual to unknown owner creates the account in the currency of operation.
+// System account is the other side of operation, cash-in for accrual and cash-out for redeem by default.
+// Meta tells why money moved and is returned with history.
+// Retry with the same idempotency key returns the original account without second update.
+func (uc *AccountUseCase) UpdBalance(ctx context.Context, ref entity.AccountRef, amount decimal.Decimal, currency, systemCode string, meta entity.TransactionMeta, idempotencyKey string) (acc entity.Account, err error) {
+	err = uc.refValidation(ref)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.refValidation: %w", err)
+	}
+
+	err = uc.metaValidation(meta)
 	if err != nil {
-		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.idValidation: %w", err)
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.metaValidation: %w", err)
 	}
 
-	if amount == 0 {
-		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.idValidation: %w", ErrorAmountIsZero)
+	system, err := uc.systemAccount(systemCode, amount)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.systemAccount: %w", err)
+	}
+
+	acc, err = uc.get(ctx, ref)
+	switch {
+	case errors.Is(err, entity.ErrAccountNotFound) && amount.IsPositive():
+		acc.Currency = entity.DefaultCurrency
+		if currency != "" {
+			acc.Currency = currency
+		}
+	case err != nil:
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.get: %w", err)
+	case currency != "" && !strings.EqualFold(currency, acc.Currency):
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - validation: %w", ErrorOperationCurrency)
 	}
 
-	acc, err = uc.repo.UpdBalance(ctx, id, -999, amount)
+	err = uc.amountValidation(amount.Abs(), acc.Currency)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.amountValidation: %w", err)
+	}
+
+	cur, _ := entity.CurrencyByCode(acc.Currency)
+
+	key, err := uc.idempotencyKey(idempotencyKey, "balance:"+ref.String(), "UpdBalance", ref, amount, cur.Code, system, meta)
 	if err != nil {
-		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.repo.UpdBalance: %w", err)
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.idempotencyKey: %w", err)
+	}
+
+	acc, err = uc.repo.UpdBalance(ctx, ref, amount, cur.Code, system, meta, key)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - UpdBalance - uc.repo.UpdBalance: %w", idempotencyError(err))
 	}
 
 	return
 }
 
 // TransferAmount - transfer amount of money from redeem account to accrual account.
-func (uc *AccountUseCase) TransferAmount(ctx context.Context, redeemId, accrId int64, amount float64) (accrAcc, redeemAcc entity.Account, err error) {
-	if accrId == redeemId {
+// Accounts in different currencies are rejected unless conversion is requested,
+// then the amount is converted with current rate from rate history.
+// Accrual account of unknown owner is created in currency of redeem account. Meta is written to both legs.
+// Retry with the same idempotency key returns the original accounts without second transfer.
+func (uc *AccountUseCase) TransferAmount(ctx context.Context, redeemRef, accrRef entity.AccountRef, amount decimal.Decimal, convertValue string, meta entity.TransactionMeta, idempotencyKey string) (accrAcc, redeemAcc entity.Account, err error) {
+	if accrRef == redeemRef {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - validation: %w", ErrorSameRedeemAccrId)
+	}
+
+	err = uc.refValidation(redeemRef)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.refValidation: %w", err)
+	}
+
+	err = uc.refValidation(accrRef)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.refValidation: %w", err)
+	}
+
+	err = uc.metaValidation(meta)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.metaValidation: %w", err)
+	}
+
+	redeemAcc, err = uc.get(ctx, redeemRef)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.get: %w", err)
+	}
+
+	accrAcc, err = uc.get(ctx, accrRef)
+	if errors.Is(err, entity.ErrAccountNotFound) {
+		accrAcc.Currency = redeemAcc.Currency
+	} else if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.get: %w", err)
+	}
+
+	if accrAcc.Id == redeemAcc.Id {
 		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - validation: %w", ErrorSameRedeemAccrId)
 	}
 
-	err = uc.amountValidation(amount)
+	err = uc.amountValidation(amount, redeemAcc.Currency)
 	if err != nil {
 		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.amountValidation: %w", err)
 	}
 
-	err = uc.idValidation(redeemId)
+	convert := strings.ToLower(convertValue) == "true"
+
+	key, err := uc.idempotencyKey(idempotencyKey, "transfer:"+redeemRef.String(), "TransferAmount", redeemRef, accrRef, amount, convert, meta)
 	if err != nil {
-		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.idValidation: %w", err)
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.idempotencyKey: %w", err)
+	}
+
+	if accrAcc.Currency == redeemAcc.Currency {
+		accrAcc, redeemAcc, err = uc.repo.TransferAmount(ctx, redeemRef, accrRef, amount, amount, nil, meta, key)
+		if err != nil {
+			return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.repo.TransferAmount: %w", idempotencyError(err))
+		}
+
+		return
 	}
 
-	err = uc.idValidation(accrId)
+	if !convert {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - validation: %w", ErrorCurrencyMismatch)
+	}
+
+	rate, err := uc.rates.Rate(ctx, redeemAcc.Currency, accrAcc.Currency)
 	if err != nil {
-		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.idValidation: %w", err)
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.rates.Rate: %w", err)
 	}
 
-	accrAcc, redeemAcc, err = uc.repo.TransferAmount(ctx, redeemId, accrId, amount)
+	if rate.Id == 0 {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - validation: %w", ErrorRateNotStored)
+	}
+
+	accrCur, _ := entity.CurrencyByCode(accrAcc.Currency)
+	accrAmount := accrCur.Round(rate.Convert(amount))
+
+	err = uc.amountValidation(accrAmount, accrAcc.Currency)
 	if err != nil {
-		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.repo.TransferAmount: %w", err)
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.amountValidation: %w", err)
+	}
+
+	accrAcc, redeemAcc, err = uc.repo.TransferAmount(ctx, redeemRef, accrRef, amount, accrAmount, &rate, meta, key)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountUseCase - TransferAmount - uc.repo.TransferAmount: %w", idempotencyError(err))
 	}
 
 	return
 }
 
-// GetHistory - get history of transaction.
-func (uc *AccountUseCase) GetHistory(ctx context.Context, id int64, limit, offset uint64, sort, isDecreasingValue string) (trans []*entity.Transaction, err error) {
-	err = uc.idValidation(id)
+// GetHistory - get history of transaction, filter selects transactions by meta.
+func (uc *AccountUseCase) GetHistory(ctx context.Context, ref entity.AccountRef, limit, offset uint64, sort, isDecreasingValue string, f entity.TransactionFilter) (trans []*entity.Transaction, err error) {
+	err = uc.refValidation(ref)
 	if err != nil {
-		return trans, fmt.Errorf("AccountUseCase - GetHistory - uc.idValidation: %w", err)
+		return trans, fmt.Errorf("AccountUseCase - GetHistory - uc.refValidation: %w", err)
+	}
+
+	id := ref.Id
+	if ref.IsOwner() {
+		acc, err := uc.repo.GetByOwner(ctx, ref.OwnerId, ref.Wallet)
+		if err != nil {
+			return trans, fmt.Errorf("AccountUseCase - GetHistory - uc.repo.GetByOwner: %w", err)
+		}
+
+		id = acc.Id
 	}
 
 	if sort == "" {
@@ -129,10 +515,109 @@ func (uc *AccountUseCase) GetHistory(ctx context.Context, id int64, limit, offse
 		isDecreasing = true
 	}
 
-	trans, err = uc.repo.GetHistory(ctx, id, limit, offset, sort, isDecreasing)
+	trans, err = uc.repo.GetHistory(ctx, id, limit, offset, sort, isDecreasing, f)
 	if err != nil {
 		return trans, fmt.Errorf("AccountUseCase - GetHistory - uc.repo.GetHistory: %w", err)
 	}
 
 	return
 }
+
+// changeStatus - move account to status with reason.
+func (uc *AccountUseCase) changeStatus(ctx context.Context, id int64, to entity.AccountStatus, reason string, sweepId int64) (acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return acc, ErrorReasonIsEmpty
+	}
+
+	return uc.repo.ChangeStatus(ctx, id, to, reason, sweepId)
+}
+
+// Freeze - freeze account, frozen account accepts credits only.
+func (uc *AccountUseCase) Freeze(ctx context.Context, id int64, reason string) (acc entity.Account, err error) {
+	acc, err = uc.changeStatus(ctx, id, entity.AccountFrozen, reason, 0)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - Freeze - uc.changeStatus: %w", err)
+	}
+
+	return
+}
+
+// Unfreeze - make frozen account active.
+func (uc *AccountUseCase) Unfreeze(ctx context.Context, id int64, reason string) (acc entity.Account, err error) {
+	acc, err = uc.changeStatus(ctx, id, entity.AccountActive, reason, 0)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - Unfreeze - uc.changeStatus: %w", err)
+	}
+
+	return
+}
+
+// Close - close account. Account with non-zero balance is closed only with sweep of the balance
+// to account with sweepId, 0 means no sweep.
+func (uc *AccountUseCase) Close(ctx context.Context, id int64, reason string, sweepId int64) (acc entity.Account, err error) {
+	if sweepId != 0 {
+		err = uc.idValidation(sweepId)
+		if err != nil {
+			return acc, fmt.Errorf("AccountUseCase - Close - uc.idValidation: %w", err)
+		}
+
+		if sweepId == id {
+			return acc, fmt.Errorf("AccountUseCase - Close - validation: %w", ErrorSameSweepId)
+		}
+	}
+
+	acc, err = uc.changeStatus(ctx, id, entity.AccountClosed, reason, sweepId)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - Close - uc.changeStatus: %w", err)
+	}
+
+	return
+}
+
+// GetStatusHistory - get history of account's status changes.
+func (uc *AccountUseCase) GetStatusHistory(ctx context.Context, id int64) (changes []*entity.StatusChange, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return changes, fmt.Errorf("AccountUseCase - GetStatusHistory - uc.idValidation: %w", err)
+	}
+
+	changes, err = uc.repo.GetStatusHistory(ctx, id)
+	if err != nil {
+		return changes, fmt.Errorf("AccountUseCase - GetStatusHistory - uc.repo.GetStatusHistory: %w", err)
+	}
+
+	return
+}
+
+// SetCreditLimit - set credit limit of account, account can go negative up to the limit.
+func (uc *AccountUseCase) SetCreditLimit(ctx context.Context, id int64, limit decimal.Decimal) (acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - SetCreditLimit - uc.idValidation: %w", err)
+	}
+
+	if limit.IsNegative() {
+		return acc, fmt.Errorf("AccountUseCase - SetCreditLimit - validation: %w", ErrorLimitIsNegative)
+	}
+
+	acc, err = uc.repo.GetById(ctx, id)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - SetCreditLimit - uc.repo.GetById: %w", err)
+	}
+
+	if cur, _ := entity.CurrencyByCode(acc.Currency); !cur.Fits(limit) {
+		return acc, fmt.Errorf("AccountUseCase - SetCreditLimit - validation: %w", ErrorAmountPrecision)
+	}
+
+	acc, err = uc.repo.SetCreditLimit(ctx, id, limit)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - SetCreditLimit - uc.repo.SetCreditLimit: %w", err)
+	}
+
+	return
+}
@@ -2,6 +2,8 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"testing"
@@ -9,25 +11,43 @@ import (
 	"github.com/cut4cut/avito-test-work/internal/entity"
 	"github.com/cut4cut/avito-test-work/internal/usecase"
 	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
 )
 
 func TestAccountUseCase_Create(t *testing.T) {
 	type fields struct {
 		ctx         context.Context
 		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
 	}
 	tests := []struct {
 		name    string
 		prepare func(f *fields)
+		arg     string
 		wantErr bool
 	}{
 		{
 			name: "Case of correct work",
 			prepare: func(f *fields) {
-				f.accountRepo.EXPECT().Create(f.ctx).Return(entity.Account{Id: 1, Balance: 0.0, CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().Create(f.ctx, "RUB", "", entity.WalletMain).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
 			},
+			arg:     "",
 			wantErr: false,
 		},
+		{
+			name: "Case of correct work: currency in lower case",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().Create(f.ctx, "USD", "", entity.WalletMain).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "USD", CreatedDt: time.Now()}, nil)
+			},
+			arg:     "usd",
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg:     "XYZ",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -36,13 +56,14 @@ func TestAccountUseCase_Create(t *testing.T) {
 			f := fields{
 				ctx:         context.Background(),
 				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
 			}
 
-			uc := usecase.New(f.accountRepo)
-			if acc, err := uc.Create(f.ctx); (err != nil) != tt.wantErr {
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.Create(f.ctx, tt.arg, "", ""); (err != nil) != tt.wantErr {
 				t.Errorf("Create() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
 			}
 		})
@@ -53,31 +74,49 @@ func TestAccountUseCase_GetById(t *testing.T) {
 	type fields struct {
 		ctx         context.Context
 		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
 	}
 	tests := []struct {
 		name    string
 		prepare func(f *fields)
-		arg     int64
+		arg     entity.AccountRef
 		wantErr bool
 	}{
 		{
 			name: "Case of correct work",
 			prepare: func(f *fields) {
-				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: 0.0, CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, CreatedDt: time.Now()}, nil)
+			},
+			arg:     entity.AccountRef{Id: 1},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: account by owner ID",
+			prepare: func(f *fields) {
+				ownerId := "user-42"
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, ownerId, entity.WalletMain).Return(entity.Account{Id: 1, OwnerId: &ownerId, Balance: decimal.Zero, CreatedDt: time.Now()}, nil)
 			},
-			arg:     1,
+			arg:     entity.AccountRef{OwnerId: "user-42", Wallet: entity.WalletMain},
 			wantErr: false,
 		},
+		{
+			name: "Case of incorrect work: owner is unknown",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-42", entity.WalletMain).Return(entity.Account{}, entity.ErrAccountNotFound)
+			},
+			arg:     entity.AccountRef{OwnerId: "user-42", Wallet: entity.WalletMain},
+			wantErr: true,
+		},
 		{
 			name:    "Case of incorrect work: ID is negative",
 			prepare: func(f *fields) {},
-			arg:     -4,
+			arg:     entity.AccountRef{Id: -4},
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: ID is zero",
 			prepare: func(f *fields) {},
-			arg:     0,
+			arg:     entity.AccountRef{Id: 0},
 			wantErr: true,
 		},
 	}
@@ -88,12 +127,13 @@ func TestAccountUseCase_GetById(t *testing.T) {
 			f := fields{
 				ctx:         context.Background(),
 				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
 			}
 
-			uc := usecase.New(f.accountRepo)
+			uc := usecase.New(f.accountRepo, f.rates)
 			if acc, err := uc.GetById(f.ctx, tt.arg); (err != nil) != tt.wantErr {
 				t.Errorf("GetById() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
 			}
@@ -101,46 +141,352 @@ func TestAccountUseCase_GetById(t *testing.T) {
 	}
 }
 
+func TestAccountUseCase_GetWallets(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg     string
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetWallets(f.ctx, "user-42").Return([]entity.Account{
+					{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", Wallet: entity.WalletMain, CreatedDt: time.Now()},
+					{Id: 2, Balance: decimal.NewFromInt(15), Currency: "RUB", Wallet: entity.WalletBonus, CreatedDt: time.Now()},
+				}, nil)
+			},
+			arg:     "user-42",
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: owner ID is empty",
+			prepare: func(f *fields) {},
+			arg:     "",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: owner ID is too long",
+			prepare: func(f *fields) {},
+			arg:     strings.Repeat("x", 65),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if accs, err := uc.GetWallets(f.ctx, tt.arg); (err != nil) != tt.wantErr {
+				t.Errorf("GetWallets() accounts=%v error = %v, wantErr %v", accs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_ConvertBalance(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    entity.AccountRef
+		arg2    string
+		want    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.rates.EXPECT().Rate(f.ctx, "RUB", "USD").Return(entity.Rate{Base: "RUB", Quote: "USD", Value: decimal.RequireFromString("0.016"), ValidFrom: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    "USD",
+			want:    decimal.RequireFromString("1.6"),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: rounding to minor unit of currency",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.RequireFromString("100.55"), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.rates.EXPECT().Rate(f.ctx, "RUB", "JPY").Return(entity.Rate{Base: "RUB", Quote: "JPY", Value: decimal.RequireFromString("2.179"), ValidFrom: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    "jpy",
+			want:    decimal.NewFromInt(219),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: same currency",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    "RUB",
+			want:    decimal.NewFromInt(100),
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    "XYZ",
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: rate not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.rates.EXPECT().Rate(f.ctx, "RUB", "KZT").Return(entity.Rate{}, errors.New("exchange rate not found"))
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    "KZT",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			acc, conv, err := uc.ConvertBalance(f.ctx, tt.arg1, tt.arg2)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ConvertBalance() account=%v conversion=%v error = %v, wantErr %v", acc, conv, err, tt.wantErr)
+			}
+			if err == nil && !conv.Balance.Equal(tt.want) {
+				t.Errorf("ConvertBalance() balance = %v, want %v", conv.Balance, tt.want)
+			}
+		})
+	}
+}
+
 func TestAccountUseCase_UpdBalance(t *testing.T) {
 	type fields struct {
 		ctx         context.Context
 		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
 	}
 	tests := []struct {
 		name    string
 		prepare func(f *fields)
-		arg1    int64
-		arg2    float64
+		arg1    entity.AccountRef
+		arg2    decimal.Decimal
+		arg3    string
+		arg4    string
+		arg5    entity.TransactionMeta
+		arg6    string
 		wantErr bool
 	}{
 		{
 			name: "Case of correct work",
 			prepare: func(f *fields) {
-				f.accountRepo.EXPECT().UpdBalance(f.ctx, int64(1), int64(-999), float64(25.0)).Return(entity.Account{Id: 1, Balance: 25.0, CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(25), "RUB", entity.SystemCashIn, entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", CreatedDt: time.Now()}, nil)
 			},
-			arg1:    1,
-			arg2:    25,
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
 			wantErr: false,
 		},
 		{
-			name:    "Case of incorrect work: amount is zero",
+			name: "Case of correct work: operation with meta",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(25), "RUB", entity.SystemCashIn, entity.TransactionMeta{Description: "Cashback for order 15", Purpose: "cashback", Source: "loyalty"}, (*entity.IdempotencyKey)(nil)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg5:    entity.TransactionMeta{Description: "Cashback for order 15", Purpose: "cashback", Source: "loyalty"},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: operation through suspense system account",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(-25), "RUB", entity.SystemSuspense, entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(5), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(-25),
+			arg4:    "Suspense",
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: exchange system account is not selectable",
 			prepare: func(f *fields) {},
-			arg1:    1,
-			arg2:    0,
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg4:    "exchange",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: incorrect purpose code",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg5:    entity.TransactionMeta{Purpose: "Order payment"},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: description is too long",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg5:    entity.TransactionMeta{Description: strings.Repeat("д", 256)},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: source is too long",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg5:    entity.TransactionMeta{Source: strings.Repeat("s", 65)},
+			wantErr: true,
+		},
+		{
+			name: "Case of correct work: source length is counted in characters",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(25), "RUB", entity.SystemCashIn, entity.TransactionMeta{Source: strings.Repeat("с", 64)}, (*entity.IdempotencyKey)(nil)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg5:    entity.TransactionMeta{Source: strings.Repeat("с", 64)},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: request with idempotency key",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(25), "RUB", entity.SystemCashIn, entity.TransactionMeta{}, gomock.Not(gomock.Nil())).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg6:    "7f1c2d9e-payment-1",
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: idempotency key is used with another request",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{Id: 1}, decimal.NewFromInt(25), "RUB", entity.SystemCashIn, entity.TransactionMeta{}, gomock.Not(gomock.Nil())).Return(entity.Account{}, entity.ErrIdempotencyConflict)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg6:    "7f1c2d9e-payment-1",
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: idempotency key is too long",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg6:    strings.Repeat("k", 129),
+			wantErr: true,
+		},
+		{
+			name: "Case of correct work: first accrual to unknown owner creates account",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-42", entity.WalletMain).Return(entity.Account{}, entity.ErrAccountNotFound)
+				f.accountRepo.EXPECT().UpdBalance(f.ctx, entity.AccountRef{OwnerId: "user-42", Wallet: entity.WalletMain}, decimal.NewFromInt(25), "USD", entity.SystemCashIn, entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(entity.Account{Id: 3, Balance: decimal.NewFromInt(25), Currency: "USD", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{OwnerId: "user-42", Wallet: entity.WalletMain},
+			arg2:    decimal.NewFromInt(25),
+			arg3:    "usd",
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: redeem from unknown owner",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-42", entity.WalletMain).Return(entity.Account{}, entity.ErrAccountNotFound)
+			},
+			arg1:    entity.AccountRef{OwnerId: "user-42", Wallet: entity.WalletMain},
+			arg2:    decimal.NewFromInt(-25),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: currency does not match the account",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(25),
+			arg3:    "USD",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: owner ID is too long",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountRef{OwnerId: strings.Repeat("x", 65), Wallet: entity.WalletMain},
+			arg2:    decimal.NewFromInt(25),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is zero",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.NewFromInt(0),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.RequireFromString("-25.001"),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: JPY amount has minor units",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "JPY", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    decimal.RequireFromString("25.5"),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: ID is negative",
 			prepare: func(f *fields) {},
-			arg1:    -89,
-			arg2:    25,
+			arg1:    entity.AccountRef{Id: -89},
+			arg2:    decimal.NewFromInt(25),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: ID is zero",
 			prepare: func(f *fields) {},
-			arg1:    0,
-			arg2:    25,
+			arg1:    entity.AccountRef{Id: 0},
+			arg2:    decimal.NewFromInt(25),
 			wantErr: true,
 		},
 	}
@@ -151,13 +497,14 @@ func TestAccountUseCase_UpdBalance(t *testing.T) {
 			f := fields{
 				ctx:         context.Background(),
 				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
 			}
 
-			uc := usecase.New(f.accountRepo)
-			if acc, err := uc.UpdBalance(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.UpdBalance(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5, tt.arg6); (err != nil) != tt.wantErr {
 				t.Errorf("UpdBalance() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
 			}
 		})
@@ -168,74 +515,175 @@ func TestAccountUseCase_TransferAmount(t *testing.T) {
 	type fields struct {
 		ctx         context.Context
 		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
 	}
 	tests := []struct {
 		name    string
 		prepare func(f *fields)
-		arg1    int64
-		arg2    int64
-		arg3    float64
+		arg1    entity.AccountRef
+		arg2    entity.AccountRef
+		arg3    decimal.Decimal
+		arg4    string
+		arg5    entity.TransactionMeta
+		arg6    string
 		wantErr bool
 	}{
 		{
 			name: "Case of correct work",
 			prepare: func(f *fields) {
-				f.accountRepo.EXPECT().TransferAmount(f.ctx, int64(1), int64(2), float64(5.0)).Return(
-					entity.Account{Id: 1, Balance: 25.0, CreatedDt: time.Now()},
-					entity.Account{Id: 2, Balance: 30.0, CreatedDt: time.Now()},
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().TransferAmount(f.ctx, entity.AccountRef{Id: 1}, entity.AccountRef{Id: 2}, decimal.NewFromInt(5), decimal.NewFromInt(5), (*entity.Rate)(nil), entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(
+					entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", CreatedDt: time.Now()},
+					entity.Account{Id: 2, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()},
 					nil)
 			},
-			arg1:    1,
-			arg2:    2,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(5),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: accrual to unknown owner creates account",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-1", entity.WalletMain).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "USD", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-2", entity.WalletMain).Return(entity.Account{}, entity.ErrAccountNotFound)
+				f.accountRepo.EXPECT().TransferAmount(f.ctx, entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletMain}, entity.AccountRef{OwnerId: "user-2", Wallet: entity.WalletMain}, decimal.NewFromInt(5), decimal.NewFromInt(5), (*entity.Rate)(nil), entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(
+					entity.Account{Id: 3, Balance: decimal.NewFromInt(5), Currency: "USD", CreatedDt: time.Now()},
+					entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "USD", CreatedDt: time.Now()},
+					nil)
+			},
+			arg1:    entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletMain},
+			arg2:    entity.AccountRef{OwnerId: "user-2", Wallet: entity.WalletMain},
+			arg3:    decimal.NewFromInt(5),
+			arg4:    "true",
 			wantErr: false,
 		},
 		{
-			name:    "Case of incorrect work: amount is zero",
+			name: "Case of correct work: transfer between wallets of owner",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-1", entity.WalletMain).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", Wallet: entity.WalletMain, CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetByOwner(f.ctx, "user-1", entity.WalletBonus).Return(entity.Account{Id: 2, Balance: decimal.Zero, Currency: "RUB", Wallet: entity.WalletBonus, CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().TransferAmount(f.ctx, entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletMain}, entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletBonus}, decimal.NewFromInt(5), decimal.NewFromInt(5), (*entity.Rate)(nil), entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(
+					entity.Account{Id: 2, Balance: decimal.NewFromInt(5), Currency: "RUB", Wallet: entity.WalletBonus, CreatedDt: time.Now()},
+					entity.Account{Id: 1, Balance: decimal.NewFromInt(25), Currency: "RUB", Wallet: entity.WalletMain, CreatedDt: time.Now()},
+					nil)
+			},
+			arg1:    entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletMain},
+			arg2:    entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletBonus},
+			arg3:    decimal.NewFromInt(5),
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown wallet",
 			prepare: func(f *fields) {},
-			arg1:    1,
-			arg2:    2,
-			arg3:    0,
+			arg1:    entity.AccountRef{OwnerId: "user-1", Wallet: entity.WalletMain},
+			arg2:    entity.AccountRef{OwnerId: "user-1", Wallet: "savings"},
+			arg3:    decimal.NewFromInt(5),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is zero",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(0),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.RequireFromString("0.001"),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: currencies are different",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "USD", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(5),
+			wantErr: true,
+		},
+		{
+			name: "Case of correct work: currencies are different and conversion is requested",
+			prepare: func(f *fields) {
+				rate := entity.Rate{Id: 7, Base: "RUB", Quote: "USD", Value: decimal.RequireFromString("0.016"), Spread: decimal.RequireFromString("0.01"), ValidFrom: time.Now()}
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(300), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "USD", CreatedDt: time.Now()}, nil)
+				f.rates.EXPECT().Rate(f.ctx, "RUB", "USD").Return(rate, nil)
+				f.accountRepo.EXPECT().TransferAmount(f.ctx, entity.AccountRef{Id: 1}, entity.AccountRef{Id: 2}, decimal.NewFromInt(100), decimal.RequireFromString("1.58"), &rate, entity.TransactionMeta{}, (*entity.IdempotencyKey)(nil)).Return(
+					entity.Account{Id: 2, Balance: decimal.RequireFromString("21.58"), Currency: "USD", CreatedDt: time.Now()},
+					entity.Account{Id: 1, Balance: decimal.NewFromInt(200), Currency: "RUB", CreatedDt: time.Now()},
+					nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(100),
+			arg4:    "true",
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: rate is not stored in rate history",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(300), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Balance: decimal.NewFromInt(20), Currency: "USD", CreatedDt: time.Now()}, nil)
+				f.rates.EXPECT().Rate(f.ctx, "RUB", "USD").Return(entity.Rate{Base: "RUB", Quote: "USD", Value: decimal.RequireFromString("0.016"), ValidFrom: time.Now()}, nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(100),
+			arg4:    "true",
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: redeem ID is negative",
 			prepare: func(f *fields) {},
-			arg1:    -1,
-			arg2:    2,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: -1},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(5),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: redeem ID is zero",
 			prepare: func(f *fields) {},
-			arg1:    0,
-			arg2:    2,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: 0},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(5),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: accrual ID is negative",
 			prepare: func(f *fields) {},
-			arg1:    1,
-			arg2:    -2,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: -2},
+			arg3:    decimal.NewFromInt(5),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: accrual ID is zero",
 			prepare: func(f *fields) {},
-			arg1:    1,
-			arg2:    0,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    entity.AccountRef{Id: 0},
+			arg3:    decimal.NewFromInt(5),
 			wantErr: true,
 		},
 		{
 			name:    "Case of incorrect work: IDs are the same",
 			prepare: func(f *fields) {},
-			arg1:    2,
-			arg2:    2,
-			arg3:    5,
+			arg1:    entity.AccountRef{Id: 2},
+			arg2:    entity.AccountRef{Id: 2},
+			arg3:    decimal.NewFromInt(5),
 			wantErr: true,
 		},
 	}
@@ -246,13 +694,14 @@ func TestAccountUseCase_TransferAmount(t *testing.T) {
 			f := fields{
 				ctx:         context.Background(),
 				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
 			}
 
-			uc := usecase.New(f.accountRepo)
-			if accrAcc, redeemAcc, err := uc.TransferAmount(f.ctx, tt.arg1, tt.arg2, tt.arg3); (err != nil) != tt.wantErr {
+			uc := usecase.New(f.accountRepo, f.rates)
+			if accrAcc, redeemAcc, err := uc.TransferAmount(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5, tt.arg6); (err != nil) != tt.wantErr {
 				t.Errorf("UpdBalance() accrual account=%v redeem account=%v error = %v, wantErr %v", accrAcc, redeemAcc, err, tt.wantErr)
 			}
 		})
@@ -263,39 +712,56 @@ func TestAccountUseCase_GetHistory(t *testing.T) {
 	type fields struct {
 		ctx         context.Context
 		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
 	}
 	tests := []struct {
 		name    string
 		prepare func(f *fields)
-		arg1    int64
+		arg1    entity.AccountRef
 		arg2    uint64
 		arg3    uint64
 		arg4    string
 		arg5    string
+		arg6    entity.TransactionFilter
 		wantErr bool
 	}{
 		{
 			name: "Case of correct work",
 			prepare: func(f *fields) {
-				f.accountRepo.EXPECT().GetHistory(f.ctx, int64(1), uint64(3), uint64(0), "trans_dt", true).Return(
+				f.accountRepo.EXPECT().GetHistory(f.ctx, int64(1), uint64(3), uint64(0), "trans_dt", true, entity.TransactionFilter{}).Return(
 					[]*entity.Transaction{
-						&entity.Transaction{Id: 1, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: 5},
-						&entity.Transaction{Id: 2, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: 5},
-						&entity.Transaction{Id: 3, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: 5},
+						&entity.Transaction{Id: 1, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: decimal.NewFromInt(5)},
+						&entity.Transaction{Id: 2, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: decimal.NewFromInt(5)},
+						&entity.Transaction{Id: 3, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: decimal.NewFromInt(5)},
 					},
 					nil)
 			},
-			arg1:    1,
+			arg1:    entity.AccountRef{Id: 1},
 			arg2:    3,
 			arg3:    0,
 			arg4:    "trans_dt",
 			arg5:    "true",
 			wantErr: false,
 		},
+		{
+			name: "Case of correct work: history is filtered by purpose",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHistory(f.ctx, int64(1), uint64(3), uint64(0), "trans_dt", false, entity.TransactionFilter{Purpose: "cashback"}).Return(
+					[]*entity.Transaction{
+						&entity.Transaction{Id: 4, TransDt: time.Now(), AccountId: 1, DocNum: -101, Type: "accrual", Amount: decimal.NewFromInt(5), TransactionMeta: entity.TransactionMeta{Purpose: "cashback"}},
+					},
+					nil)
+			},
+			arg1:    entity.AccountRef{Id: 1},
+			arg2:    3,
+			arg3:    0,
+			arg6:    entity.TransactionFilter{Purpose: "cashback"},
+			wantErr: false,
+		},
 		{
 			name:    "Case of incorrect work: ID is negative",
 			prepare: func(f *fields) {},
-			arg1:    -1,
+			arg1:    entity.AccountRef{Id: -1},
 			arg2:    3,
 			arg3:    0,
 			arg4:    "trans_dt",
@@ -305,7 +771,7 @@ func TestAccountUseCase_GetHistory(t *testing.T) {
 		{
 			name:    "Case of incorrect work: ID is zero",
 			prepare: func(f *fields) {},
-			arg1:    0,
+			arg1:    entity.AccountRef{Id: 0},
 			arg2:    3,
 			arg3:    0,
 			arg4:    "trans_dt",
@@ -320,15 +786,351 @@ func TestAccountUseCase_GetHistory(t *testing.T) {
 			f := fields{
 				ctx:         context.Background(),
 				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
 			}
 			if tt.prepare != nil {
 				tt.prepare(&f)
 			}
 
-			uc := usecase.New(f.accountRepo)
-			if trans, err := uc.GetHistory(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5); (err != nil) != tt.wantErr {
+			uc := usecase.New(f.accountRepo, f.rates)
+			if trans, err := uc.GetHistory(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5, tt.arg6); (err != nil) != tt.wantErr {
 				t.Errorf("GetHistory() trans history=%v error = %v, wantErr %v", trans, err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestAccountUseCase_Freeze(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    string
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ChangeStatus(f.ctx, int64(1), entity.AccountFrozen, "fraud check", int64(0)).Return(entity.Account{Id: 1, Status: entity.AccountFrozen, StatusDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "fraud check",
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: reason is empty",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    " ",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    "fraud check",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.Freeze(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("Freeze() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_Close(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    string
+		arg3    int64
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ChangeStatus(f.ctx, int64(1), entity.AccountClosed, "client request", int64(0)).Return(entity.Account{Id: 1, Status: entity.AccountClosed, StatusDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "client request",
+			arg3:    0,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: sweep to another account",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ChangeStatus(f.ctx, int64(1), entity.AccountClosed, "client request", int64(2)).Return(entity.Account{Id: 1, Status: entity.AccountClosed, StatusDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "client request",
+			arg3:    2,
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: sweep to the same account",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "client request",
+			arg3:    1,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: sweep ID is negative",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "client request",
+			arg3:    -2,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: reason is empty",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "",
+			arg3:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.Close(f.ctx, tt.arg1, tt.arg2, tt.arg3); (err != nil) != tt.wantErr {
+				t.Errorf("Close() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_SetCreditLimit(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().SetCreditLimit(f.ctx, int64(1), decimal.NewFromInt(1000)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreditLimit: decimal.NewFromInt(1000), CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(1000),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: limit is removed",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreditLimit: decimal.NewFromInt(1000), CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().SetCreditLimit(f.ctx, int64(1), decimal.Zero).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.Zero,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: limit is less than debt",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(-500), Currency: "RUB", CreditLimit: decimal.NewFromInt(1000), CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().SetCreditLimit(f.ctx, int64(1), decimal.NewFromInt(100)).Return(entity.Account{}, entity.ErrLimitBelowDebt)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(100),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: JPY limit has minor units",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.Zero, Currency: "JPY", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.RequireFromString("100.5"),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: limit is negative",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(-100),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    decimal.NewFromInt(100),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.SetCreditLimit(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("SetCreditLimit() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_List(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	accs := []entity.Account{
+		{Id: 1, Balance: decimal.NewFromInt(10), Currency: "RUB", CreatedDt: time.Now()},
+		{Id: 2, Balance: decimal.NewFromInt(20), Currency: "RUB", CreatedDt: time.Now()},
+		{Id: 3, Balance: decimal.NewFromInt(30), Currency: "RUB", CreatedDt: time.Now()},
+	}
+	tests := []struct {
+		name       string
+		prepare    func(f *fields)
+		arg1       entity.AccountFilter
+		arg2       string
+		wantCursor entity.AccountCursor
+		wantErr    bool
+	}{
+		{
+			name: "Case of correct work: last page",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().List(f.ctx, entity.AccountFilter{Currency: "RUB", Sort: "id", Limit: 51}).Return(accs, int64(3), nil)
+			},
+			arg1:    entity.AccountFilter{Currency: "rub"},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: next page exists",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().List(f.ctx, entity.AccountFilter{Sort: "balance", IsDecreasing: true, Limit: 3}).Return(accs, int64(10), nil)
+			},
+			arg1:       entity.AccountFilter{Sort: "balance", IsDecreasing: true, Limit: 2},
+			wantCursor: entity.AccountCursor{Value: "20", Id: 2},
+			wantErr:    false,
+		},
+		{
+			name: "Case of correct work: page after cursor",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().List(f.ctx, entity.AccountFilter{Sort: "balance", Limit: 3, After: &entity.AccountCursor{Value: "20", Id: 2}}).Return(accs[2:], int64(3), nil)
+			},
+			arg1:    entity.AccountFilter{Sort: "balance", Limit: 2},
+			arg2:    entity.AccountCursor{Value: "20", Id: 2}.Encode(),
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown sort column",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountFilter{Sort: "owner_id; DROP TABLE account"},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown status",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountFilter{Status: "deleted"},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: min balance is greater than max balance",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountFilter{MinBalance: decimal.NewNullDecimal(decimal.NewFromInt(10)), MaxBalance: decimal.NewNullDecimal(decimal.NewFromInt(5))},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: limit is too large",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountFilter{Limit: 100000},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: incorrect cursor",
+			prepare: func(f *fields) {},
+			arg2:    "not a cursor",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: cursor value does not match sort column",
+			prepare: func(f *fields) {},
+			arg1:    entity.AccountFilter{Sort: "created_dt"},
+			arg2:    entity.AccountCursor{Value: "20", Id: 2}.Encode(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			page, err := uc.List(f.ctx, tt.arg1, tt.arg2)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() page=%v error = %v, wantErr %v", page, err, tt.wantErr)
+			}
+
+			if tt.wantCursor.Id != 0 && page.NextCursor != tt.wantCursor.Encode() {
+				t.Errorf("List() next cursor = %v, want %v", page.NextCursor, tt.wantCursor.Encode())
+			}
+		})
+	}
+}
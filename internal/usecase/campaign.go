@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// _campaignNameMaxLen - max length of campaign name in runes.
+const _campaignNameMaxLen = 128
+
+// campaignValidation - check cashback campaign and normalize it: currency code is upper case,
+// zero start time means now.
+func (uc *AccountUseCase) campaignValidation(c entity.Campaign) (entity.Campaign, error) {
+	if c.Name == "" {
+		return c, ErrorCampaignNameIsEmpty
+	}
+
+	if utf8.RuneCountInString(c.Name) > _campaignNameMaxLen {
+		return c, ErrorCampaignNameTooLong
+	}
+
+	if err := uc.metaValidation(entity.TransactionMeta{Purpose: c.Purpose}); err != nil {
+		return c, err
+	}
+
+	cur, ok := entity.CurrencyByCode(c.Currency)
+	if !ok {
+		return c, ErrorUnknownCurrency
+	}
+
+	c.Currency = cur.Code
+
+	if err := percentValidation(c.Percent); err != nil {
+		return c, err
+	} else if c.Percent.IsZero() {
+		return c, ErrorPercentOutOfRange
+	}
+
+	if c.MonthlyCap.Valid {
+		if err := fixedAmountValidation(cur, c.MonthlyCap.Decimal); err != nil {
+			return c, err
+		} else if c.MonthlyCap.Decimal.IsZero() {
+			return c, ErrorAmountIsZero
+		}
+	}
+
+	if err := fixedAmountValidation(cur, c.Budget); err != nil {
+		return c, err
+	}
+
+	if c.StartDt.IsZero() {
+		c.StartDt = time.Now()
+	}
+
+	if c.EndDt != nil && !c.EndDt.After(c.StartDt) {
+		return c, ErrorCampaignPeriod
+	}
+
+	return c, nil
+}
+
+// CreateCampaign - create cashback campaign, it pays percent of redeems with purpose in currency
+// from promo system account until its budget is spent.
+func (uc *AccountUseCase) CreateCampaign(ctx context.Context, c entity.Campaign) (created entity.Campaign, err error) {
+	c, err = uc.campaignValidation(c)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateCampaign - uc.campaignValidation: %w", err)
+	}
+
+	created, err = uc.repo.CreateCampaign(ctx, c)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateCampaign - uc.repo.CreateCampaign: %w", err)
+	}
+
+	return
+}
+
+// GetCampaign - get cashback campaign by ID.
+func (uc *AccountUseCase) GetCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - GetCampaign - uc.idValidation: %w", err)
+	}
+
+	c, err = uc.repo.GetCampaign(ctx, id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - GetCampaign - uc.repo.GetCampaign: %w", err)
+	}
+
+	return
+}
+
+// ListCampaigns - get cashback campaigns in status, empty status means any status.
+func (uc *AccountUseCase) ListCampaigns(ctx context.Context, status string) (campaigns []*entity.Campaign, err error) {
+	if status != "" && !entity.CampaignStatus(status).IsKnown() {
+		return campaigns, fmt.Errorf("AccountUseCase - ListCampaigns - validation: %w", ErrorUnknownCampaignStatus)
+	}
+
+	campaigns, err = uc.repo.ListCampaigns(ctx, entity.CampaignStatus(status))
+	if err != nil {
+		return campaigns, fmt.Errorf("AccountUseCase - ListCampaigns - uc.repo.ListCampaigns: %w", err)
+	}
+
+	return
+}
+
+// AddCampaignBudget - add amount to budget of campaign, exhausted campaign pays cashback again.
+func (uc *AccountUseCase) AddCampaignBudget(ctx context.Context, id int64, amount decimal.Decimal) (c entity.Campaign, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - AddCampaignBudget - uc.idValidation: %w", err)
+	}
+
+	c, err = uc.repo.GetCampaign(ctx, id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - AddCampaignBudget - uc.repo.GetCampaign: %w", err)
+	}
+
+	err = uc.amountValidation(amount, c.Currency)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - AddCampaignBudget - uc.amountValidation: %w", err)
+	}
+
+	c, err = uc.repo.AddCampaignBudget(ctx, id, amount)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - AddCampaignBudget - uc.repo.AddCampaignBudget: %w", err)
+	}
+
+	return
+}
+
+// PauseCampaign - pause campaign, it does not pay cashback until it is resumed.
+func (uc *AccountUseCase) PauseCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - PauseCampaign - uc.idValidation: %w", err)
+	}
+
+	c, err = uc.repo.PauseCampaign(ctx, id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - PauseCampaign - uc.repo.PauseCampaign: %w", err)
+	}
+
+	return
+}
+
+// ResumeCampaign - resume paused campaign.
+func (uc *AccountUseCase) ResumeCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - ResumeCampaign - uc.idValidation: %w", err)
+	}
+
+	c, err = uc.repo.ResumeCampaign(ctx, id)
+	if err != nil {
+		return c, fmt.Errorf("AccountUseCase - ResumeCampaign - uc.repo.ResumeCampaign: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,211 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateCampaign(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	start := time.Now().Add(time.Hour)
+	end := start.Add(30 * 24 * time.Hour)
+	before := start.Add(-time.Minute)
+	campaign := func(mod func(c *entity.Campaign)) entity.Campaign {
+		c := entity.Campaign{
+			Name:       "Cashback on groceries",
+			Purpose:    "groceries",
+			Currency:   "RUB",
+			Percent:    decimal.NewFromInt(5),
+			MonthlyCap: decimal.NewNullDecimal(decimal.NewFromInt(500)),
+			Budget:     decimal.NewFromInt(100000),
+			StartDt:    start,
+			EndDt:      &end,
+		}
+		if mod != nil {
+			mod(&c)
+		}
+		return c
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    entity.Campaign
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CreateCampaign(f.ctx, campaign(nil)).Return(entity.Campaign{Id: 1, Status: entity.CampaignActive}, nil)
+			},
+			arg1:    campaign(func(c *entity.Campaign) { c.Currency = "rub" }),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: start now without cap and end",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CreateCampaign(f.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c entity.Campaign) (entity.Campaign, error) {
+					if c.StartDt.IsZero() {
+						t.Errorf("CreateCampaign() start time is zero")
+					}
+					return entity.Campaign{Id: 2, Status: entity.CampaignActive}, nil
+				})
+			},
+			arg1: campaign(func(c *entity.Campaign) {
+				c.MonthlyCap, c.StartDt, c.EndDt = decimal.NullDecimal{}, time.Time{}, nil
+			}),
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: name is empty",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.Name = "" }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: incorrect purpose",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.Purpose = "Groceries and more" }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.Currency = "XXX" }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: percent is zero",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.Percent = decimal.Zero }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: monthly cap is zero",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.MonthlyCap = decimal.NewNullDecimal(decimal.Zero) }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: budget is negative",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.Budget = decimal.NewFromInt(-1) }),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: end is before start",
+			prepare: func(f *fields) {},
+			arg1:    campaign(func(c *entity.Campaign) { c.EndDt = &before }),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if c, err := uc.CreateCampaign(f.ctx, tt.arg1); (err != nil) != tt.wantErr {
+				t.Errorf("CreateCampaign() campaign=%v error = %v, wantErr %v", c, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_AddCampaignBudget(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	exhausted := entity.Campaign{Id: 1, Currency: "RUB", Budget: decimal.NewFromInt(1000), Spent: decimal.NewFromInt(1000), Status: entity.CampaignExhausted}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetCampaign(f.ctx, int64(1)).Return(exhausted, nil)
+				f.accountRepo.EXPECT().AddCampaignBudget(f.ctx, int64(1), decimal.NewFromInt(500)).
+					Return(entity.Campaign{Id: 1, Currency: "RUB", Budget: decimal.NewFromInt(1500), Spent: decimal.NewFromInt(1000), Status: entity.CampaignActive}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(500),
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: amount is negative",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetCampaign(f.ctx, int64(1)).Return(exhausted, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(-500),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetCampaign(f.ctx, int64(1)).Return(exhausted, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.RequireFromString("0.001"),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: campaign is not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetCampaign(f.ctx, int64(2)).Return(entity.Campaign{}, entity.ErrCampaignNotFound)
+			},
+			arg1:    2,
+			arg2:    decimal.NewFromInt(500),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is negative",
+			prepare: func(f *fields) {},
+			arg1:    -1,
+			arg2:    decimal.NewFromInt(500),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if c, err := uc.AddCampaignBudget(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("AddCampaignBudget() campaign=%v error = %v, wantErr %v", c, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,9 +3,78 @@ package usecase
 import "errors"
 
 var (
-	ErrorAmountIsNegative error = errors.New("amount is negative")
-	ErrorAmountIsZero     error = errors.New("amount is zero")
-	ErrorIdIsNegative     error = errors.New("ID is negative")
-	ErrorIdIsZero         error = errors.New("ID is zero")
-	ErrorSameRedeemAccrId error = errors.New("redeem and accrual ID are the same")
+	ErrorAmountIsNegative       error = errors.New("amount is negative")
+	ErrorAmountIsZero           error = errors.New("amount is zero")
+	ErrorAmountPrecision        error = errors.New("amount has too many decimal places")
+	ErrorLimitIsNegative        error = errors.New("credit limit is negative")
+	ErrorIdIsNegative           error = errors.New("ID is negative")
+	ErrorIdIsZero               error = errors.New("ID is zero")
+	ErrorSameRedeemAccrId       error = errors.New("redeem and accrual ID are the same")
+	ErrorOwnerIdTooLong         error = errors.New("owner ID is too long")
+	ErrorOwnerIdIsEmpty         error = errors.New("owner ID is empty")
+	ErrorUnknownWallet          error = errors.New("unknown wallet")
+	ErrorUnknownSort            error = errors.New("unknown sort column")
+	ErrorUnknownStatus          error = errors.New("unknown account status")
+	ErrorLimitTooLarge          error = errors.New("page limit is too large")
+	ErrorBadCursor              error = errors.New("incorrect page cursor")
+	ErrorBalanceRange           error = errors.New("min balance is greater than max balance")
+	ErrorDateRange              error = errors.New("start of date range is after its end")
+	ErrorIdempotencyKeyTooLong  error = errors.New("idempotency key is too long")
+	ErrorIdempotencyConflict    error = errors.New("idempotency key is already used with another request")
+	ErrorReasonIsEmpty          error = errors.New("reason is empty")
+	ErrorSameSweepId            error = errors.New("account and sweep account ID are the same")
+	ErrorOrderIdIsEmpty         error = errors.New("order ID is empty")
+	ErrorOrderIdTooLong         error = errors.New("order ID is too long")
+	ErrorDescriptionTooLong     error = errors.New("description is too long")
+	ErrorIncorrectPurpose       error = errors.New("incorrect purpose code")
+	ErrorSourceTooLong          error = errors.New("source is too long")
+	ErrorTTLIsNegative          error = errors.New("hold TTL is negative")
+	ErrorTTLTooSmall            error = errors.New("hold TTL is too small")
+	ErrorTTLTooLarge            error = errors.New("hold TTL is too large")
+	ErrorUnknownSystemAccount   error = errors.New("unknown system account")
+	ErrorBatchIsEmpty           error = errors.New("batch has no legs")
+	ErrorBatchTooLarge          error = errors.New("batch has too many legs")
+	ErrorSplitIsEmpty           error = errors.New("split has no shares")
+	ErrorSplitTooLarge          error = errors.New("split has too many shares")
+	ErrorSplitDuplicateAccount  error = errors.New("account has several shares in split")
+	ErrorSplitMixedShares       error = errors.New("shares must be all percents or all fixed amounts")
+	ErrorSplitPercent           error = errors.New("share percent is out of range (0, 100] or has more than 4 decimal places")
+	ErrorSplitPercentSum        error = errors.New("share percents do not add up to 100")
+	ErrorSplitAmountMismatch    error = errors.New("amount does not match the sum of shares")
+	ErrorSplitShareIsZero       error = errors.New("amount is too small to split, share is zero")
+	ErrorRunAtInPast            error = errors.New("run time is not in the future")
+	ErrorRunAtTooFar            error = errors.New("run time is too far in the future")
+	ErrorUnknownScheduledStatus error = errors.New("unknown status of scheduled transfer")
+	ErrorNothingToUpdate        error = errors.New("nothing to update")
+	ErrorIncorrectSchedule      error = errors.New("incorrect schedule, expected interval like @every 720h or cron expression")
+	ErrorMaxChargesIsNegative   error = errors.New("max number of charges is negative")
+	ErrorStartInPast            error = errors.New("start time is in the past")
+	ErrorEndBeforeStart         error = errors.New("end time is before the first charge")
+	ErrorUnknownRecurringStatus error = errors.New("unknown status of recurring plan")
+	ErrorUnknownTier            error = errors.New("unknown account tier")
+	ErrorUnknownFeeOperation    error = errors.New("unknown operation of fee")
+	ErrorUnknownFeeKind         error = errors.New("unknown kind of fee")
+	ErrorIncorrectFeeTiers      error = errors.New("fee tiers must start at zero amount and go in ascending order")
+	ErrorPercentOutOfRange      error = errors.New("percent is out of range [0, 100) or has more than 4 decimal places")
+	ErrorFeeRange               error = errors.New("min fee is greater than max fee")
+	ErrorCampaignNameIsEmpty    error = errors.New("campaign name is empty")
+	ErrorCampaignNameTooLong    error = errors.New("campaign name is too long")
+	ErrorCampaignPeriod         error = errors.New("campaign end is not after its start")
+	ErrorUnknownCampaignStatus  error = errors.New("unknown status of campaign")
+	ErrorSameBuyerSellerId      error = errors.New("buyer and seller ID are the same")
+	ErrorEscrowTTLIsNegative    error = errors.New("safe deal TTL is negative")
+	ErrorEscrowTTLTooSmall      error = errors.New("safe deal TTL is too small")
+	ErrorEscrowTTLTooLarge      error = errors.New("safe deal TTL is too large")
+	ErrorUnknownLimitPeriod     error = errors.New("unknown period of velocity limit")
+	ErrorLimitCountIsNegative   error = errors.New("max number of debits is negative")
+	ErrorVelocityLimitIsEmpty   error = errors.New("velocity limit has neither max amount nor max number of debits")
+
+	ErrorUnknownCurrency   error = errors.New("unknown currency")
+	ErrorCurrencyMismatch  error = errors.New("accounts have different currencies")
+	ErrorOperationCurrency error = errors.New("currency of operation does not match the account")
+	ErrorRateNotStored     error = errors.New("exchange rate is not stored in rate history")
+	ErrorRateIsNotPositive error = errors.New("rate is not positive")
+	ErrorSpreadOutOfRange  error = errors.New("spread is out of range [0, 1)")
+	ErrorSameBaseQuote     error = errors.New("base and quote currencies are the same")
+	ErrorValidFromInPast   error = errors.New("start of validity of rate is in the past")
 )
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+const (
+	// _defaultEscrowTTL - time of safe deal to be released when TTL is not passed.
+	_defaultEscrowTTL = 14 * 24 * time.Hour
+	// _minEscrowTTL - min TTL of safe deal.
+	_minEscrowTTL = time.Second
+	// _maxEscrowTTL - max TTL of safe deal.
+	_maxEscrowTTL = 90 * 24 * time.Hour
+)
+
+func (uc *AccountUseCase) escrowTTLValidation(ttl time.Duration) (err error) {
+	if ttl < 0 {
+		err = ErrorEscrowTTLIsNegative
+	} else if ttl > 0 && ttl < _minEscrowTTL {
+		err = ErrorEscrowTTLTooSmall
+	} else if ttl > _maxEscrowTTL {
+		err = ErrorEscrowTTLTooLarge
+	}
+
+	return
+}
+
+// FundEscrow - open safe deal of buyer and seller for order: amount is moved from buyer to escrow until it is
+// released to seller or refunded to buyer. Deal which is not released for ttl is refunded, zero ttl means default TTL.
+func (uc *AccountUseCase) FundEscrow(ctx context.Context, buyerId, sellerId int64, orderId string, amount decimal.Decimal, ttl time.Duration, meta entity.TransactionMeta) (e entity.Escrow, acc entity.Account, err error) {
+	if buyerId == sellerId {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - validation: %w", ErrorSameBuyerSellerId)
+	}
+
+	err = uc.orderIdValidation(orderId)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.orderIdValidation: %w", err)
+	}
+
+	err = uc.escrowTTLValidation(ttl)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.escrowTTLValidation: %w", err)
+	}
+
+	if ttl == 0 {
+		ttl = _defaultEscrowTTL
+	}
+
+	err = uc.metaValidation(meta)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.metaValidation: %w", err)
+	}
+
+	currencies := make([]string, 0, 2)
+
+	for _, id := range []int64{buyerId, sellerId} {
+		err = uc.idValidation(id)
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.idValidation: %w", err)
+		}
+
+		acc, err := uc.repo.GetById(ctx, id)
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.repo.GetById: %w", err)
+		}
+
+		currencies = append(currencies, acc.Currency)
+	}
+
+	if currencies[0] != currencies[1] {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - validation: %w", ErrorCurrencyMismatch)
+	}
+
+	err = uc.amountValidation(amount, currencies[0])
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.amountValidation: %w", err)
+	}
+
+	e, acc, err = uc.repo.CreateEscrow(ctx, entity.Escrow{
+		BuyerId:         buyerId,
+		SellerId:        sellerId,
+		OrderId:         orderId,
+		Amount:          amount,
+		Currency:        currencies[0],
+		TransactionMeta: meta,
+	}, ttl)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - FundEscrow - uc.repo.CreateEscrow: %w", err)
+	}
+
+	return
+}
+
+// GetEscrow - get safe deal by ID.
+func (uc *AccountUseCase) GetEscrow(ctx context.Context, id int64) (e entity.Escrow, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return e, fmt.Errorf("AccountUseCase - GetEscrow - uc.idValidation: %w", err)
+	}
+
+	e, err = uc.repo.GetEscrow(ctx, id)
+	if err != nil {
+		return e, fmt.Errorf("AccountUseCase - GetEscrow - uc.repo.GetEscrow: %w", err)
+	}
+
+	return
+}
+
+// GetEscrowHistory - get history of safe deal's status changes.
+func (uc *AccountUseCase) GetEscrowHistory(ctx context.Context, id int64) (changes []*entity.EscrowChange, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return changes, fmt.Errorf("AccountUseCase - GetEscrowHistory - uc.idValidation: %w", err)
+	}
+
+	changes, err = uc.repo.GetEscrowHistory(ctx, id)
+	if err != nil {
+		return changes, fmt.Errorf("AccountUseCase - GetEscrowHistory - uc.repo.GetEscrowHistory: %w", err)
+	}
+
+	return
+}
+
+// changeEscrow - move safe deal to status with reason.
+func (uc *AccountUseCase) changeEscrow(ctx context.Context, id int64, to entity.EscrowStatus, reason string) (e entity.Escrow, acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return
+	}
+
+	return uc.repo.ChangeEscrowStatus(ctx, id, to, reason)
+}
+
+// ReleaseEscrow - pay amount of funded or disputed safe deal to seller, e.g. delivery is confirmed.
+func (uc *AccountUseCase) ReleaseEscrow(ctx context.Context, id int64, reason string) (e entity.Escrow, acc entity.Account, err error) {
+	e, acc, err = uc.changeEscrow(ctx, id, entity.EscrowReleased, reason)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - ReleaseEscrow - uc.changeEscrow: %w", err)
+	}
+
+	return
+}
+
+// RefundEscrow - return amount of funded or disputed safe deal to buyer.
+func (uc *AccountUseCase) RefundEscrow(ctx context.Context, id int64, reason string) (e entity.Escrow, acc entity.Account, err error) {
+	e, acc, err = uc.changeEscrow(ctx, id, entity.EscrowRefunded, reason)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountUseCase - RefundEscrow - uc.changeEscrow: %w", err)
+	}
+
+	return
+}
+
+// DisputeEscrow - dispute funded safe deal with reason, money is held until the dispute is resolved
+// by release or refund, disputed deal does not expire.
+func (uc *AccountUseCase) DisputeEscrow(ctx context.Context, id int64, reason string) (e entity.Escrow, err error) {
+	if strings.TrimSpace(reason) == "" {
+		return e, fmt.Errorf("AccountUseCase - DisputeEscrow - validation: %w", ErrorReasonIsEmpty)
+	}
+
+	e, _, err = uc.changeEscrow(ctx, id, entity.EscrowDisputed, reason)
+	if err != nil {
+		return e, fmt.Errorf("AccountUseCase - DisputeEscrow - uc.changeEscrow: %w", err)
+	}
+
+	return
+}
+
+// RefundExpiredEscrows - refund all expired funded safe deals by batches of batchSize, returns number of refunded deals.
+func (uc *AccountUseCase) RefundExpiredEscrows(ctx context.Context, batchSize uint64) (total int64, err error) {
+	var count int64
+
+	for {
+		count, err = uc.repo.RefundExpiredEscrows(ctx, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("AccountUseCase - RefundExpiredEscrows - uc.repo.RefundExpiredEscrows: %w", err)
+		}
+
+		total += count
+
+		if uint64(count) < batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
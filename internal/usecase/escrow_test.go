@@ -0,0 +1,270 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_FundEscrow(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	buyer := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB", CreatedDt: time.Now()}
+	seller := entity.Account{Id: 2, Currency: "RUB", CreatedDt: time.Now()}
+	escrow := entity.Escrow{BuyerId: 1, SellerId: 2, OrderId: "order-1", Amount: decimal.NewFromInt(600), Currency: "RUB"}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    string
+		arg3    decimal.Decimal
+		arg4    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().CreateEscrow(f.ctx, escrow, 72*time.Hour).Return(entity.Escrow{Id: 1, Status: entity.EscrowFunded}, buyer, nil)
+			},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    72 * time.Hour,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: default TTL",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().CreateEscrow(f.ctx, escrow, 14*24*time.Hour).Return(entity.Escrow{Id: 1, Status: entity.EscrowFunded}, buyer, nil)
+			},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    0,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: deal for the order already exists",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().CreateEscrow(f.ctx, escrow, 72*time.Hour).Return(entity.Escrow{}, entity.Account{}, entity.ErrEscrowExists)
+			},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    72 * time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: buyer is seller",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    0,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: order ID is empty",
+			prepare: func(f *fields) {},
+			arg1:    2,
+			arg2:    " ",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    0,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: TTL is too large",
+			prepare: func(f *fields) {},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    91 * 24 * time.Hour,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: accounts have different currencies",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Currency: "USD"}, nil)
+			},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(600),
+			arg4:    0,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is zero",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+			},
+			arg1:    2,
+			arg2:    "order-1",
+			arg3:    decimal.Zero,
+			arg4:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if e, _, err := uc.FundEscrow(f.ctx, 1, tt.arg1, tt.arg2, tt.arg3, tt.arg4, entity.TransactionMeta{}); (err != nil) != tt.wantErr {
+				t.Errorf("FundEscrow() escrow=%v error = %v, wantErr %v", e, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_DisputeEscrow(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    string
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ChangeEscrowStatus(f.ctx, int64(1), entity.EscrowDisputed, "item is broken").Return(entity.Escrow{Id: 1, Status: entity.EscrowDisputed}, entity.Account{}, nil)
+			},
+			arg1:    1,
+			arg2:    "item is broken",
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: deal is released",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ChangeEscrowStatus(f.ctx, int64(1), entity.EscrowDisputed, "item is broken").Return(entity.Escrow{Id: 1, Status: entity.EscrowReleased}, entity.Account{}, entity.ErrEscrowTransition)
+			},
+			arg1:    1,
+			arg2:    "item is broken",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: reason is empty",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    "item is broken",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if e, err := uc.DisputeEscrow(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("DisputeEscrow() escrow=%v error = %v, wantErr %v", e, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_RefundExpiredEscrows(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name      string
+		prepare   func(f *fields)
+		arg       uint64
+		wantCount int64
+		wantErr   bool
+	}{
+		{
+			name: "Case of correct work: full batches are repeated",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.accountRepo.EXPECT().RefundExpiredEscrows(f.ctx, uint64(2)).Return(int64(2), nil),
+					f.accountRepo.EXPECT().RefundExpiredEscrows(f.ctx, uint64(2)).Return(int64(1), nil),
+				)
+			},
+			arg:       2,
+			wantCount: 3,
+			wantErr:   false,
+		},
+		{
+			name: "Case of incorrect work: error of the first batch",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().RefundExpiredEscrows(f.ctx, uint64(2)).Return(int64(0), errors.New("db is down"))
+			},
+			arg:       2,
+			wantCount: 0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			count, err := uc.RefundExpiredEscrows(f.ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefundExpiredEscrows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("RefundExpiredEscrows() count = %v, want %v", count, tt.wantCount)
+			}
+		})
+	}
+}
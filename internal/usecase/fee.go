@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// _maxFeePercent - upper bound of fee percent, percent is less than it.
+var _maxFeePercent = decimal.NewFromInt(100)
+
+// _percentPlaces - decimal places of percent of fee or share.
+const _percentPlaces = 4
+
+// percentValidation - percent is in [0, 100) and has no more than 4 decimal places.
+func percentValidation(percent decimal.Decimal) error {
+	if percent.IsNegative() || !percent.LessThan(_maxFeePercent) || !percent.Equal(percent.Truncate(_percentPlaces)) {
+		return ErrorPercentOutOfRange
+	}
+
+	return nil
+}
+
+// fixedAmountValidation - fixed amount, e.g. flat fee or cap, is not negative and fits currency.
+func fixedAmountValidation(cur entity.Currency, amount decimal.Decimal) error {
+	if amount.IsNegative() {
+		return ErrorAmountIsNegative
+	}
+
+	if !cur.Fits(amount) {
+		return ErrorAmountPrecision
+	}
+
+	return nil
+}
+
+// feeRuleValidation - check fee rule and normalize it: currency code is upper case, fields which the kind
+// of fee does not use are cleared. Tiers of tiered fee start at zero amount and go in ascending order.
+func feeRuleValidation(rule entity.FeeRule) (entity.FeeRule, error) {
+	if !rule.Operation.IsKnown() {
+		return rule, ErrorUnknownFeeOperation
+	}
+
+	cur, ok := entity.CurrencyByCode(rule.Currency)
+	if !ok {
+		return rule, ErrorUnknownCurrency
+	}
+
+	rule.Currency = cur.Code
+
+	if rule.Tier != nil && !rule.Tier.IsKnown() {
+		return rule, ErrorUnknownTier
+	}
+
+	switch rule.Kind {
+	case entity.FeeFlat:
+		rule.Percent, rule.Tiers = decimal.Zero, nil
+	case entity.FeePercent:
+		rule.Tiers = nil
+	case entity.FeeTiered:
+		rule.Flat, rule.Percent = decimal.Zero, decimal.Zero
+
+		if len(rule.Tiers) == 0 || !rule.Tiers[0].From.IsZero() {
+			return rule, ErrorIncorrectFeeTiers
+		}
+
+		for i, tier := range rule.Tiers {
+			if i > 0 && !tier.From.GreaterThan(rule.Tiers[i-1].From) {
+				return rule, ErrorIncorrectFeeTiers
+			}
+
+			for _, amount := range []decimal.Decimal{tier.From, tier.Flat} {
+				if err := fixedAmountValidation(cur, amount); err != nil {
+					return rule, err
+				}
+			}
+
+			if err := percentValidation(tier.Percent); err != nil {
+				return rule, err
+			}
+		}
+	default:
+		return rule, ErrorUnknownFeeKind
+	}
+
+	if err := fixedAmountValidation(cur, rule.Flat); err != nil {
+		return rule, err
+	}
+
+	if err := percentValidation(rule.Percent); err != nil {
+		return rule, err
+	}
+
+	for _, limit := range []decimal.NullDecimal{rule.MinFee, rule.MaxFee} {
+		if !limit.Valid {
+			continue
+		}
+
+		if err := fixedAmountValidation(cur, limit.Decimal); err != nil {
+			return rule, err
+		}
+	}
+
+	if rule.MinFee.Valid && rule.MaxFee.Valid && rule.MinFee.Decimal.GreaterThan(rule.MaxFee.Decimal) {
+		return rule, ErrorFeeRange
+	}
+
+	return rule, nil
+}
+
+// CreateFeeRule - create fee rule of operation in currency for accounts of tier, nil tier means any tier.
+// The rule replaces active rule of the same operation, currency and tier.
+func (uc *AccountUseCase) CreateFeeRule(ctx context.Context, rule entity.FeeRule) (created entity.FeeRule, err error) {
+	rule, err = feeRuleValidation(rule)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateFeeRule - feeRuleValidation: %w", err)
+	}
+
+	created, err = uc.repo.CreateFeeRule(ctx, rule)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateFeeRule - uc.repo.CreateFeeRule: %w", err)
+	}
+
+	return
+}
+
+// ListFeeRules - get active fee rules.
+func (uc *AccountUseCase) ListFeeRules(ctx context.Context) (rules []*entity.FeeRule, err error) {
+	rules, err = uc.repo.ListFeeRules(ctx)
+	if err != nil {
+		return rules, fmt.Errorf("AccountUseCase - ListFeeRules - uc.repo.ListFeeRules: %w", err)
+	}
+
+	return
+}
+
+// DisableFeeRule - disable active fee rule, operations are not charged by it anymore.
+func (uc *AccountUseCase) DisableFeeRule(ctx context.Context, id int64) (rule entity.FeeRule, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return rule, fmt.Errorf("AccountUseCase - DisableFeeRule - uc.idValidation: %w", err)
+	}
+
+	rule, err = uc.repo.DisableFeeRule(ctx, id)
+	if err != nil {
+		return rule, fmt.Errorf("AccountUseCase - DisableFeeRule - uc.repo.DisableFeeRule: %w", err)
+	}
+
+	return
+}
+
+// SetTier - set tier of account, fee rules of the tier apply to operations of account.
+func (uc *AccountUseCase) SetTier(ctx context.Context, id int64, tier entity.AccountTier) (acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - SetTier - uc.idValidation: %w", err)
+	}
+
+	if !tier.IsKnown() {
+		return acc, fmt.Errorf("AccountUseCase - SetTier - validation: %w", ErrorUnknownTier)
+	}
+
+	acc, err = uc.repo.SetTier(ctx, id, tier)
+	if err != nil {
+		return acc, fmt.Errorf("AccountUseCase - SetTier - uc.repo.SetTier: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,232 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateFeeRule(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	premium := entity.TierPremium
+	unknown := entity.AccountTier("gold")
+	tiers := []entity.FeeTier{
+		{From: decimal.Zero, Flat: decimal.NewFromInt(10)},
+		{From: decimal.NewFromInt(10000), Percent: decimal.RequireFromString("0.5")},
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    entity.FeeRule
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: percent fee",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CreateFeeRule(f.ctx, entity.FeeRule{
+					Operation: entity.FeeTransfer,
+					Currency:  "RUB",
+					Tier:      &premium,
+					Kind:      entity.FeePercent,
+					Flat:      decimal.NewFromInt(5),
+					Percent:   decimal.RequireFromString("1.5"),
+					MinFee:    decimal.NewNullDecimal(decimal.NewFromInt(10)),
+					MaxFee:    decimal.NewNullDecimal(decimal.NewFromInt(500)),
+				}).Return(entity.FeeRule{Id: 1, Active: true, CreatedDt: time.Now()}, nil)
+			},
+			arg1: entity.FeeRule{
+				Operation: entity.FeeTransfer,
+				Currency:  "rub",
+				Tier:      &premium,
+				Kind:      entity.FeePercent,
+				Flat:      decimal.NewFromInt(5),
+				Percent:   decimal.RequireFromString("1.5"),
+				Tiers:     tiers,
+				MinFee:    decimal.NewNullDecimal(decimal.NewFromInt(10)),
+				MaxFee:    decimal.NewNullDecimal(decimal.NewFromInt(500)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: tiered fee",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CreateFeeRule(f.ctx, entity.FeeRule{
+					Operation: entity.FeeWithdrawal,
+					Currency:  "RUB",
+					Kind:      entity.FeeTiered,
+					Flat:      decimal.Zero,
+					Percent:   decimal.Zero,
+					Tiers:     tiers,
+				}).Return(entity.FeeRule{Id: 2, Active: true, CreatedDt: time.Now()}, nil)
+			},
+			arg1: entity.FeeRule{
+				Operation: entity.FeeWithdrawal,
+				Currency:  "RUB",
+				Kind:      entity.FeeTiered,
+				Flat:      decimal.NewFromInt(5),
+				Tiers:     tiers,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown operation",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: "deposit", Currency: "RUB", Kind: entity.FeeFlat, Flat: decimal.NewFromInt(10)},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "XXX", Kind: entity.FeeFlat, Flat: decimal.NewFromInt(10)},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown tier",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Tier: &unknown, Kind: entity.FeeFlat, Flat: decimal.NewFromInt(10)},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown kind",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Kind: "progressive"},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: percent is out of range",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Kind: entity.FeePercent, Percent: decimal.NewFromInt(100)},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: flat fee has too many decimal places",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Kind: entity.FeeFlat, Flat: decimal.RequireFromString("0.001")},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: tiers do not start at zero",
+			prepare: func(f *fields) {},
+			arg1:    entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Kind: entity.FeeTiered, Tiers: tiers[1:]},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: tiers are not ascending",
+			prepare: func(f *fields) {},
+			arg1: entity.FeeRule{Operation: entity.FeeTransfer, Currency: "RUB", Kind: entity.FeeTiered, Tiers: []entity.FeeTier{
+				{From: decimal.Zero, Flat: decimal.NewFromInt(10)},
+				{From: decimal.Zero, Flat: decimal.NewFromInt(20)},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: min fee is greater than max fee",
+			prepare: func(f *fields) {},
+			arg1: entity.FeeRule{
+				Operation: entity.FeeTransfer,
+				Currency:  "RUB",
+				Kind:      entity.FeePercent,
+				Percent:   decimal.NewFromInt(1),
+				MinFee:    decimal.NewNullDecimal(decimal.NewFromInt(500)),
+				MaxFee:    decimal.NewNullDecimal(decimal.NewFromInt(10)),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if rule, err := uc.CreateFeeRule(f.ctx, tt.arg1); (err != nil) != tt.wantErr {
+				t.Errorf("CreateFeeRule() rule=%v error = %v, wantErr %v", rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_SetTier(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    entity.AccountTier
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().SetTier(f.ctx, int64(1), entity.TierBusiness).Return(entity.Account{Id: 1, Currency: "RUB", Tier: entity.TierBusiness}, nil)
+			},
+			arg1:    1,
+			arg2:    entity.TierBusiness,
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown tier",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "gold",
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    entity.TierPremium,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: account is not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().SetTier(f.ctx, int64(2), entity.TierPremium).Return(entity.Account{}, errors.New("no rows in result set"))
+			},
+			arg1:    2,
+			arg2:    entity.TierPremium,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if acc, err := uc.SetTier(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("SetTier() account=%v error = %v, wantErr %v", acc, err, tt.wantErr)
+			}
+		})
+	}
+}
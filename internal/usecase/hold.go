@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+const (
+	// _orderIdMaxLen - max length of order ID of hold.
+	_orderIdMaxLen = 64
+	// _defaultHoldTTL - TTL of hold when TTL is not passed.
+	_defaultHoldTTL = 24 * time.Hour
+	// _minHoldTTL - min TTL of hold.
+	_minHoldTTL = time.Second
+	// _maxHoldTTL - max TTL of hold.
+	_maxHoldTTL = 30 * 24 * time.Hour
+)
+
+func (uc *AccountUseCase) orderIdValidation(orderId string) (err error) {
+	if strings.TrimSpace(orderId) == "" {
+		err = ErrorOrderIdIsEmpty
+	} else if len(orderId) > _orderIdMaxLen {
+		err = ErrorOrderIdTooLong
+	}
+
+	return
+}
+
+func (uc *AccountUseCase) ttlValidation(ttl time.Duration) (err error) {
+	if ttl < 0 {
+		err = ErrorTTLIsNegative
+	} else if ttl > 0 && ttl < _minHoldTTL {
+		err = ErrorTTLTooSmall
+	} else if ttl > _maxHoldTTL {
+		err = ErrorTTLTooLarge
+	}
+
+	return
+}
+
+// CreateHold - reserve amount on account for order, reserved amount is not available for redeem.
+// Hold which is not captured or released for ttl expires, zero ttl means default TTL.
+func (uc *AccountUseCase) CreateHold(ctx context.Context, accountId int64, orderId string, amount decimal.Decimal, ttl time.Duration) (hold entity.Hold, acc entity.Account, err error) {
+	err = uc.idValidation(accountId)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.idValidation: %w", err)
+	}
+
+	err = uc.orderIdValidation(orderId)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.orderIdValidation: %w", err)
+	}
+
+	err = uc.ttlValidation(ttl)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.ttlValidation: %w", err)
+	}
+
+	if ttl == 0 {
+		ttl = _defaultHoldTTL
+	}
+
+	acc, err = uc.repo.GetById(ctx, accountId)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.repo.GetById: %w", err)
+	}
+
+	err = uc.amountValidation(amount, acc.Currency)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.amountValidation: %w", err)
+	}
+
+	hold, acc, err = uc.repo.CreateHold(ctx, accountId, orderId, amount, ttl)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CreateHold - uc.repo.CreateHold: %w", err)
+	}
+
+	return
+}
+
+// CaptureHold - charge amount of hold from account, the rest of hold is released.
+// Zero amount means capture of whole hold.
+func (uc *AccountUseCase) CaptureHold(ctx context.Context, id int64, amount decimal.Decimal) (hold entity.Hold, acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CaptureHold - uc.idValidation: %w", err)
+	}
+
+	hold, err = uc.repo.GetHold(ctx, id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CaptureHold - uc.repo.GetHold: %w", err)
+	}
+
+	if amount.IsZero() {
+		amount = hold.Amount
+	}
+
+	err = uc.amountValidation(amount, hold.Currency)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CaptureHold - uc.amountValidation: %w", err)
+	}
+
+	hold, acc, err = uc.repo.CaptureHold(ctx, id, amount)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - CaptureHold - uc.repo.CaptureHold: %w", err)
+	}
+
+	return
+}
+
+// ReleaseHold - return reserved amount of hold to available balance of account.
+func (uc *AccountUseCase) ReleaseHold(ctx context.Context, id int64) (hold entity.Hold, acc entity.Account, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - ReleaseHold - uc.idValidation: %w", err)
+	}
+
+	hold, acc, err = uc.repo.ReleaseHold(ctx, id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountUseCase - ReleaseHold - uc.repo.ReleaseHold: %w", err)
+	}
+
+	return
+}
+
+// GetHold - get hold by ID.
+func (uc *AccountUseCase) GetHold(ctx context.Context, id int64) (hold entity.Hold, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return hold, fmt.Errorf("AccountUseCase - GetHold - uc.idValidation: %w", err)
+	}
+
+	hold, err = uc.repo.GetHold(ctx, id)
+	if err != nil {
+		return hold, fmt.Errorf("AccountUseCase - GetHold - uc.repo.GetHold: %w", err)
+	}
+
+	return
+}
+
+// ExpireHolds - release all expired holds by batches of batchSize, returns number of released holds.
+func (uc *AccountUseCase) ExpireHolds(ctx context.Context, batchSize uint64) (total int64, err error) {
+	var count int64
+
+	for {
+		count, err = uc.repo.ExpireHolds(ctx, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("AccountUseCase - ExpireHolds - uc.repo.ExpireHolds: %w", err)
+		}
+
+		total += count
+
+		if uint64(count) < batchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
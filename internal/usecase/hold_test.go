@@ -0,0 +1,340 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateHold(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    string
+		arg3    decimal.Decimal
+		arg4    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().CreateHold(f.ctx, int64(1), "order-1", decimal.NewFromInt(40), time.Hour).Return(entity.Hold{Id: 1, AccountId: 1, OrderId: "order-1", Amount: decimal.NewFromInt(40), Currency: "RUB", Status: entity.HoldActive}, entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Reserved: decimal.NewFromInt(40), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: default TTL",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().CreateHold(f.ctx, int64(1), "order-1", decimal.NewFromInt(40), 24*time.Hour).Return(entity.Hold{Id: 1, AccountId: 1, OrderId: "order-1", Amount: decimal.NewFromInt(40), Currency: "RUB", Status: entity.HoldActive}, entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Reserved: decimal.NewFromInt(40), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    0,
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: TTL is negative",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    -time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: TTL is too small",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Millisecond,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: TTL is too large",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    365 * 24 * time.Hour,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: not enough available money",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(10), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().CreateHold(f.ctx, int64(1), "order-1", decimal.NewFromInt(40), time.Hour).Return(entity.Hold{}, entity.Account{}, entity.ErrNotEnoughMoney)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: order already has hold",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+				f.accountRepo.EXPECT().CreateHold(f.ctx, int64(1), "order-1", decimal.NewFromInt(40), time.Hour).Return(entity.Hold{}, entity.Account{}, entity.ErrHoldExists)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.RequireFromString("0.001"),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is negative",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(entity.Account{Id: 1, Balance: decimal.NewFromInt(100), Currency: "RUB", CreatedDt: time.Now()}, nil)
+			},
+			arg1:    1,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(-40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: order ID is empty",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    " ",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: order ID is too long",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    strings.Repeat("a", 65),
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    "order-1",
+			arg3:    decimal.NewFromInt(40),
+			arg4:    time.Hour,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if hold, _, err := uc.CreateHold(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4); (err != nil) != tt.wantErr {
+				t.Errorf("CreateHold() hold=%v error = %v, wantErr %v", hold, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_CaptureHold(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	active := entity.Hold{Id: 1, AccountId: 1, OrderId: "order-1", Amount: decimal.NewFromInt(40), Currency: "RUB", Status: entity.HoldActive}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHold(f.ctx, int64(1)).Return(active, nil)
+				f.accountRepo.EXPECT().CaptureHold(f.ctx, int64(1), decimal.NewFromInt(30)).Return(entity.Hold{Id: 1, Status: entity.HoldCaptured}, entity.Account{Id: 1}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(30),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: zero amount captures whole hold",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHold(f.ctx, int64(1)).Return(active, nil)
+				f.accountRepo.EXPECT().CaptureHold(f.ctx, int64(1), decimal.NewFromInt(40)).Return(entity.Hold{Id: 1, Status: entity.HoldCaptured}, entity.Account{Id: 1}, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.Zero,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: amount is greater than hold",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHold(f.ctx, int64(1)).Return(active, nil)
+				f.accountRepo.EXPECT().CaptureHold(f.ctx, int64(1), decimal.NewFromInt(50)).Return(entity.Hold{}, entity.Account{}, entity.ErrHoldExceeded)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(50),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: hold is not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHold(f.ctx, int64(2)).Return(entity.Hold{}, entity.ErrHoldNotFound)
+			},
+			arg1:    2,
+			arg2:    decimal.NewFromInt(30),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is negative",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetHold(f.ctx, int64(1)).Return(active, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(-30),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is negative",
+			prepare: func(f *fields) {},
+			arg1:    -1,
+			arg2:    decimal.NewFromInt(30),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if hold, _, err := uc.CaptureHold(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("CaptureHold() hold=%v error = %v, wantErr %v", hold, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_ExpireHolds(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name      string
+		prepare   func(f *fields)
+		arg       uint64
+		wantCount int64
+		wantErr   bool
+	}{
+		{
+			name: "Case of correct work: full batches are repeated",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(2)).Return(int64(2), nil),
+					f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(2)).Return(int64(2), nil),
+					f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(2)).Return(int64(1), nil),
+				)
+			},
+			arg:       2,
+			wantCount: 5,
+			wantErr:   false,
+		},
+		{
+			name: "Case of correct work: no expired holds",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(100)).Return(int64(0), nil)
+			},
+			arg:       100,
+			wantCount: 0,
+			wantErr:   false,
+		},
+		{
+			name: "Case of incorrect work: error of the second batch",
+			prepare: func(f *fields) {
+				gomock.InOrder(
+					f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(2)).Return(int64(2), nil),
+					f.accountRepo.EXPECT().ExpireHolds(f.ctx, uint64(2)).Return(int64(0), errors.New("db is down")),
+				)
+			},
+			arg:       2,
+			wantCount: 2,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			count, err := uc.ExpireHolds(f.ctx, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpireHolds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("ExpireHolds() count = %v, want %v", count, tt.wantCount)
+			}
+		})
+	}
+}
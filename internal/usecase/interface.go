@@ -3,19 +3,85 @@ package usecase
 
 import (
 	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
 
 	"github.com/cut4cut/avito-test-work/internal/entity"
 )
 
-//go:generate mockgen -destination=./mocks_test.go -package=usecase_test github.com/cut4cut/avito-test-work/internal/usecase AccountRepo
+//go:generate mockgen -destination=./mocks_test.go -package=usecase_test github.com/cut4cut/avito-test-work/internal/usecase AccountRepo,RateProvider,RateRepo
 
 type (
 	// AccountRepo -.
 	AccountRepo interface {
-		Create(context.Context) (entity.Account, error)
+		Create(context.Context, string, string, entity.Wallet) (entity.Account, error)
 		GetById(context.Context, int64) (entity.Account, error)
-		UpdBalance(context.Context, int64, int64, float64) (entity.Account, error)
-		TransferAmount(context.Context, int64, int64, float64) (entity.Account, entity.Account, error)
-		GetHistory(context.Context, int64, uint64, uint64, string, bool) ([]*entity.Transaction, error)
+		GetByOwner(context.Context, string, entity.Wallet) (entity.Account, error)
+		GetWallets(context.Context, string) ([]entity.Account, error)
+		List(context.Context, entity.AccountFilter) ([]entity.Account, int64, error)
+		UpdBalance(context.Context, entity.AccountRef, decimal.Decimal, string, entity.SystemAccount, entity.TransactionMeta, *entity.IdempotencyKey) (entity.Account, error)
+		TransferAmount(context.Context, entity.AccountRef, entity.AccountRef, decimal.Decimal, decimal.Decimal, *entity.Rate, entity.TransactionMeta, *entity.IdempotencyKey) (entity.Account, entity.Account, error)
+		BatchTransfer(context.Context, []entity.TransferLeg, *entity.IdempotencyKey) ([]entity.Account, error)
+		SplitTransfer(context.Context, int64, []entity.SplitShare, entity.TransactionMeta, *entity.IdempotencyKey) (entity.SplitTransfer, error)
+		GetHistory(context.Context, int64, uint64, uint64, string, bool, entity.TransactionFilter) ([]*entity.Transaction, error)
+		GetTransaction(context.Context, int64) (entity.Transaction, error)
+		ReverseTransaction(context.Context, int64, decimal.Decimal) ([]*entity.Transaction, error)
+		ChangeStatus(context.Context, int64, entity.AccountStatus, string, int64) (entity.Account, error)
+		GetStatusHistory(context.Context, int64) ([]*entity.StatusChange, error)
+		SetCreditLimit(context.Context, int64, decimal.Decimal) (entity.Account, error)
+		SetTier(context.Context, int64, entity.AccountTier) (entity.Account, error)
+		CreateHold(context.Context, int64, string, decimal.Decimal, time.Duration) (entity.Hold, entity.Account, error)
+		CaptureHold(context.Context, int64, decimal.Decimal) (entity.Hold, entity.Account, error)
+		ReleaseHold(context.Context, int64) (entity.Hold, entity.Account, error)
+		GetHold(context.Context, int64) (entity.Hold, error)
+		ExpireHolds(context.Context, uint64) (int64, error)
+		CreateScheduledTransfer(context.Context, entity.ScheduledTransfer) (entity.ScheduledTransfer, error)
+		GetScheduledTransfer(context.Context, int64) (entity.ScheduledTransfer, error)
+		ListScheduledTransfers(context.Context, entity.ScheduledTransferFilter) ([]*entity.ScheduledTransfer, error)
+		UpdateScheduledTransfer(context.Context, int64, decimal.Decimal, time.Time) (entity.ScheduledTransfer, error)
+		CancelScheduledTransfer(context.Context, int64) (entity.ScheduledTransfer, error)
+		DueScheduledTransfers(context.Context, uint64) ([]int64, error)
+		ExecuteScheduledTransfer(context.Context, int64) (entity.ScheduledTransfer, error)
+		FailScheduledTransfer(context.Context, int64, entity.FailureCode, string, int, time.Duration) (entity.ScheduledTransfer, error)
+		CreateRecurringPlan(context.Context, entity.RecurringPlan) (entity.RecurringPlan, error)
+		GetRecurringPlan(context.Context, int64) (entity.RecurringPlan, error)
+		ListRecurringPlans(context.Context, entity.RecurringPlanFilter) ([]*entity.RecurringPlan, error)
+		CancelRecurringPlan(context.Context, int64) (entity.RecurringPlan, error)
+		ResumeRecurringPlan(context.Context, int64) (entity.RecurringPlan, error)
+		DueRecurringPlans(context.Context, uint64) ([]*entity.RecurringPlan, error)
+		RecordRecurringAttempt(context.Context, entity.RecurringPlan, entity.RecurringPlan, entity.RecurringAttempt) (entity.RecurringPlan, error)
+		GetRecurringAttempts(context.Context, int64, uint64, uint64) ([]*entity.RecurringAttempt, error)
+		CreateFeeRule(context.Context, entity.FeeRule) (entity.FeeRule, error)
+		ListFeeRules(context.Context) ([]*entity.FeeRule, error)
+		DisableFeeRule(context.Context, int64) (entity.FeeRule, error)
+		CreateVelocityLimit(context.Context, entity.VelocityLimit) (entity.VelocityLimit, error)
+		ListVelocityLimits(context.Context, int64) ([]*entity.VelocityLimit, error)
+		DisableVelocityLimit(context.Context, int64) (entity.VelocityLimit, error)
+		CreateCampaign(context.Context, entity.Campaign) (entity.Campaign, error)
+		GetCampaign(context.Context, int64) (entity.Campaign, error)
+		ListCampaigns(context.Context, entity.CampaignStatus) ([]*entity.Campaign, error)
+		AddCampaignBudget(context.Context, int64, decimal.Decimal) (entity.Campaign, error)
+		PauseCampaign(context.Context, int64) (entity.Campaign, error)
+		ResumeCampaign(context.Context, int64) (entity.Campaign, error)
+		CreateEscrow(context.Context, entity.Escrow, time.Duration) (entity.Escrow, entity.Account, error)
+		GetEscrow(context.Context, int64) (entity.Escrow, error)
+		ChangeEscrowStatus(context.Context, int64, entity.EscrowStatus, string) (entity.Escrow, entity.Account, error)
+		RefundExpiredEscrows(context.Context, uint64) (int64, error)
+		GetEscrowHistory(context.Context, int64) ([]*entity.EscrowChange, error)
+		CheckLedger(context.Context) (entity.LedgerCheck, error)
+		SystemAccounts(context.Context) ([]entity.LedgerAccount, error)
+	}
+
+	// RateProvider -.
+	RateProvider interface {
+		Rate(context.Context, string, string) (entity.Rate, error)
+	}
+
+	// RateRepo -.
+	RateRepo interface {
+		Create(context.Context, entity.Rate) (entity.Rate, error)
+		GetAt(context.Context, string, string, time.Time) (entity.Rate, error)
+		GetHistory(context.Context, string, string, uint64, uint64) ([]*entity.Rate, error)
 	}
 )
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// CheckLedger - check that sum of all postings is zero in every currency and in every journal entry.
+func (uc *AccountUseCase) CheckLedger(ctx context.Context) (check entity.LedgerCheck, err error) {
+	check, err = uc.repo.CheckLedger(ctx)
+	if err != nil {
+		return check, fmt.Errorf("AccountUseCase - CheckLedger - uc.repo.CheckLedger: %w", err)
+	}
+
+	return
+}
+
+// SystemAccounts - get chart of system accounts with their balances.
+func (uc *AccountUseCase) SystemAccounts(ctx context.Context) (accs []entity.LedgerAccount, err error) {
+	accs, err = uc.repo.SystemAccounts(ctx)
+	if err != nil {
+		return accs, fmt.Errorf("AccountUseCase - SystemAccounts - uc.repo.SystemAccounts: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,82 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CheckLedger(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name         string
+		prepare      func(f *fields)
+		wantBalanced bool
+		wantErr      bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CheckLedger(f.ctx).Return(entity.LedgerCheck{
+					Balanced: true,
+					Totals:   []entity.LedgerTotal{{Currency: "RUB", Sum: decimal.Zero}, {Currency: "USD", Sum: decimal.Zero}},
+				}, nil)
+			},
+			wantBalanced: true,
+			wantErr:      false,
+		},
+		{
+			name: "Case of correct work: journal entry is not balanced",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CheckLedger(f.ctx).Return(entity.LedgerCheck{
+					Balanced:           false,
+					Totals:             []entity.LedgerTotal{{Currency: "RUB", Sum: decimal.NewFromInt(5)}},
+					UnbalancedJournals: []int64{12},
+				}, nil)
+			},
+			wantBalanced: false,
+			wantErr:      false,
+		},
+		{
+			name: "Case of incorrect work: repository error",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CheckLedger(f.ctx).Return(entity.LedgerCheck{}, errors.New("connection refused"))
+			},
+			wantBalanced: false,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			check, err := uc.CheckLedger(f.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckLedger() check=%v error = %v, wantErr %v", check, err, tt.wantErr)
+			}
+			if check.Balanced != tt.wantBalanced {
+				t.Errorf("CheckLedger() balanced = %v, want %v", check.Balanced, tt.wantBalanced)
+			}
+		})
+	}
+}
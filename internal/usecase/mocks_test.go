@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/cut4cut/avito-test-work/internal/usecase (interfaces: AccountRepo)
+// Source: github.com/cut4cut/avito-test-work/internal/usecase (interfaces: AccountRepo,RateProvider,RateRepo)
 
 // Package usecase_test is a generated GoMock package.
 package usecase_test
@@ -7,9 +7,11 @@ package usecase_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/cut4cut/avito-test-work/internal/entity"
 	gomock "github.com/golang/mock/gomock"
+	decimal "github.com/shopspring/decimal"
 )
 
 // MockAccountRepo is a mock of AccountRepo interface.
@@ -35,19 +37,353 @@ func (m *MockAccountRepo) EXPECT() *MockAccountRepoMockRecorder {
 	return m.recorder
 }
 
+// AddCampaignBudget mocks base method.
+func (m *MockAccountRepo) AddCampaignBudget(arg0 context.Context, arg1 int64, arg2 decimal.Decimal) (entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCampaignBudget", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCampaignBudget indicates an expected call of AddCampaignBudget.
+func (mr *MockAccountRepoMockRecorder) AddCampaignBudget(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCampaignBudget", reflect.TypeOf((*MockAccountRepo)(nil).AddCampaignBudget), arg0, arg1, arg2)
+}
+
+// BatchTransfer mocks base method.
+func (m *MockAccountRepo) BatchTransfer(arg0 context.Context, arg1 []entity.TransferLeg, arg2 *entity.IdempotencyKey) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchTransfer", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchTransfer indicates an expected call of BatchTransfer.
+func (mr *MockAccountRepoMockRecorder) BatchTransfer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchTransfer", reflect.TypeOf((*MockAccountRepo)(nil).BatchTransfer), arg0, arg1, arg2)
+}
+
+// CancelRecurringPlan mocks base method.
+func (m *MockAccountRepo) CancelRecurringPlan(arg0 context.Context, arg1 int64) (entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelRecurringPlan", arg0, arg1)
+	ret0, _ := ret[0].(entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelRecurringPlan indicates an expected call of CancelRecurringPlan.
+func (mr *MockAccountRepoMockRecorder) CancelRecurringPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRecurringPlan", reflect.TypeOf((*MockAccountRepo)(nil).CancelRecurringPlan), arg0, arg1)
+}
+
+// CancelScheduledTransfer mocks base method.
+func (m *MockAccountRepo) CancelScheduledTransfer(arg0 context.Context, arg1 int64) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelScheduledTransfer indicates an expected call of CancelScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) CancelScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).CancelScheduledTransfer), arg0, arg1)
+}
+
+// CaptureHold mocks base method.
+func (m *MockAccountRepo) CaptureHold(arg0 context.Context, arg1 int64, arg2 decimal.Decimal) (entity.Hold, entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Hold)
+	ret1, _ := ret[1].(entity.Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockAccountRepoMockRecorder) CaptureHold(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockAccountRepo)(nil).CaptureHold), arg0, arg1, arg2)
+}
+
+// ChangeEscrowStatus mocks base method.
+func (m *MockAccountRepo) ChangeEscrowStatus(arg0 context.Context, arg1 int64, arg2 entity.EscrowStatus, arg3 string) (entity.Escrow, entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEscrowStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.Escrow)
+	ret1, _ := ret[1].(entity.Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ChangeEscrowStatus indicates an expected call of ChangeEscrowStatus.
+func (mr *MockAccountRepoMockRecorder) ChangeEscrowStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEscrowStatus", reflect.TypeOf((*MockAccountRepo)(nil).ChangeEscrowStatus), arg0, arg1, arg2, arg3)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockAccountRepo) ChangeStatus(arg0 context.Context, arg1 int64, arg2 entity.AccountStatus, arg3 string, arg4 int64) (entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockAccountRepoMockRecorder) ChangeStatus(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockAccountRepo)(nil).ChangeStatus), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CheckLedger mocks base method.
+func (m *MockAccountRepo) CheckLedger(arg0 context.Context) (entity.LedgerCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckLedger", arg0)
+	ret0, _ := ret[0].(entity.LedgerCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckLedger indicates an expected call of CheckLedger.
+func (mr *MockAccountRepoMockRecorder) CheckLedger(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLedger", reflect.TypeOf((*MockAccountRepo)(nil).CheckLedger), arg0)
+}
+
 // Create mocks base method.
-func (m *MockAccountRepo) Create(arg0 context.Context) (entity.Account, error) {
+func (m *MockAccountRepo) Create(arg0 context.Context, arg1, arg2 string, arg3 entity.Wallet) (entity.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", arg0)
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(entity.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockAccountRepoMockRecorder) Create(arg0 interface{}) *gomock.Call {
+func (mr *MockAccountRepoMockRecorder) Create(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountRepo)(nil).Create), arg0, arg1, arg2, arg3)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockAccountRepo) CreateCampaign(arg0 context.Context, arg1 entity.Campaign) (entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockAccountRepoMockRecorder) CreateCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockAccountRepo)(nil).CreateCampaign), arg0, arg1)
+}
+
+// CreateEscrow mocks base method.
+func (m *MockAccountRepo) CreateEscrow(arg0 context.Context, arg1 entity.Escrow, arg2 time.Duration) (entity.Escrow, entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEscrow", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Escrow)
+	ret1, _ := ret[1].(entity.Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateEscrow indicates an expected call of CreateEscrow.
+func (mr *MockAccountRepoMockRecorder) CreateEscrow(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrow", reflect.TypeOf((*MockAccountRepo)(nil).CreateEscrow), arg0, arg1, arg2)
+}
+
+// CreateFeeRule mocks base method.
+func (m *MockAccountRepo) CreateFeeRule(arg0 context.Context, arg1 entity.FeeRule) (entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeeRule", arg0, arg1)
+	ret0, _ := ret[0].(entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeeRule indicates an expected call of CreateFeeRule.
+func (mr *MockAccountRepoMockRecorder) CreateFeeRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeeRule", reflect.TypeOf((*MockAccountRepo)(nil).CreateFeeRule), arg0, arg1)
+}
+
+// CreateHold mocks base method.
+func (m *MockAccountRepo) CreateHold(arg0 context.Context, arg1 int64, arg2 string, arg3 decimal.Decimal, arg4 time.Duration) (entity.Hold, entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(entity.Hold)
+	ret1, _ := ret[1].(entity.Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockAccountRepoMockRecorder) CreateHold(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockAccountRepo)(nil).CreateHold), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CreateRecurringPlan mocks base method.
+func (m *MockAccountRepo) CreateRecurringPlan(arg0 context.Context, arg1 entity.RecurringPlan) (entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecurringPlan", arg0, arg1)
+	ret0, _ := ret[0].(entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecurringPlan indicates an expected call of CreateRecurringPlan.
+func (mr *MockAccountRepoMockRecorder) CreateRecurringPlan(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccountRepo)(nil).Create), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecurringPlan", reflect.TypeOf((*MockAccountRepo)(nil).CreateRecurringPlan), arg0, arg1)
+}
+
+// CreateScheduledTransfer mocks base method.
+func (m *MockAccountRepo) CreateScheduledTransfer(arg0 context.Context, arg1 entity.ScheduledTransfer) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransfer indicates an expected call of CreateScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) CreateScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).CreateScheduledTransfer), arg0, arg1)
+}
+
+// CreateVelocityLimit mocks base method.
+func (m *MockAccountRepo) CreateVelocityLimit(arg0 context.Context, arg1 entity.VelocityLimit) (entity.VelocityLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVelocityLimit", arg0, arg1)
+	ret0, _ := ret[0].(entity.VelocityLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVelocityLimit indicates an expected call of CreateVelocityLimit.
+func (mr *MockAccountRepoMockRecorder) CreateVelocityLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVelocityLimit", reflect.TypeOf((*MockAccountRepo)(nil).CreateVelocityLimit), arg0, arg1)
+}
+
+// DisableFeeRule mocks base method.
+func (m *MockAccountRepo) DisableFeeRule(arg0 context.Context, arg1 int64) (entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableFeeRule", arg0, arg1)
+	ret0, _ := ret[0].(entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableFeeRule indicates an expected call of DisableFeeRule.
+func (mr *MockAccountRepoMockRecorder) DisableFeeRule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableFeeRule", reflect.TypeOf((*MockAccountRepo)(nil).DisableFeeRule), arg0, arg1)
+}
+
+// DisableVelocityLimit mocks base method.
+func (m *MockAccountRepo) DisableVelocityLimit(arg0 context.Context, arg1 int64) (entity.VelocityLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableVelocityLimit", arg0, arg1)
+	ret0, _ := ret[0].(entity.VelocityLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableVelocityLimit indicates an expected call of DisableVelocityLimit.
+func (mr *MockAccountRepoMockRecorder) DisableVelocityLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableVelocityLimit", reflect.TypeOf((*MockAccountRepo)(nil).DisableVelocityLimit), arg0, arg1)
+}
+
+// DueRecurringPlans mocks base method.
+func (m *MockAccountRepo) DueRecurringPlans(arg0 context.Context, arg1 uint64) ([]*entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueRecurringPlans", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueRecurringPlans indicates an expected call of DueRecurringPlans.
+func (mr *MockAccountRepoMockRecorder) DueRecurringPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueRecurringPlans", reflect.TypeOf((*MockAccountRepo)(nil).DueRecurringPlans), arg0, arg1)
+}
+
+// DueScheduledTransfers mocks base method.
+func (m *MockAccountRepo) DueScheduledTransfers(arg0 context.Context, arg1 uint64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DueScheduledTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DueScheduledTransfers indicates an expected call of DueScheduledTransfers.
+func (mr *MockAccountRepoMockRecorder) DueScheduledTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DueScheduledTransfers", reflect.TypeOf((*MockAccountRepo)(nil).DueScheduledTransfers), arg0, arg1)
+}
+
+// ExecuteScheduledTransfer mocks base method.
+func (m *MockAccountRepo) ExecuteScheduledTransfer(arg0 context.Context, arg1 int64) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteScheduledTransfer indicates an expected call of ExecuteScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) ExecuteScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).ExecuteScheduledTransfer), arg0, arg1)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockAccountRepo) ExpireHolds(arg0 context.Context, arg1 uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockAccountRepoMockRecorder) ExpireHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockAccountRepo)(nil).ExpireHolds), arg0, arg1)
+}
+
+// FailScheduledTransfer mocks base method.
+func (m *MockAccountRepo) FailScheduledTransfer(arg0 context.Context, arg1 int64, arg2 entity.FailureCode, arg3 string, arg4 int, arg5 time.Duration) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailScheduledTransfer", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailScheduledTransfer indicates an expected call of FailScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) FailScheduledTransfer(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).FailScheduledTransfer), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // GetById mocks base method.
@@ -65,25 +401,447 @@ func (mr *MockAccountRepoMockRecorder) GetById(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAccountRepo)(nil).GetById), arg0, arg1)
 }
 
+// GetByOwner mocks base method.
+func (m *MockAccountRepo) GetByOwner(arg0 context.Context, arg1 string, arg2 entity.Wallet) (entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOwner", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOwner indicates an expected call of GetByOwner.
+func (mr *MockAccountRepoMockRecorder) GetByOwner(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOwner", reflect.TypeOf((*MockAccountRepo)(nil).GetByOwner), arg0, arg1, arg2)
+}
+
+// GetCampaign mocks base method.
+func (m *MockAccountRepo) GetCampaign(arg0 context.Context, arg1 int64) (entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", arg0, arg1)
+	ret0, _ := ret[0].(entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockAccountRepoMockRecorder) GetCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockAccountRepo)(nil).GetCampaign), arg0, arg1)
+}
+
+// GetEscrow mocks base method.
+func (m *MockAccountRepo) GetEscrow(arg0 context.Context, arg1 int64) (entity.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrow", arg0, arg1)
+	ret0, _ := ret[0].(entity.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrow indicates an expected call of GetEscrow.
+func (mr *MockAccountRepoMockRecorder) GetEscrow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrow", reflect.TypeOf((*MockAccountRepo)(nil).GetEscrow), arg0, arg1)
+}
+
+// GetEscrowHistory mocks base method.
+func (m *MockAccountRepo) GetEscrowHistory(arg0 context.Context, arg1 int64) ([]*entity.EscrowChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrowHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.EscrowChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrowHistory indicates an expected call of GetEscrowHistory.
+func (mr *MockAccountRepoMockRecorder) GetEscrowHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrowHistory", reflect.TypeOf((*MockAccountRepo)(nil).GetEscrowHistory), arg0, arg1)
+}
+
 // GetHistory mocks base method.
-func (m *MockAccountRepo) GetHistory(arg0 context.Context, arg1 int64, arg2, arg3 uint64, arg4 string, arg5 bool) ([]*entity.Transaction, error) {
+func (m *MockAccountRepo) GetHistory(arg0 context.Context, arg1 int64, arg2, arg3 uint64, arg4 string, arg5 bool, arg6 entity.TransactionFilter) ([]*entity.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].([]*entity.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetHistory indicates an expected call of GetHistory.
-func (mr *MockAccountRepoMockRecorder) GetHistory(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+func (mr *MockAccountRepoMockRecorder) GetHistory(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockAccountRepo)(nil).GetHistory), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// GetHold mocks base method.
+func (m *MockAccountRepo) GetHold(arg0 context.Context, arg1 int64) (entity.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", arg0, arg1)
+	ret0, _ := ret[0].(entity.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockAccountRepoMockRecorder) GetHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockAccountRepo)(nil).GetHold), arg0, arg1)
+}
+
+// GetRecurringAttempts mocks base method.
+func (m *MockAccountRepo) GetRecurringAttempts(arg0 context.Context, arg1 int64, arg2, arg3 uint64) ([]*entity.RecurringAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringAttempts", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*entity.RecurringAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringAttempts indicates an expected call of GetRecurringAttempts.
+func (mr *MockAccountRepoMockRecorder) GetRecurringAttempts(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringAttempts", reflect.TypeOf((*MockAccountRepo)(nil).GetRecurringAttempts), arg0, arg1, arg2, arg3)
+}
+
+// GetRecurringPlan mocks base method.
+func (m *MockAccountRepo) GetRecurringPlan(arg0 context.Context, arg1 int64) (entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringPlan", arg0, arg1)
+	ret0, _ := ret[0].(entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringPlan indicates an expected call of GetRecurringPlan.
+func (mr *MockAccountRepoMockRecorder) GetRecurringPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringPlan", reflect.TypeOf((*MockAccountRepo)(nil).GetRecurringPlan), arg0, arg1)
+}
+
+// GetScheduledTransfer mocks base method.
+func (m *MockAccountRepo) GetScheduledTransfer(arg0 context.Context, arg1 int64) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransfer", arg0, arg1)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransfer indicates an expected call of GetScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) GetScheduledTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).GetScheduledTransfer), arg0, arg1)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockAccountRepo) GetStatusHistory(arg0 context.Context, arg1 int64) ([]*entity.StatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.StatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockAccountRepoMockRecorder) GetStatusHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockAccountRepo)(nil).GetStatusHistory), arg0, arg1)
+}
+
+// GetTransaction mocks base method.
+func (m *MockAccountRepo) GetTransaction(arg0 context.Context, arg1 int64) (entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", arg0, arg1)
+	ret0, _ := ret[0].(entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockAccountRepoMockRecorder) GetTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockAccountRepo)(nil).GetTransaction), arg0, arg1)
+}
+
+// GetWallets mocks base method.
+func (m *MockAccountRepo) GetWallets(arg0 context.Context, arg1 string) ([]entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWallets", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWallets indicates an expected call of GetWallets.
+func (mr *MockAccountRepoMockRecorder) GetWallets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWallets", reflect.TypeOf((*MockAccountRepo)(nil).GetWallets), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockAccountRepo) List(arg0 context.Context, arg1 entity.AccountFilter) ([]entity.Account, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Account)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockAccountRepoMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAccountRepo)(nil).List), arg0, arg1)
+}
+
+// ListCampaigns mocks base method.
+func (m *MockAccountRepo) ListCampaigns(arg0 context.Context, arg1 entity.CampaignStatus) ([]*entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCampaigns", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCampaigns indicates an expected call of ListCampaigns.
+func (mr *MockAccountRepoMockRecorder) ListCampaigns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCampaigns", reflect.TypeOf((*MockAccountRepo)(nil).ListCampaigns), arg0, arg1)
+}
+
+// ListFeeRules mocks base method.
+func (m *MockAccountRepo) ListFeeRules(arg0 context.Context) ([]*entity.FeeRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFeeRules", arg0)
+	ret0, _ := ret[0].([]*entity.FeeRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeeRules indicates an expected call of ListFeeRules.
+func (mr *MockAccountRepoMockRecorder) ListFeeRules(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeeRules", reflect.TypeOf((*MockAccountRepo)(nil).ListFeeRules), arg0)
+}
+
+// ListRecurringPlans mocks base method.
+func (m *MockAccountRepo) ListRecurringPlans(arg0 context.Context, arg1 entity.RecurringPlanFilter) ([]*entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecurringPlans", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecurringPlans indicates an expected call of ListRecurringPlans.
+func (mr *MockAccountRepoMockRecorder) ListRecurringPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecurringPlans", reflect.TypeOf((*MockAccountRepo)(nil).ListRecurringPlans), arg0, arg1)
+}
+
+// ListScheduledTransfers mocks base method.
+func (m *MockAccountRepo) ListScheduledTransfers(arg0 context.Context, arg1 entity.ScheduledTransferFilter) ([]*entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledTransfers indicates an expected call of ListScheduledTransfers.
+func (mr *MockAccountRepoMockRecorder) ListScheduledTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledTransfers", reflect.TypeOf((*MockAccountRepo)(nil).ListScheduledTransfers), arg0, arg1)
+}
+
+// ListVelocityLimits mocks base method.
+func (m *MockAccountRepo) ListVelocityLimits(arg0 context.Context, arg1 int64) ([]*entity.VelocityLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVelocityLimits", arg0, arg1)
+	ret0, _ := ret[0].([]*entity.VelocityLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVelocityLimits indicates an expected call of ListVelocityLimits.
+func (mr *MockAccountRepoMockRecorder) ListVelocityLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVelocityLimits", reflect.TypeOf((*MockAccountRepo)(nil).ListVelocityLimits), arg0, arg1)
+}
+
+// PauseCampaign mocks base method.
+func (m *MockAccountRepo) PauseCampaign(arg0 context.Context, arg1 int64) (entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseCampaign", arg0, arg1)
+	ret0, _ := ret[0].(entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseCampaign indicates an expected call of PauseCampaign.
+func (mr *MockAccountRepoMockRecorder) PauseCampaign(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseCampaign", reflect.TypeOf((*MockAccountRepo)(nil).PauseCampaign), arg0, arg1)
+}
+
+// RecordRecurringAttempt mocks base method.
+func (m *MockAccountRepo) RecordRecurringAttempt(arg0 context.Context, arg1, arg2 entity.RecurringPlan, arg3 entity.RecurringAttempt) (entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRecurringAttempt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordRecurringAttempt indicates an expected call of RecordRecurringAttempt.
+func (mr *MockAccountRepoMockRecorder) RecordRecurringAttempt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRecurringAttempt", reflect.TypeOf((*MockAccountRepo)(nil).RecordRecurringAttempt), arg0, arg1, arg2, arg3)
+}
+
+// RefundExpiredEscrows mocks base method.
+func (m *MockAccountRepo) RefundExpiredEscrows(arg0 context.Context, arg1 uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundExpiredEscrows", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundExpiredEscrows indicates an expected call of RefundExpiredEscrows.
+func (mr *MockAccountRepoMockRecorder) RefundExpiredEscrows(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundExpiredEscrows", reflect.TypeOf((*MockAccountRepo)(nil).RefundExpiredEscrows), arg0, arg1)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockAccountRepo) ReleaseHold(arg0 context.Context, arg1 int64) (entity.Hold, entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0, arg1)
+	ret0, _ := ret[0].(entity.Hold)
+	ret1, _ := ret[1].(entity.Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockAccountRepoMockRecorder) ReleaseHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockAccountRepo)(nil).ReleaseHold), arg0, arg1)
+}
+
+// ResumeCampaign mocks base method.
+func (m *MockAccountRepo) ResumeCampaign(arg0 context.Context, arg1 int64) (entity.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeCampaign", arg0, arg1)
+	ret0, _ := ret[0].(entity.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeCampaign indicates an expected call of ResumeCampaign.
+func (mr *MockAccountRepoMockRecorder) ResumeCampaign(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockAccountRepo)(nil).GetHistory), arg0, arg1, arg2, arg3, arg4, arg5)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeCampaign", reflect.TypeOf((*MockAccountRepo)(nil).ResumeCampaign), arg0, arg1)
+}
+
+// ResumeRecurringPlan mocks base method.
+func (m *MockAccountRepo) ResumeRecurringPlan(arg0 context.Context, arg1 int64) (entity.RecurringPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeRecurringPlan", arg0, arg1)
+	ret0, _ := ret[0].(entity.RecurringPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeRecurringPlan indicates an expected call of ResumeRecurringPlan.
+func (mr *MockAccountRepoMockRecorder) ResumeRecurringPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeRecurringPlan", reflect.TypeOf((*MockAccountRepo)(nil).ResumeRecurringPlan), arg0, arg1)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockAccountRepo) ReverseTransaction(arg0 context.Context, arg1 int64, arg2 decimal.Decimal) ([]*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockAccountRepoMockRecorder) ReverseTransaction(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockAccountRepo)(nil).ReverseTransaction), arg0, arg1, arg2)
+}
+
+// SetCreditLimit mocks base method.
+func (m *MockAccountRepo) SetCreditLimit(arg0 context.Context, arg1 int64, arg2 decimal.Decimal) (entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCreditLimit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCreditLimit indicates an expected call of SetCreditLimit.
+func (mr *MockAccountRepoMockRecorder) SetCreditLimit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCreditLimit", reflect.TypeOf((*MockAccountRepo)(nil).SetCreditLimit), arg0, arg1, arg2)
+}
+
+// SetTier mocks base method.
+func (m *MockAccountRepo) SetTier(arg0 context.Context, arg1 int64, arg2 entity.AccountTier) (entity.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTier", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTier indicates an expected call of SetTier.
+func (mr *MockAccountRepoMockRecorder) SetTier(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTier", reflect.TypeOf((*MockAccountRepo)(nil).SetTier), arg0, arg1, arg2)
+}
+
+// SplitTransfer mocks base method.
+func (m *MockAccountRepo) SplitTransfer(arg0 context.Context, arg1 int64, arg2 []entity.SplitShare, arg3 entity.TransactionMeta, arg4 *entity.IdempotencyKey) (entity.SplitTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SplitTransfer", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(entity.SplitTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitTransfer indicates an expected call of SplitTransfer.
+func (mr *MockAccountRepoMockRecorder) SplitTransfer(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitTransfer", reflect.TypeOf((*MockAccountRepo)(nil).SplitTransfer), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SystemAccounts mocks base method.
+func (m *MockAccountRepo) SystemAccounts(arg0 context.Context) ([]entity.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SystemAccounts", arg0)
+	ret0, _ := ret[0].([]entity.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SystemAccounts indicates an expected call of SystemAccounts.
+func (mr *MockAccountRepoMockRecorder) SystemAccounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SystemAccounts", reflect.TypeOf((*MockAccountRepo)(nil).SystemAccounts), arg0)
 }
 
 // TransferAmount mocks base method.
-func (m *MockAccountRepo) TransferAmount(arg0 context.Context, arg1, arg2 int64, arg3 float64) (entity.Account, entity.Account, error) {
+func (m *MockAccountRepo) TransferAmount(arg0 context.Context, arg1, arg2 entity.AccountRef, arg3, arg4 decimal.Decimal, arg5 *entity.Rate, arg6 entity.TransactionMeta, arg7 *entity.IdempotencyKey) (entity.Account, entity.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferAmount", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "TransferAmount", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	ret0, _ := ret[0].(entity.Account)
 	ret1, _ := ret[1].(entity.Account)
 	ret2, _ := ret[2].(error)
@@ -91,22 +849,143 @@ func (m *MockAccountRepo) TransferAmount(arg0 context.Context, arg1, arg2 int64,
 }
 
 // TransferAmount indicates an expected call of TransferAmount.
-func (mr *MockAccountRepoMockRecorder) TransferAmount(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockAccountRepoMockRecorder) TransferAmount(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAmount", reflect.TypeOf((*MockAccountRepo)(nil).TransferAmount), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAmount", reflect.TypeOf((*MockAccountRepo)(nil).TransferAmount), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
 
 // UpdBalance mocks base method.
-func (m *MockAccountRepo) UpdBalance(arg0 context.Context, arg1, arg2 int64, arg3 float64) (entity.Account, error) {
+func (m *MockAccountRepo) UpdBalance(arg0 context.Context, arg1 entity.AccountRef, arg2 decimal.Decimal, arg3 string, arg4 entity.SystemAccount, arg5 entity.TransactionMeta, arg6 *entity.IdempotencyKey) (entity.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdBalance", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "UpdBalance", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 	ret0, _ := ret[0].(entity.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdBalance indicates an expected call of UpdBalance.
-func (mr *MockAccountRepoMockRecorder) UpdBalance(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockAccountRepoMockRecorder) UpdBalance(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdBalance", reflect.TypeOf((*MockAccountRepo)(nil).UpdBalance), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// UpdateScheduledTransfer mocks base method.
+func (m *MockAccountRepo) UpdateScheduledTransfer(arg0 context.Context, arg1 int64, arg2 decimal.Decimal, arg3 time.Time) (entity.ScheduledTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledTransfer", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.ScheduledTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledTransfer indicates an expected call of UpdateScheduledTransfer.
+func (mr *MockAccountRepoMockRecorder) UpdateScheduledTransfer(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledTransfer", reflect.TypeOf((*MockAccountRepo)(nil).UpdateScheduledTransfer), arg0, arg1, arg2, arg3)
+}
+
+// MockRateProvider is a mock of RateProvider interface.
+type MockRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateProviderMockRecorder
+}
+
+// MockRateProviderMockRecorder is the mock recorder for MockRateProvider.
+type MockRateProviderMockRecorder struct {
+	mock *MockRateProvider
+}
+
+// NewMockRateProvider creates a new mock instance.
+func NewMockRateProvider(ctrl *gomock.Controller) *MockRateProvider {
+	mock := &MockRateProvider{ctrl: ctrl}
+	mock.recorder = &MockRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateProvider) EXPECT() *MockRateProviderMockRecorder {
+	return m.recorder
+}
+
+// Rate mocks base method.
+func (m *MockRateProvider) Rate(arg0 context.Context, arg1, arg2 string) (entity.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockRateProviderMockRecorder) Rate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockRateProvider)(nil).Rate), arg0, arg1, arg2)
+}
+
+// MockRateRepo is a mock of RateRepo interface.
+type MockRateRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateRepoMockRecorder
+}
+
+// MockRateRepoMockRecorder is the mock recorder for MockRateRepo.
+type MockRateRepoMockRecorder struct {
+	mock *MockRateRepo
+}
+
+// NewMockRateRepo creates a new mock instance.
+func NewMockRateRepo(ctrl *gomock.Controller) *MockRateRepo {
+	mock := &MockRateRepo{ctrl: ctrl}
+	mock.recorder = &MockRateRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateRepo) EXPECT() *MockRateRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRateRepo) Create(arg0 context.Context, arg1 entity.Rate) (entity.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(entity.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRateRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRateRepo)(nil).Create), arg0, arg1)
+}
+
+// GetAt mocks base method.
+func (m *MockRateRepo) GetAt(arg0 context.Context, arg1, arg2 string, arg3 time.Time) (entity.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAt indicates an expected call of GetAt.
+func (mr *MockRateRepoMockRecorder) GetAt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAt", reflect.TypeOf((*MockRateRepo)(nil).GetAt), arg0, arg1, arg2, arg3)
+}
+
+// GetHistory mocks base method.
+func (m *MockRateRepo) GetHistory(arg0 context.Context, arg1, arg2 string, arg3, arg4 uint64) ([]*entity.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*entity.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockRateRepoMockRecorder) GetHistory(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdBalance", reflect.TypeOf((*MockAccountRepo)(nil).UpdBalance), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockRateRepo)(nil).GetHistory), arg0, arg1, arg2, arg3, arg4)
 }
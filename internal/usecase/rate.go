@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// RateUseCase - use case with history of exchange rates.
+type RateUseCase struct {
+	repo RateRepo
+}
+
+// NewRate - create new exchange rate use case.
+func NewRate(r RateRepo) *RateUseCase {
+	return &RateUseCase{
+		repo: r,
+	}
+}
+
+func (uc *RateUseCase) pairValidation(base, quote string) (baseCur, quoteCur entity.Currency, err error) {
+	baseCur, okBase := entity.CurrencyByCode(base)
+	quoteCur, okQuote := entity.CurrencyByCode(quote)
+
+	if !okBase || !okQuote {
+		err = ErrorUnknownCurrency
+	} else if baseCur.Code == quoteCur.Code {
+		err = ErrorSameBaseQuote
+	}
+
+	return
+}
+
+// Create - add new rate of currency pair with spread valid from the moment, the current moment is used by default.
+// Rate history is append-only, so the moment can not be in the past.
+func (uc *RateUseCase) Create(ctx context.Context, base, quote string, value, spread decimal.Decimal, validFrom time.Time) (rate entity.Rate, err error) {
+	baseCur, quoteCur, err := uc.pairValidation(base, quote)
+	if err != nil {
+		return rate, fmt.Errorf("RateUseCase - Create - uc.pairValidation: %w", err)
+	}
+
+	if !value.IsPositive() {
+		return rate, fmt.Errorf("RateUseCase - Create - validation: %w", ErrorRateIsNotPositive)
+	}
+
+	if spread.IsNegative() || spread.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return rate, fmt.Errorf("RateUseCase - Create - validation: %w", ErrorSpreadOutOfRange)
+	}
+
+	if validFrom.IsZero() {
+		validFrom = time.Now()
+	} else if validFrom.Before(time.Now()) {
+		return rate, fmt.Errorf("RateUseCase - Create - validation: %w", ErrorValidFromInPast)
+	}
+
+	rate, err = uc.repo.Create(ctx, entity.Rate{Base: baseCur.Code, Quote: quoteCur.Code, Value: value, Spread: spread, ValidFrom: validFrom})
+	if err != nil {
+		return rate, fmt.Errorf("RateUseCase - Create - uc.repo.Create: %w", err)
+	}
+
+	return
+}
+
+// GetAt - get rate of currency pair which was valid at the moment, the current moment is used by default.
+func (uc *RateUseCase) GetAt(ctx context.Context, base, quote string, at time.Time) (rate entity.Rate, err error) {
+	baseCur, quoteCur, err := uc.pairValidation(base, quote)
+	if err != nil {
+		return rate, fmt.Errorf("RateUseCase - GetAt - uc.pairValidation: %w", err)
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	rate, err = uc.repo.GetAt(ctx, baseCur.Code, quoteCur.Code, at)
+	if err != nil {
+		return rate, fmt.Errorf("RateUseCase - GetAt - uc.repo.GetAt: %w", err)
+	}
+
+	return
+}
+
+// GetHistory - get history of currency pair's rates.
+func (uc *RateUseCase) GetHistory(ctx context.Context, base, quote string, limit, offset uint64) (rates []*entity.Rate, err error) {
+	baseCur, quoteCur, err := uc.pairValidation(base, quote)
+	if err != nil {
+		return rates, fmt.Errorf("RateUseCase - GetHistory - uc.pairValidation: %w", err)
+	}
+
+	rates, err = uc.repo.GetHistory(ctx, baseCur.Code, quoteCur.Code, limit, offset)
+	if err != nil {
+		return rates, fmt.Errorf("RateUseCase - GetHistory - uc.repo.GetHistory: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+type cachedRate struct {
+	rate      entity.Rate
+	expiresDt time.Time
+}
+
+// RateCache - rate provider which keeps received rates for TTL.
+type RateCache struct {
+	provider RateProvider
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	rates map[string]cachedRate
+}
+
+var _ RateProvider = (*RateCache)(nil)
+
+// NewRateCache - wrap rate provider with cache.
+func NewRateCache(p RateProvider, ttl time.Duration) *RateCache {
+	return &RateCache{
+		provider: p,
+		ttl:      ttl,
+		rates:    make(map[string]cachedRate),
+	}
+}
+
+// Rate - get rate from cache or from provider when cached rate is expired.
+func (c *RateCache) Rate(ctx context.Context, base, quote string) (rate entity.Rate, err error) {
+	key := base + "/" + quote
+
+	c.mu.Lock()
+	cached, ok := c.rates[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresDt) {
+		return cached.rate, nil
+	}
+
+	rate, err = c.provider.Rate(ctx, base, quote)
+	if err != nil {
+		return rate, fmt.Errorf("RateCache - Rate - c.provider.Rate: %w", err)
+	}
+
+	c.mu.Lock()
+	c.rates[key] = cachedRate{rate: rate, expiresDt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return
+}
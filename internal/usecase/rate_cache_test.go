@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestRateCache_Rate(t *testing.T) {
+	type fields struct {
+		ctx   context.Context
+		rates *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		ttl     time.Duration
+		calls   int
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: rate is taken from cache",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(entity.Rate{Base: "USD", Quote: "RUB", Value: decimal.NewFromInt(62), ValidFrom: time.Now()}, nil).Times(1)
+			},
+			ttl:     time.Minute,
+			calls:   3,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: cached rate is expired",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(entity.Rate{Base: "USD", Quote: "RUB", Value: decimal.NewFromInt(62), ValidFrom: time.Now()}, nil).Times(3)
+			},
+			ttl:     0,
+			calls:   3,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: provider error is not cached",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(entity.Rate{}, usecase.ErrorUnknownCurrency).Times(2)
+			},
+			ttl:     time.Minute,
+			calls:   2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:   context.Background(),
+				rates: NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			c := usecase.NewRateCache(f.rates, tt.ttl)
+			for i := 0; i < tt.calls; i++ {
+				if rate, err := c.Rate(f.ctx, "USD", "RUB"); (err != nil) != tt.wantErr {
+					t.Errorf("Rate() rate=%v error = %v, wantErr %v", rate, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
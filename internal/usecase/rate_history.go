@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// RateHistory - rate provider which stores received rates in rate history,
+// so conversion with rate of file or http provider records ID of rate.
+type RateHistory struct {
+	provider RateProvider
+	repo     RateRepo
+}
+
+var _ RateProvider = (*RateHistory)(nil)
+
+// NewRateHistory - wrap rate provider with rate history.
+func NewRateHistory(p RateProvider, r RateRepo) *RateHistory {
+	return &RateHistory{
+		provider: p,
+		repo:     r,
+	}
+}
+
+// Rate - get rate from provider and return current rate of history when it is the same,
+// otherwise received rate is added to history valid from the current moment.
+// Rate of history is not cached, so conversion always uses rate which is valid now.
+func (h *RateHistory) Rate(ctx context.Context, base, quote string) (rate entity.Rate, err error) {
+	received, err := h.provider.Rate(ctx, base, quote)
+	if err != nil {
+		return rate, fmt.Errorf("RateHistory - Rate - h.provider.Rate: %w", err)
+	}
+
+	now := time.Now()
+
+	stored, err := h.repo.GetAt(ctx, base, quote, now)
+	if err == nil && stored.Value.Equal(received.Value) && stored.Spread.Equal(received.Spread) {
+		return stored, nil
+	}
+
+	rate, err = h.repo.Create(ctx, entity.Rate{Base: base, Quote: quote, Value: received.Value, Spread: received.Spread, ValidFrom: now})
+	if errors.Is(err, entity.ErrRateConflict) {
+		// rate is added concurrently or later rate is already in history, current rate of history is used
+		rate, err = h.repo.GetAt(ctx, base, quote, now)
+		if err != nil {
+			return rate, fmt.Errorf("RateHistory - Rate - h.repo.GetAt: %w", err)
+		}
+
+		return
+	}
+	if err != nil {
+		return rate, fmt.Errorf("RateHistory - Rate - h.repo.Create: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,119 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestRateHistory_Rate(t *testing.T) {
+	type fields struct {
+		ctx   context.Context
+		rates *MockRateProvider
+		repo  *MockRateRepo
+	}
+	received := entity.Rate{Base: "USD", Quote: "RUB", Value: decimal.NewFromInt(62), Spread: decimal.RequireFromString("0.01")}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		wantId  int64
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: rate is the same as current rate of history",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(received, nil)
+				f.repo.EXPECT().GetAt(f.ctx, "USD", "RUB", gomock.Any()).Return(entity.Rate{Id: 3, Base: "USD", Quote: "RUB", Value: decimal.RequireFromString("62.000"), Spread: decimal.RequireFromString("0.01")}, nil)
+			},
+			wantId:  3,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: changed rate is added to history",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(received, nil)
+				f.repo.EXPECT().GetAt(f.ctx, "USD", "RUB", gomock.Any()).Return(entity.Rate{Id: 3, Base: "USD", Quote: "RUB", Value: decimal.NewFromInt(61), Spread: decimal.RequireFromString("0.01")}, nil)
+				f.repo.EXPECT().Create(f.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rate entity.Rate) (entity.Rate, error) {
+					if !rate.Value.Equal(received.Value) || !rate.Spread.Equal(received.Spread) || time.Since(rate.ValidFrom) > time.Minute {
+						t.Errorf("Create() rate = %v, want %v valid from now", rate, received)
+					}
+					rate.Id = 4
+
+					return rate, nil
+				})
+			},
+			wantId:  4,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: pair has no rate in history",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(received, nil)
+				f.repo.EXPECT().GetAt(f.ctx, "USD", "RUB", gomock.Any()).Return(entity.Rate{}, entity.ErrRateNotFound)
+				f.repo.EXPECT().Create(f.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rate entity.Rate) (entity.Rate, error) {
+					rate.Id = 1
+
+					return rate, nil
+				})
+			},
+			wantId:  1,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: later rate is already in history",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(received, nil)
+				f.repo.EXPECT().GetAt(f.ctx, "USD", "RUB", gomock.Any()).Return(entity.Rate{Id: 3, Base: "USD", Quote: "RUB", Value: decimal.NewFromInt(61)}, nil).Times(2)
+				f.repo.EXPECT().Create(f.ctx, gomock.Any()).Return(entity.Rate{}, entity.ErrRateConflict)
+			},
+			wantId:  3,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: provider error",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(entity.Rate{}, entity.ErrRateNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: rate is not stored",
+			prepare: func(f *fields) {
+				f.rates.EXPECT().Rate(f.ctx, "USD", "RUB").Return(received, nil)
+				f.repo.EXPECT().GetAt(f.ctx, "USD", "RUB", gomock.Any()).Return(entity.Rate{}, entity.ErrRateNotFound)
+				f.repo.EXPECT().Create(f.ctx, gomock.Any()).Return(entity.Rate{}, errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:   context.Background(),
+				rates: NewMockRateProvider(ctrl),
+				repo:  NewMockRateRepo(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			h := usecase.NewRateHistory(f.rates, f.repo)
+			rate, err := h.Rate(f.ctx, "USD", "RUB")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if rate.Id != tt.wantId {
+				t.Errorf("Rate() rate id = %v, want %v", rate.Id, tt.wantId)
+			}
+		})
+	}
+}
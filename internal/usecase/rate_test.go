@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestRateUseCase_Create(t *testing.T) {
+	validFrom := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	type fields struct {
+		ctx      context.Context
+		rateRepo *MockRateRepo
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    string
+		arg2    string
+		arg3    decimal.Decimal
+		arg4    decimal.Decimal
+		arg5    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				rate := entity.Rate{Base: "USD", Quote: "RUB", Value: decimal.RequireFromString("62.5"), Spread: decimal.Zero, ValidFrom: validFrom}
+				f.rateRepo.EXPECT().Create(f.ctx, rate).Return(entity.Rate{Id: 1, Base: "USD", Quote: "RUB", Value: decimal.RequireFromString("62.5"), ValidFrom: validFrom}, nil)
+			},
+			arg1:    "usd",
+			arg2:    "RUB",
+			arg3:    decimal.RequireFromString("62.5"),
+			arg4:    decimal.Zero,
+			arg5:    validFrom,
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    "XYZ",
+			arg2:    "RUB",
+			arg3:    decimal.RequireFromString("62.5"),
+			arg4:    decimal.Zero,
+			arg5:    validFrom,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: same currencies",
+			prepare: func(f *fields) {},
+			arg1:    "RUB",
+			arg2:    "RUB",
+			arg3:    decimal.RequireFromString("1"),
+			arg4:    decimal.Zero,
+			arg5:    validFrom,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: spread is out of range",
+			prepare: func(f *fields) {},
+			arg1:    "USD",
+			arg2:    "RUB",
+			arg3:    decimal.RequireFromString("62.5"),
+			arg4:    decimal.NewFromInt(1),
+			arg5:    validFrom,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: start of validity is in the past",
+			prepare: func(f *fields) {},
+			arg1:    "USD",
+			arg2:    "RUB",
+			arg3:    decimal.RequireFromString("62.5"),
+			arg4:    decimal.Zero,
+			arg5:    time.Date(2022, 7, 11, 0, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: rate is zero",
+			prepare: func(f *fields) {},
+			arg1:    "USD",
+			arg2:    "RUB",
+			arg3:    decimal.Zero,
+			arg4:    decimal.Zero,
+			arg5:    validFrom,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:      context.Background(),
+				rateRepo: NewMockRateRepo(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.NewRate(f.rateRepo)
+			if rate, err := uc.Create(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5); (err != nil) != tt.wantErr {
+				t.Errorf("Create() rate=%v error = %v, wantErr %v", rate, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRateUseCase_GetAt(t *testing.T) {
+	at := time.Date(2022, 7, 12, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		ctx      context.Context
+		rateRepo *MockRateRepo
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    string
+		arg2    string
+		arg3    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.rateRepo.EXPECT().GetAt(f.ctx, "USD", "RUB", at).Return(entity.Rate{Id: 1, Base: "USD", Quote: "RUB", Value: decimal.RequireFromString("62.5"), ValidFrom: at.Add(-time.Hour)}, nil)
+			},
+			arg1:    "USD",
+			arg2:    "RUB",
+			arg3:    at,
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    "USD",
+			arg2:    "XYZ",
+			arg3:    at,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:      context.Background(),
+				rateRepo: NewMockRateRepo(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.NewRate(f.rateRepo)
+			if rate, err := uc.GetAt(f.ctx, tt.arg1, tt.arg2, tt.arg3); (err != nil) != tt.wantErr {
+				t.Errorf("GetAt() rate=%v error = %v, wantErr %v", rate, err, tt.wantErr)
+			}
+		})
+	}
+}
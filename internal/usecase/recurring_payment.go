@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// nextCharge - scheduled time of the charge after prev one, charges missed until now are skipped.
+func nextCharge(s entity.Schedule, prev, now time.Time) time.Time {
+	next := s.Next(prev)
+	for !next.IsZero() && !next.After(now) {
+		next = s.Next(next)
+	}
+
+	return next
+}
+
+// recurringKey - idempotency key of charge of plan, retry of charge after unknown result does not charge twice.
+// Keys of charges are scoped by plan apart from keys of client requests, so a client can not take them.
+func (uc *AccountUseCase) recurringKey(plan entity.RecurringPlan, charge int) (*entity.IdempotencyKey, error) {
+	return uc.idempotencyKey(strconv.Itoa(charge), fmt.Sprintf("recurring:%d", plan.Id), "RecurringCharge", plan.RedeemId, plan.AccrualId, plan.Amount, plan.TransactionMeta)
+}
+
+// CreateRecurringPlan - create plan of periodic transfer from redeem account to accrual account by schedule,
+// which is interval (@every 720h) or cron expression (0 10 1 * *). The first charge is at start time
+// for interval and at the first time of cron expression after start time, zero start time means now.
+// Plan is completed after maxCharges charges, zero means no limit, or when the next charge is after end time.
+func (uc *AccountUseCase) CreateRecurringPlan(ctx context.Context, redeemId, accrId int64, amount decimal.Decimal, scheduleSpec string, startAt time.Time, maxCharges int, endAt time.Time, meta entity.TransactionMeta) (plan entity.RecurringPlan, err error) {
+	if redeemId == accrId {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorSameRedeemAccrId)
+	}
+
+	schedule, err := entity.ParseSchedule(scheduleSpec)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - entity.ParseSchedule: %w", ErrorIncorrectSchedule)
+	}
+
+	if maxCharges < 0 {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorMaxChargesIsNegative)
+	}
+
+	now := time.Now()
+	if startAt.IsZero() {
+		startAt = now
+	} else if startAt.Before(now.Add(-time.Minute)) {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorStartInPast)
+	}
+
+	first := schedule.First(startAt)
+	if first.IsZero() {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorIncorrectSchedule)
+	}
+
+	var end *time.Time
+	if !endAt.IsZero() {
+		if endAt.Before(first) {
+			return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorEndBeforeStart)
+		}
+
+		end = &endAt
+	}
+
+	err = uc.metaValidation(meta)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - uc.metaValidation: %w", err)
+	}
+
+	currencies := make([]string, 0, 2)
+
+	for _, id := range []int64{redeemId, accrId} {
+		err = uc.idValidation(id)
+		if err != nil {
+			return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - uc.idValidation: %w", err)
+		}
+
+		acc, err := uc.repo.GetById(ctx, id)
+		if err != nil {
+			return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - uc.repo.GetById: %w", err)
+		}
+
+		currencies = append(currencies, acc.Currency)
+	}
+
+	if currencies[0] != currencies[1] {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - validation: %w", ErrorCurrencyMismatch)
+	}
+
+	err = uc.amountValidation(amount, currencies[0])
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - uc.amountValidation: %w", err)
+	}
+
+	plan, err = uc.repo.CreateRecurringPlan(ctx, entity.RecurringPlan{
+		RedeemId:        redeemId,
+		AccrualId:       accrId,
+		Amount:          amount,
+		Currency:        currencies[0],
+		Schedule:        scheduleSpec,
+		NextChargeDt:    first,
+		MaxCharges:      maxCharges,
+		EndDt:           end,
+		TransactionMeta: meta,
+	})
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CreateRecurringPlan - uc.repo.CreateRecurringPlan: %w", err)
+	}
+
+	return
+}
+
+// GetRecurringPlan - get recurring payment plan by ID.
+func (uc *AccountUseCase) GetRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - GetRecurringPlan - uc.idValidation: %w", err)
+	}
+
+	plan, err = uc.repo.GetRecurringPlan(ctx, id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - GetRecurringPlan - uc.repo.GetRecurringPlan: %w", err)
+	}
+
+	return
+}
+
+// ListRecurringPlans - find recurring payment plans by filter.
+func (uc *AccountUseCase) ListRecurringPlans(ctx context.Context, f entity.RecurringPlanFilter) (plans []*entity.RecurringPlan, err error) {
+	if f.AccountId != 0 {
+		err = uc.idValidation(f.AccountId)
+		if err != nil {
+			return plans, fmt.Errorf("AccountUseCase - ListRecurringPlans - uc.idValidation: %w", err)
+		}
+	}
+
+	if f.Status != "" && !f.Status.IsKnown() {
+		return plans, fmt.Errorf("AccountUseCase - ListRecurringPlans - validation: %w", ErrorUnknownRecurringStatus)
+	}
+
+	if f.Limit > _maxPageLimit {
+		return plans, fmt.Errorf("AccountUseCase - ListRecurringPlans - validation: %w", ErrorLimitTooLarge)
+	} else if f.Limit == 0 {
+		f.Limit = _defaultPageLimit
+	}
+
+	plans, err = uc.repo.ListRecurringPlans(ctx, f)
+	if err != nil {
+		return plans, fmt.Errorf("AccountUseCase - ListRecurringPlans - uc.repo.ListRecurringPlans: %w", err)
+	}
+
+	return
+}
+
+// CancelRecurringPlan - cancel active or suspended plan.
+func (uc *AccountUseCase) CancelRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CancelRecurringPlan - uc.idValidation: %w", err)
+	}
+
+	plan, err = uc.repo.CancelRecurringPlan(ctx, id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - CancelRecurringPlan - uc.repo.CancelRecurringPlan: %w", err)
+	}
+
+	return
+}
+
+// ResumeRecurringPlan - activate plan which is suspended by dunning, the failed charge is attempted at once.
+func (uc *AccountUseCase) ResumeRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - ResumeRecurringPlan - uc.idValidation: %w", err)
+	}
+
+	plan, err = uc.repo.ResumeRecurringPlan(ctx, id)
+	if err != nil {
+		return plan, fmt.Errorf("AccountUseCase - ResumeRecurringPlan - uc.repo.ResumeRecurringPlan: %w", err)
+	}
+
+	return
+}
+
+// GetRecurringAttempts - get history of attempts of charges of plan.
+func (uc *AccountUseCase) GetRecurringAttempts(ctx context.Context, id int64, limit, offset uint64) (attempts []*entity.RecurringAttempt, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return attempts, fmt.Errorf("AccountUseCase - GetRecurringAttempts - uc.idValidation: %w", err)
+	}
+
+	if limit > _maxPageLimit {
+		return attempts, fmt.Errorf("AccountUseCase - GetRecurringAttempts - validation: %w", ErrorLimitTooLarge)
+	} else if limit == 0 {
+		limit = _defaultPageLimit
+	}
+
+	_, err = uc.repo.GetRecurringPlan(ctx, id)
+	if err != nil {
+		return attempts, fmt.Errorf("AccountUseCase - GetRecurringAttempts - uc.repo.GetRecurringPlan: %w", err)
+	}
+
+	attempts, err = uc.repo.GetRecurringAttempts(ctx, id, limit, offset)
+	if err != nil {
+		return attempts, fmt.Errorf("AccountUseCase - GetRecurringAttempts - uc.repo.GetRecurringAttempts: %w", err)
+	}
+
+	return
+}
+
+// chargeRecurringPlan - make the next charge of plan and record its attempt. Charge failed because of insufficient
+// funds is retried after retryDelay, plan is suspended when retries run out. Charge failed for other reason is
+// retried after retryDelay without counting, transient failure is not recorded and is retried by the next run.
+func (uc *AccountUseCase) chargeRecurringPlan(ctx context.Context, plan entity.RecurringPlan, retries int, retryDelay time.Duration) (charged bool, err error) {
+	schedule, err := entity.ParseSchedule(plan.Schedule)
+	if err != nil {
+		return false, fmt.Errorf("AccountUseCase - chargeRecurringPlan - entity.ParseSchedule: %w", err)
+	}
+
+	charge := plan.Charges + 1
+	attempt := entity.RecurringAttempt{PlanId: plan.Id, Charge: charge, Amount: plan.Amount, Status: entity.RecurringAttemptSucceeded}
+	next := plan
+	now := time.Now()
+
+	key, err := uc.recurringKey(plan, charge)
+	if err != nil {
+		return false, fmt.Errorf("AccountUseCase - chargeRecurringPlan - uc.recurringKey: %w", err)
+	}
+
+	_, _, err = uc.repo.TransferAmount(ctx, entity.AccountRef{Id: plan.RedeemId}, entity.AccountRef{Id: plan.AccrualId}, plan.Amount, plan.Amount, nil, plan.TransactionMeta, key)
+	if err != nil && transient(ctx, err) {
+		return false, nil
+	}
+
+	if err == nil {
+		next.Charges = charge
+		next.FailedAttempts = 0
+		next.NextChargeDt = nextCharge(schedule, plan.NextChargeDt, now)
+		next.NextAttemptDt = next.NextChargeDt
+
+		if plan.MaxCharges > 0 && charge >= plan.MaxCharges || next.NextChargeDt.IsZero() || plan.EndDt != nil && next.NextChargeDt.After(*plan.EndDt) {
+			next.Status = entity.RecurringCompleted
+			next.NextChargeDt, next.NextAttemptDt = plan.NextChargeDt, plan.NextChargeDt
+		}
+	} else {
+		attempt.Status = entity.RecurringAttemptFailed
+		attempt.Failure = failureReason(err)
+		attempt.FailureCode = failureCode(err)
+		next.NextAttemptDt = now.Add(retryDelay)
+
+		if attempt.FailureCode == entity.FailureInsufficientFunds {
+			next.FailedAttempts++
+		}
+
+		if next.FailedAttempts > retries {
+			next.Status = entity.RecurringSuspended
+		}
+	}
+
+	_, err = uc.repo.RecordRecurringAttempt(ctx, plan, next, attempt)
+	if errors.Is(err, entity.ErrRecurringNotDue) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("AccountUseCase - chargeRecurringPlan - uc.repo.RecordRecurringAttempt: %w", err)
+	}
+
+	return attempt.Status == entity.RecurringAttemptSucceeded, nil
+}
+
+// ChargeRecurringPlans - make due charges of up to batchSize plans, returns number of successful charges.
+// Charge failed because of insufficient funds is retried up to retries times with retryDelay between attempts,
+// then plan is suspended.
+func (uc *AccountUseCase) ChargeRecurringPlans(ctx context.Context, batchSize uint64, retries int, retryDelay time.Duration) (charged int64, err error) {
+	plans, err := uc.repo.DueRecurringPlans(ctx, batchSize)
+	if err != nil {
+		return charged, fmt.Errorf("AccountUseCase - ChargeRecurringPlans - uc.repo.DueRecurringPlans: %w", err)
+	}
+
+	for _, plan := range plans {
+		if ctx.Err() != nil {
+			return charged, nil
+		}
+
+		ok, err := uc.chargeRecurringPlan(ctx, *plan, retries, retryDelay)
+		if err != nil {
+			return charged, fmt.Errorf("AccountUseCase - ChargeRecurringPlans - uc.chargeRecurringPlan: %w", err)
+		}
+
+		if ok {
+			charged++
+		}
+	}
+
+	return charged, nil
+}
@@ -0,0 +1,315 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateRecurringPlan(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	start := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC)
+	user := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB", CreatedDt: time.Now()}
+	merchant := entity.Account{Id: 2, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}
+	tests := []struct {
+		name     string
+		prepare  func(f *fields)
+		schedule string
+		startAt  time.Time
+		max      int
+		endAt    time.Time
+		wantErr  bool
+	}{
+		{
+			name: "Case of correct work: cron schedule starts at its first time",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(user, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(merchant, nil)
+				f.accountRepo.EXPECT().CreateRecurringPlan(f.ctx, entity.RecurringPlan{
+					RedeemId:     1,
+					AccrualId:    2,
+					Amount:       decimal.NewFromInt(299),
+					Currency:     "RUB",
+					Schedule:     "0 10 1 * *",
+					NextChargeDt: time.Date(2030, 2, 1, 10, 0, 0, 0, time.UTC),
+					MaxCharges:   12,
+					EndDt:        &end,
+				}).Return(entity.RecurringPlan{Id: 1, Status: entity.RecurringActive}, nil)
+			},
+			schedule: "0 10 1 * *",
+			startAt:  start,
+			max:      12,
+			endAt:    end,
+			wantErr:  false,
+		},
+		{
+			name: "Case of correct work: interval schedule starts at start time",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(user, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(merchant, nil)
+				f.accountRepo.EXPECT().CreateRecurringPlan(f.ctx, entity.RecurringPlan{
+					RedeemId:     1,
+					AccrualId:    2,
+					Amount:       decimal.NewFromInt(299),
+					Currency:     "RUB",
+					Schedule:     "@every 720h",
+					NextChargeDt: start,
+				}).Return(entity.RecurringPlan{Id: 1, Status: entity.RecurringActive}, nil)
+			},
+			schedule: "@every 720h",
+			startAt:  start,
+			wantErr:  false,
+		},
+		{
+			name:     "Case of incorrect work: incorrect cron expression",
+			prepare:  func(f *fields) {},
+			schedule: "0 25 * * *",
+			startAt:  start,
+			wantErr:  true,
+		},
+		{
+			name:     "Case of incorrect work: interval is too small",
+			prepare:  func(f *fields) {},
+			schedule: "@every 10s",
+			startAt:  start,
+			wantErr:  true,
+		},
+		{
+			name:     "Case of incorrect work: cron expression has no time",
+			prepare:  func(f *fields) {},
+			schedule: "0 10 30 2 *",
+			startAt:  start,
+			wantErr:  true,
+		},
+		{
+			name:     "Case of incorrect work: end time is before the first charge",
+			prepare:  func(f *fields) {},
+			schedule: "0 10 1 * *",
+			startAt:  start,
+			endAt:    start.Add(time.Hour),
+			wantErr:  true,
+		},
+		{
+			name:     "Case of incorrect work: max number of charges is negative",
+			prepare:  func(f *fields) {},
+			schedule: "@daily",
+			startAt:  start,
+			max:      -1,
+			wantErr:  true,
+		},
+		{
+			name:     "Case of incorrect work: start time is in the past",
+			prepare:  func(f *fields) {},
+			schedule: "@daily",
+			startAt:  time.Now().Add(-time.Hour),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			plan, err := uc.CreateRecurringPlan(f.ctx, 1, 2, decimal.NewFromInt(299), tt.schedule, tt.startAt, tt.max, tt.endAt, entity.TransactionMeta{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateRecurringPlan() plan=%v error = %v, wantErr %v", plan, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_ChargeRecurringPlans(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	user := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB"}
+	merchant := entity.Account{Id: 2, Balance: decimal.Zero, Currency: "RUB"}
+	due := time.Now().Add(-time.Minute).Truncate(time.Second)
+	active := func(maxCharges, failedAttempts int) entity.RecurringPlan {
+		return entity.RecurringPlan{
+			Id:             7,
+			RedeemId:       1,
+			AccrualId:      2,
+			Amount:         decimal.NewFromInt(299),
+			Currency:       "RUB",
+			Schedule:       "@every 720h",
+			Status:         entity.RecurringActive,
+			NextChargeDt:   due,
+			NextAttemptDt:  due,
+			Charges:        2,
+			MaxCharges:     maxCharges,
+			FailedAttempts: failedAttempts,
+		}
+	}
+	transfer := func(f *fields, t *testing.T, err error) {
+		f.accountRepo.EXPECT().TransferAmount(f.ctx, entity.AccountRef{Id: 1}, entity.AccountRef{Id: 2}, decimal.NewFromInt(299), decimal.NewFromInt(299),
+			(*entity.Rate)(nil), entity.TransactionMeta{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ entity.AccountRef, _, _ decimal.Decimal, _ *entity.Rate, _ entity.TransactionMeta, key *entity.IdempotencyKey) (entity.Account, entity.Account, error) {
+				if key == nil || key.Scope != "recurring:7" || key.Key != "3" {
+					t.Errorf("TransferAmount() key = %v", key)
+				}
+				return merchant, user, err
+			})
+	}
+	record := func(f *fields, plan entity.RecurringPlan, check func(next entity.RecurringPlan, attempt entity.RecurringAttempt), err error) {
+		f.accountRepo.EXPECT().RecordRecurringAttempt(f.ctx, plan, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, next entity.RecurringPlan, attempt entity.RecurringAttempt) (entity.RecurringPlan, error) {
+				check(next, attempt)
+				return next, err
+			})
+	}
+	tests := []struct {
+		name        string
+		prepare     func(f *fields, t *testing.T, plan entity.RecurringPlan)
+		plan        entity.RecurringPlan
+		wantCharged int64
+		wantErr     bool
+	}{
+		{
+			name: "Case of correct work: charge moves plan to the next period",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, nil)
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {
+					if next.Status != entity.RecurringActive || next.Charges != 3 || !next.NextChargeDt.Equal(due.Add(720*time.Hour)) {
+						t.Errorf("RecordRecurringAttempt() next = %v", next)
+					}
+					if attempt.Status != entity.RecurringAttemptSucceeded || attempt.Charge != 3 {
+						t.Errorf("RecordRecurringAttempt() attempt = %v", attempt)
+					}
+				}, nil)
+			},
+			plan:        active(0, 0),
+			wantCharged: 1,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: the last charge completes plan",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, nil)
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {
+					if next.Status != entity.RecurringCompleted || next.Charges != 3 {
+						t.Errorf("RecordRecurringAttempt() next = %v", next)
+					}
+				}, nil)
+			},
+			plan:        active(3, 0),
+			wantCharged: 1,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: not enough money, charge is retried later",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, &entity.InsufficientFundsError{Available: decimal.NewFromInt(100), Err: entity.ErrNotEnoughMoney})
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {
+					if next.Status != entity.RecurringActive || next.FailedAttempts != 1 || next.Charges != 2 || !next.NextAttemptDt.After(time.Now()) {
+						t.Errorf("RecordRecurringAttempt() next = %v", next)
+					}
+					if attempt.Status != entity.RecurringAttemptFailed || attempt.Charge != 3 || attempt.Failure == "" || attempt.FailureCode != entity.FailureInsufficientFunds {
+						t.Errorf("RecordRecurringAttempt() attempt = %v", attempt)
+					}
+				}, nil)
+			},
+			plan:        active(0, 0),
+			wantCharged: 0,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: retries run out, plan is suspended",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, &entity.InsufficientFundsError{Available: decimal.NewFromInt(100), Err: entity.ErrNotEnoughMoney})
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {
+					if next.Status != entity.RecurringSuspended || next.FailedAttempts != 4 {
+						t.Errorf("RecordRecurringAttempt() next = %v", next)
+					}
+				}, nil)
+			},
+			plan:        active(0, 3),
+			wantCharged: 0,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: failure other than insufficient funds is not counted",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, &entity.VelocityLimitError{Period: entity.LimitDay, Kind: entity.LimitCount, Max: decimal.NewFromInt(5), Remaining: decimal.Zero})
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {
+					if next.Status != entity.RecurringActive || next.FailedAttempts != 3 || !next.NextAttemptDt.After(time.Now()) {
+						t.Errorf("RecordRecurringAttempt() next = %v", next)
+					}
+					if attempt.Status != entity.RecurringAttemptFailed || attempt.FailureCode != entity.FailureVelocityLimit {
+						t.Errorf("RecordRecurringAttempt() attempt = %v", attempt)
+					}
+				}, nil)
+			},
+			plan:        active(0, 3),
+			wantCharged: 0,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: charge lost to concurrent operation is not recorded",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, fmt.Errorf("AccountRepo - TransferAmount - tx.Commit: %w", entity.ErrConcurrentUpdate))
+			},
+			plan:        active(0, 3),
+			wantCharged: 0,
+			wantErr:     false,
+		},
+		{
+			name: "Case of correct work: attempt is recorded by another worker",
+			prepare: func(f *fields, t *testing.T, plan entity.RecurringPlan) {
+				transfer(f, t, nil)
+				record(f, plan, func(next entity.RecurringPlan, attempt entity.RecurringAttempt) {}, entity.ErrRecurringNotDue)
+			},
+			plan:        active(0, 0),
+			wantCharged: 0,
+			wantErr:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			plan := tt.plan
+			f.accountRepo.EXPECT().DueRecurringPlans(f.ctx, uint64(10)).Return([]*entity.RecurringPlan{&plan}, nil)
+			if tt.prepare != nil {
+				tt.prepare(&f, t, tt.plan)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			charged, err := uc.ChargeRecurringPlans(f.ctx, 10, 3, time.Hour)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChargeRecurringPlans() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if charged != tt.wantCharged {
+				t.Errorf("ChargeRecurringPlans() charged = %v, want %v", charged, tt.wantCharged)
+			}
+		})
+	}
+}
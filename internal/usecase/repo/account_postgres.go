@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -9,12 +10,36 @@ import (
 	"github.com/cut4cut/avito-test-work/internal/entity"
 	"github.com/cut4cut/avito-test-work/pkg/postgres"
 	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
 	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
 )
 
 const _defaultEntityCap = 64
 const isoLevel = pgx.Serializable
 
+// batchIsoLevel - isolation level of batches of background workers. Amounts are changed relatively under row locks,
+// so read committed is enough and does not fail the whole batch on concurrent updates of accounts.
+const batchIsoLevel = pgx.ReadCommitted
+
+const (
+	// _uniqueViolation - SQLSTATE of unique constraint violation.
+	_uniqueViolation = "23505"
+	// _serializationFailure - SQLSTATE of transaction which can not be serialized with concurrent ones.
+	_serializationFailure = "40001"
+	// _deadlockDetected - SQLSTATE of transaction which is chosen as victim of deadlock.
+	_deadlockDetected = "40P01"
+)
+
+// _accountColumns - columns of account table in order of accountFields.
+const _accountColumns = "id, owner_id, balance, currency, status, status_dt, credit_limit, reserved, " +
+	"balance + credit_limit - reserved, wallet, tier, created_dt"
+
+// accountFields - destinations to scan row of _accountColumns.
+func accountFields(acc *entity.Account) []interface{} {
+	return []interface{}{&acc.Id, &acc.OwnerId, &acc.Balance, &acc.Currency, &acc.Status, &acc.StatusDt, &acc.CreditLimit, &acc.Reserved, &acc.Available, &acc.Wallet, &acc.Tier, &acc.CreatedDt}
+}
+
 // AccountRepo - repository with account.
 type AccountRepo struct {
 	*postgres.Postgres
@@ -25,31 +50,47 @@ func New(pg *postgres.Postgres) *AccountRepo {
 	return &AccountRepo{pg}
 }
 
-func selectTransactionType(amount float64) (string, error) {
-	if amount > 0 {
+func selectTransactionType(amount decimal.Decimal) (string, error) {
+	if amount.IsPositive() {
 		return "accrual", nil
-	} else if amount < 0 {
+	} else if amount.IsNegative() {
 		return "redeem", nil
 	}
 	return "", errors.New("amount in transaction is zero")
 }
 
-// Create - create new account with default values.
-func (r *AccountRepo) Create(ctx context.Context) (acc entity.Account, err error) {
-	sql, _, err := r.Builder.
+// customerPred - predicate of customer account by ID, system accounts are not reachable through customer operations.
+func customerPred(id int64) sq.Eq {
+	return sq.Eq{"id": id, "type": string(entity.AccountCustomer)}
+}
+
+// ownerValue - value of owner_id column, empty owner ID is stored as NULL.
+func ownerValue(ownerId string) interface{} {
+	if ownerId == "" {
+		return nil
+	}
+	return ownerId
+}
+
+// Create - create new account in currency and wallet with default values, owner ID is optional.
+func (r *AccountRepo) Create(ctx context.Context, currency, ownerId string, wallet entity.Wallet) (acc entity.Account, err error) {
+	sql, args, err := r.Builder.
 		Insert("account").
-		Columns("id, balance, created_dt").
+		Columns("id, owner_id, wallet, balance, currency, created_dt").
 		Values(
 			sq.Expr("DEFAULT"),
+			ownerValue(ownerId),
+			string(wallet),
 			sq.Expr("DEFAULT"),
+			currency,
 			sq.Expr("DEFAULT")).
-		Suffix("RETURNING \"id\", \"balance\", \"created_dt\"").
+		Suffix("RETURNING " + _accountColumns).
 		ToSql()
 	if err != nil {
 		return acc, fmt.Errorf("AccountRepo - Create - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(ctx, sql).Scan(&acc.Id, &acc.Balance, &acc.CreatedDt)
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
 	if err != nil {
 		return acc, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", err)
 	}
@@ -59,16 +100,16 @@ func (r *AccountRepo) Create(ctx context.Context) (acc entity.Account, err error
 
 // GetByID - get account's values by ID.
 func (r *AccountRepo) GetById(ctx context.Context, id int64) (acc entity.Account, err error) {
-	sql, _, err := r.Builder.
-		Select("id, balance, created_dt").
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
 		From("account").
-		Where(sq.Eq{"id": id}).
+		Where(customerPred(id)).
 		ToSql()
 	if err != nil {
 		return acc, fmt.Errorf("AccountRepo - GetByID - r.Builder: %w", err)
 	}
 
-	err = r.Pool.QueryRow(context.Background(), sql, id).Scan(&acc.Id, &acc.Balance, &acc.CreatedDt)
+	err = r.Pool.QueryRow(context.Background(), sql, args...).Scan(accountFields(&acc)...)
 	if err != nil {
 		return acc, fmt.Errorf("AccountRepo - GetByID - r.Pool.QueryRow: %w", err)
 	}
@@ -76,78 +117,589 @@ func (r *AccountRepo) GetById(ctx context.Context, id int64) (acc entity.Account
 	return
 }
 
-// updBalance - helper function to update the balance.
-func (r *AccountRepo) updBalance(ctx context.Context, tx *pgx.Tx, transType string, id, docNum int64, amount float64) (acc entity.Account, err error) {
-	sql, _, err := r.Builder.
-		Select("balance").
+// GetByOwner - get account's values by external owner ID and wallet.
+func (r *AccountRepo) GetByOwner(ctx context.Context, ownerId string, wallet entity.Wallet) (acc entity.Account, err error) {
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
+		From("account").
+		Where(sq.Eq{"owner_id": ownerId, "wallet": string(wallet)}).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - GetByOwner - r.Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return acc, fmt.Errorf("AccountRepo - GetByOwner - r.Pool.QueryRow: %w", entity.ErrAccountNotFound)
+	} else if err != nil {
+		return acc, fmt.Errorf("AccountRepo - GetByOwner - r.Pool.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// GetWallets - get all wallets of owner, wallets are read by one query to get consistent balances.
+func (r *AccountRepo) GetWallets(ctx context.Context, ownerId string) (accs []entity.Account, err error) {
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
 		From("account").
+		Where(sq.Eq{"owner_id": ownerId}).
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - GetWallets - r.Builder: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - GetWallets - r.Pool.Query: %w", err)
+	}
+
+	accs, err = scanAccounts(rows)
+	if err != nil {
+		return nil, fmt.Errorf("AccountRepo - GetWallets - scanAccounts: %w", err)
+	}
+
+	return
+}
+
+// scanAccounts - scan rows of _accountColumns and close them.
+func scanAccounts(rows pgx.Rows) ([]entity.Account, error) {
+	defer rows.Close()
+
+	accs := make([]entity.Account, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		acc := entity.Account{}
+
+		err := rows.Scan(accountFields(&acc)...)
+		if err != nil {
+			return nil, err
+		}
+
+		accs = append(accs, acc)
+	}
+
+	return accs, rows.Err()
+}
+
+// accountFilterPred - predicate of account search conditions.
+func accountFilterPred(f entity.AccountFilter) sq.And {
+	pred := sq.And{sq.Eq{"type": string(entity.AccountCustomer)}}
+
+	if f.MinBalance.Valid {
+		pred = append(pred, sq.GtOrEq{"balance": f.MinBalance.Decimal})
+	}
+
+	if f.MaxBalance.Valid {
+		pred = append(pred, sq.LtOrEq{"balance": f.MaxBalance.Decimal})
+	}
+
+	if !f.CreatedFrom.IsZero() {
+		pred = append(pred, sq.GtOrEq{"created_dt": f.CreatedFrom})
+	}
+
+	if !f.CreatedTo.IsZero() {
+		pred = append(pred, sq.Lt{"created_dt": f.CreatedTo})
+	}
+
+	if f.Status != "" {
+		pred = append(pred, sq.Eq{"status": string(f.Status)})
+	}
+
+	if f.OwnerId != "" {
+		pred = append(pred, sq.Eq{"owner_id": f.OwnerId})
+	}
+
+	if f.Currency != "" {
+		pred = append(pred, sq.Eq{"currency": f.Currency})
+	}
+
+	return pred
+}
+
+// List - find accounts by filter, page is taken after cursor by keyset of sort column and ID.
+// Page and total count are read in one snapshot.
+func (r *AccountRepo) List(ctx context.Context, f entity.AccountFilter) (accs []entity.Account, total int64, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return accs, total, err
+	}
+	defer tx.Rollback(ctx)
+
+	pred := accountFilterPred(f)
+
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("account").
+		Where(pred).
+		ToSql()
+	if err != nil {
+		return accs, total, fmt.Errorf("AccountRepo - List - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(&total)
+	if err != nil {
+		return accs, total, fmt.Errorf("AccountRepo - List - tx.QueryRow: %w", err)
+	}
+
+	dir, op := "ASC", ">"
+	if f.IsDecreasing {
+		dir, op = "DESC", "<"
+	}
+
+	if f.After != nil {
+		value, err := f.After.SortValue(f.Sort)
+		if err != nil {
+			return accs, total, fmt.Errorf("AccountRepo - List - f.After.SortValue: %w", err)
+		}
+
+		pred = append(pred, sq.Expr(fmt.Sprintf("(%s, id) %s (?, ?)", f.Sort, op), value, f.After.Id))
+	}
+
+	sql, args, err = r.Builder.
+		Select(_accountColumns).
+		From("account").
+		Where(pred).
+		OrderBy(fmt.Sprintf("%s %s", f.Sort, dir), fmt.Sprintf("id %s", dir)).
+		Limit(f.Limit).
+		ToSql()
+	if err != nil {
+		return accs, total, fmt.Errorf("AccountRepo - List - r.Builder: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return accs, total, fmt.Errorf("AccountRepo - List - tx.Query: %w", err)
+	}
+
+	accs, err = scanAccounts(rows)
+	if err != nil {
+		return nil, total, fmt.Errorf("AccountRepo - List - scanAccounts: %w", err)
+	}
+
+	return
+}
+
+// resolve - get ID and currency of referenced customer account inside transaction.
+// Wallet of unknown owner is created in createCurrency, empty createCurrency means no creation.
+func (r *AccountRepo) resolve(ctx context.Context, tx *pgx.Tx, ref entity.AccountRef, createCurrency string) (id int64, currency string, err error) {
+	pred := customerPred(ref.Id)
+	if ref.IsOwner() {
+		pred = sq.Eq{"owner_id": ref.OwnerId, "wallet": string(ref.Wallet)}
+	}
+
+	sql, args, err := r.Builder.
+		Select("id, currency").
+		From("account").
+		Where(pred).
+		ToSql()
+	if err != nil {
+		return id, currency, fmt.Errorf("AccountRepo - resolve - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&id, &currency)
+	switch {
+	case err == nil:
+		return
+	case !errors.Is(err, pgx.ErrNoRows) || !ref.IsOwner():
+		return id, currency, fmt.Errorf("AccountRepo - resolve - tx.QueryRow: %w", err)
+	case createCurrency == "":
+		return id, currency, fmt.Errorf("AccountRepo - resolve - tx.QueryRow: %w", entity.ErrAccountNotFound)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("account").
+		Columns("owner_id, wallet, currency").
+		Values(ref.OwnerId, string(ref.Wallet), createCurrency).
+		Suffix("RETURNING id, currency").
+		ToSql()
+	if err != nil {
+		return id, currency, fmt.Errorf("AccountRepo - resolve - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&id, &currency)
+	if err != nil {
+		return id, currency, fmt.Errorf("AccountRepo - resolve - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// balanceChange - change of account's balance written as posting of journal entry.
+// Reversal is reversed posting, fee points to posting of operation, escrow is safe deal the money is held for.
+// Sweep allows to redeem frozen account when it is being closed.
+type balanceChange struct {
+	transType  string
+	id, docNum int64
+	journalId  int64
+	amount     decimal.Decimal
+	rate       *entity.Rate
+	holdId     *int64
+	reversalOf *int64
+	feeOf      *int64
+	campaignId *int64
+	escrowId   *int64
+	meta       entity.TransactionMeta
+	sweep      bool
+}
+
+// insufficientFunds - error of redeem more than available amount of account with credit limit.
+func insufficientFunds(available, limit decimal.Decimal) error {
+	if limit.IsPositive() {
+		return &entity.InsufficientFundsError{Available: available, Err: entity.ErrLimitExceeded}
+	}
+
+	return &entity.InsufficientFundsError{Available: available, Err: entity.ErrNotEnoughMoney}
+}
+
+// journal - create journal entry inside transaction, returns its ID.
+func (r *AccountRepo) journal(ctx context.Context, tx *pgx.Tx) (id int64, err error) {
+	sql, args, err := r.Builder.
+		Insert("journal").
+		Columns("created_dt").
+		Values(sq.Expr("DEFAULT")).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return id, fmt.Errorf("AccountRepo - journal - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		return id, fmt.Errorf("AccountRepo - journal - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// batchJournal - create journal entry of batch inside transaction, entries of batch have ID of its first entry
+// as batch ID, zero batchId starts new batch. Returns ID of created entry.
+func (r *AccountRepo) batchJournal(ctx context.Context, tx *pgx.Tx, batchId int64) (id int64, err error) {
+	id, err = r.journal(ctx, tx)
+	if err != nil {
+		return id, fmt.Errorf("AccountRepo - batchJournal - r.journal: %w", err)
+	}
+
+	if batchId == 0 {
+		batchId = id
+	}
+
+	sql, args, err := r.Builder.
+		Update("journal").
+		Set("batch_id", batchId).
 		Where(sq.Eq{"id": id}).
 		ToSql()
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - r.Builder: %w", err)
+		return id, fmt.Errorf("AccountRepo - batchJournal - r.Builder: %w", err)
 	}
 
-	balance := 0.0
-	err = (*tx).QueryRow(ctx, sql, id).Scan(&balance)
+	_, err = (*tx).Exec(ctx, sql, args...)
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", err)
+		return id, fmt.Errorf("AccountRepo - batchJournal - tx.Exec: %w", err)
+	}
+
+	return
+}
+
+// systemAccount - get ID of system account in currency inside transaction.
+func (r *AccountRepo) systemAccount(ctx context.Context, tx *pgx.Tx, code entity.SystemAccount, currency string) (id int64, err error) {
+	sql, args, err := r.Builder.
+		Select("id").
+		From("account").
+		Where(sq.Eq{"type": string(entity.AccountSystem), "system_code": string(code), "currency": currency}).
+		ToSql()
+	if err != nil {
+		return id, fmt.Errorf("AccountRepo - systemAccount - r.Builder: %w", err)
 	}
 
-	if balance+amount < 0 {
-		return acc, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", ErrNotEnoughMoney)
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return id, fmt.Errorf("AccountRepo - systemAccount - tx.QueryRow: %w", entity.ErrSystemAccountNotFound)
+	} else if err != nil {
+		return id, fmt.Errorf("AccountRepo - systemAccount - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// post - write posting of balance change in currency, returns ID of written transaction.
+func (r *AccountRepo) post(ctx context.Context, tx *pgx.Tx, ch balanceChange, currency string) (transId int64, err error) {
+	var rateId *int64
+	var rate, spread decimal.NullDecimal
+	if ch.rate != nil {
+		rateId = &ch.rate.Id
+		rate = decimal.NewNullDecimal(ch.rate.Value)
+		spread = decimal.NewNullDecimal(ch.rate.Spread)
+	}
+
+	sql, args, err := r.Builder.
+		Insert("fct_transcation").
+		Columns("account_id, doc_num, journal_id, type, amount, currency, rate_id, rate, spread, hold_id, reversal_of, fee_of, campaign_id, escrow_id, description, purpose, source").
+		Values(ch.id, ch.docNum, ch.journalId, ch.transType, ch.amount, currency, rateId, rate, spread, ch.holdId, ch.reversalOf, ch.feeOf, ch.campaignId, ch.escrowId,
+			ch.meta.Description, ch.meta.Purpose, ch.meta.Source).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return transId, fmt.Errorf("AccountRepo - post - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&transId)
+	if err != nil {
+		return transId, fmt.Errorf("AccountRepo - post - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// updBalance - helper function to update the balance, returns ID of written transaction.
+// Redeem is limited by available amount, which is balance plus credit limit minus amount reserved by holds.
+// Balance of system account is the sum of its postings and is not kept in account row, as every operation
+// goes through system accounts, so only posting is written and account has ID and currency only.
+func (r *AccountRepo) updBalance(ctx context.Context, tx *pgx.Tx, ch balanceChange) (acc entity.Account, transId int64, err error) {
+	sql, _, err := r.Builder.
+		Select("balance, credit_limit, reserved, currency, status, type").
+		From("account").
+		Where(sq.Eq{"id": ch.id}).
+		ToSql()
+	if err != nil {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - r.Builder: %w", err)
+	}
+
+	balance, limit, reserved, currency, status, accType := decimal.Zero, decimal.Zero, decimal.Zero, "", entity.AccountActive, entity.AccountCustomer
+	err = (*tx).QueryRow(ctx, sql, ch.id).Scan(&balance, &limit, &reserved, &currency, &status, &accType)
+	if err != nil {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", err)
+	}
+
+	if accType == entity.AccountSystem {
+		transId, err = r.post(ctx, tx, ch, currency)
+		if err != nil {
+			return acc, transId, fmt.Errorf("AccountRepo - updBalance - r.post: %w", err)
+		}
+
+		return entity.Account{Id: ch.id, Currency: currency}, transId, nil
+	}
+
+	if status == entity.AccountClosed {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - validation: %w", entity.ErrAccountClosed)
+	}
+
+	if status == entity.AccountFrozen && ch.amount.IsNegative() && !ch.sweep {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - validation: %w", entity.ErrAccountFrozen)
+	}
+
+	if available := balance.Add(limit).Sub(reserved); available.Add(ch.amount).IsNegative() {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - validation: %w", insufficientFunds(available, limit))
 	}
 
 	sqlUpd, _, err := r.Builder.
 		Update("account").
 		Set("balance", sq.Expr("balance + $2")).
-		Where(sq.Eq{"id": id}).
-		Suffix("RETURNING \"id\", \"balance\", \"created_dt\"").
+		Where(sq.Eq{"id": ch.id}).
+		Suffix("RETURNING " + _accountColumns).
 		ToSql()
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - r.Builder: %w", err)
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - r.Builder: %w", err)
 	}
 
-	sqlIns, _, err := r.Builder.
-		Insert("fct_transcation").
-		Values(
-			sq.Expr("DEFAULT"),
-			sq.Expr("DEFAULT"),
-			id,
-			docNum,
-			transType,
-			amount).
+	err = (*tx).QueryRow(ctx, sqlUpd, ch.id, ch.amount).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", err)
+	}
+
+	if ch.transType == "redeem" && ch.amount.IsNegative() && !ch.sweep {
+		err = r.checkVelocity(ctx, tx, ch.id, ch.journalId, currency, ch.amount.Neg())
+		if err != nil {
+			return acc, transId, fmt.Errorf("AccountRepo - updBalance - r.checkVelocity: %w", err)
+		}
+	}
+
+	transId, err = r.post(ctx, tx, ch, currency)
+	if err != nil {
+		return acc, transId, fmt.Errorf("AccountRepo - updBalance - r.post: %w", err)
+	}
+
+	return
+}
+
+// debited - charge fee of operation from account and pay cashback of campaigns to it after redeem of amount
+// from it inside transaction, operationId is the redeem posting. Every redeem of customer which is charged
+// with fee or gets cashback goes through it. Returns account after fee and cashback.
+func (r *AccountRepo) debited(ctx context.Context, tx *pgx.Tx, op entity.FeeOperation, acc entity.Account, journalId, operationId int64, amount decimal.Decimal, meta entity.TransactionMeta) (entity.Account, error) {
+	acc, err := r.chargeFee(ctx, tx, op, acc, journalId, operationId, amount, meta)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - debited - r.chargeFee: %w", err)
+	}
+
+	acc, err = r.cashback(ctx, tx, acc, journalId, amount, meta)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - debited - r.cashback: %w", err)
+	}
+
+	return acc, nil
+}
+
+// replay - read response stored with idempotency key inside transaction to dst.
+// Found is false when key is not used yet.
+func (r *AccountRepo) replay(ctx context.Context, tx *pgx.Tx, key *entity.IdempotencyKey, dst interface{}) (found bool, err error) {
+	sql, args, err := r.Builder.
+		Select("request_hash, response").
+		From("idempotency_key").
+		Where(sq.Eq{"scope": key.Scope, "key": key.Key}).
 		ToSql()
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - r.Builder: %w", err)
+		return found, fmt.Errorf("AccountRepo - replay - r.Builder: %w", err)
+	}
+
+	var hash string
+	var response []byte
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&hash, &response)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return found, fmt.Errorf("AccountRepo - replay - tx.QueryRow: %w", err)
+	}
+
+	if hash != key.Hash {
+		return found, fmt.Errorf("AccountRepo - replay - validation: %w", entity.ErrIdempotencyConflict)
 	}
 
-	err = (*tx).QueryRow(ctx, sqlUpd, id, amount).Scan(&acc.Id, &acc.Balance, &acc.CreatedDt)
+	err = json.Unmarshal(response, dst)
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - tx.QueryRow: %w", err)
+		return found, fmt.Errorf("AccountRepo - replay - json.Unmarshal: %w", err)
 	}
 
-	_, err = (*tx).Exec(ctx, sqlIns, id, docNum, transType, amount)
+	return true, nil
+}
+
+// remember - store response with idempotency key inside transaction.
+func (r *AccountRepo) remember(ctx context.Context, tx *pgx.Tx, key *entity.IdempotencyKey, response interface{}) error {
+	data, err := json.Marshal(response)
 	if err != nil {
-		return acc, fmt.Errorf("AccountRepo - updBalance - tx.Exec: %w", err)
+		return fmt.Errorf("AccountRepo - remember - json.Marshal: %w", err)
 	}
 
-	return
+	sql, args, err := r.Builder.
+		Insert("idempotency_key").
+		Columns("scope, key, request_hash, response").
+		Values(key.Scope, key.Key, key.Hash, data).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AccountRepo - remember - r.Builder: %w", err)
+	}
+
+	_, err = (*tx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - remember - tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// lostRace - check that error of request is race with concurrent request: the same idempotency key is stored
+// by it or transaction can not be serialized with it.
+func lostRace(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && (pgErr.Code == _uniqueViolation || pgErr.Code == _serializationFailure)
+}
+
+// concurrentUpdate - wrap error of transaction which lost to concurrent one with entity.ErrConcurrentUpdate,
+// so it is retried as is instead of failure of operation.
+func concurrentUpdate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == _serializationFailure || pgErr.Code == _deadlockDetected) {
+		return fmt.Errorf("%s: %w", err, entity.ErrConcurrentUpdate)
+	}
+
+	return err
+}
+
+// replayRace - read response of concurrent request with the same idempotency key to dst when request lost
+// the race for the key, so the loser gets the original response or conflict like a retry. Err is returned
+// as is when the request has no key, did not lose a race or the key is not stored by the other request.
+func (r *AccountRepo) replayRace(ctx context.Context, key *entity.IdempotencyKey, dst interface{}, err error) error {
+	if key == nil || !lostRace(err) {
+		return err
+	}
+
+	tx, txErr := r.Pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if txErr != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	found, replayErr := r.replay(ctx, &tx, key, dst)
+	if replayErr != nil {
+		return fmt.Errorf("AccountRepo - replayRace - r.replay: %w", replayErr)
+	} else if !found {
+		return err
+	}
+
+	return nil
 }
 
-// UpdBalance - update account's balance.
-func (r *AccountRepo) UpdBalance(ctx context.Context, id, docNum int64, amount float64) (acc entity.Account, err error) {
+// UpdBalance - update account's balance in currency, meta is written to transaction history.
+// The other side of journal entry is system account in the same currency. The first accrual to unknown owner creates
+// the account in the same transaction. Redeem to cash out system account is withdrawal, it is charged with fee
+// and gets cashback of campaigns.
+// Request with idempotency key is done once, retry or concurrent request with the same key gets the original
+// account, nil key means no idempotency.
+func (r *AccountRepo) UpdBalance(ctx context.Context, ref entity.AccountRef, amount decimal.Decimal, currency string, system entity.SystemAccount, meta entity.TransactionMeta, key *entity.IdempotencyKey) (acc entity.Account, err error) {
 	transType, err := selectTransactionType(amount)
 	if err != nil {
 		return acc, fmt.Errorf("AccountRepo - UpdBalance - selectTransactionType: %w", err)
 	}
 
+	systemType, err := selectTransactionType(amount.Neg())
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - selectTransactionType: %w", err)
+	}
+
+	defer func() { err = concurrentUpdate(r.replayRace(ctx, key, &acc, err)) }()
+
 	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
 	if err != nil {
 		return acc, err
 	}
 	defer tx.Rollback(ctx)
 
-	acc, err = r.updBalance(ctx, &tx, transType, id, docNum, amount)
+	if key != nil {
+		found, err := r.replay(ctx, &tx, key, &acc)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - UpdBalance - r.replay: %w", err)
+		} else if found {
+			return acc, nil
+		}
+	}
+
+	createCurrency := ""
+	if amount.IsPositive() {
+		createCurrency = currency
+	}
+
+	id, accCurrency, err := r.resolve(ctx, &tx, ref, createCurrency)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - r.resolve: %w", err)
+	}
+
+	if accCurrency != currency {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - validation: %w", entity.ErrCurrencyMismatch)
+	}
+
+	systemId, err := r.systemAccount(ctx, &tx, system, currency)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - r.systemAccount: %w", err)
+	}
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - r.journal: %w", err)
+	}
+
+	acc, transId, err := r.updBalance(ctx, &tx, balanceChange{transType: transType, id: id, docNum: systemId, journalId: journalId, amount: amount, meta: meta})
 	if err != nil {
 		fmt.Println(err)
 		if errors.Is(err, pgx.ErrTxCommitRollback) {
@@ -156,6 +708,25 @@ func (r *AccountRepo) UpdBalance(ctx context.Context, id, docNum int64, amount f
 		return acc, fmt.Errorf("AccountRepo - UpdBalance - r.updBalance: %w", err)
 	}
 
+	_, _, err = r.updBalance(ctx, &tx, balanceChange{transType: systemType, id: systemId, docNum: id, journalId: journalId, amount: amount.Neg(), meta: meta})
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - UpdBalance - r.updBalance: %w", err)
+	}
+
+	if amount.IsNegative() && system == entity.SystemCashOut {
+		acc, err = r.debited(ctx, &tx, entity.FeeWithdrawal, acc, journalId, transId, amount.Neg(), meta)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - UpdBalance - r.debited: %w", err)
+		}
+	}
+
+	if key != nil {
+		err = r.remember(ctx, &tx, key, acc)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - UpdBalance - r.remember: %w", err)
+		}
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		fmt.Println(err)
@@ -168,22 +739,139 @@ func (r *AccountRepo) UpdBalance(ctx context.Context, id, docNum int64, amount f
 	return
 }
 
+// exchange - write postings of exchange system accounts which balance conversion of transfer in journal entry:
+// the exchange takes redeem amount in base currency and gives accrual amount in quote currency.
+func (r *AccountRepo) exchange(ctx context.Context, tx *pgx.Tx, journalId int64, rate *entity.Rate, redeemId, accrId int64, redeemAmount, accrAmount decimal.Decimal, meta entity.TransactionMeta) error {
+	baseId, err := r.systemAccount(ctx, tx, entity.SystemExchange, rate.Base)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - exchange - r.systemAccount: %w", err)
+	}
+
+	quoteId, err := r.systemAccount(ctx, tx, entity.SystemExchange, rate.Quote)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - exchange - r.systemAccount: %w", err)
+	}
+
+	_, _, err = r.updBalance(ctx, tx, balanceChange{transType: "accrual", id: baseId, docNum: redeemId, journalId: journalId, amount: redeemAmount, rate: rate, meta: meta})
+	if err != nil {
+		return fmt.Errorf("AccountRepo - exchange - r.updBalance: %w", err)
+	}
+
+	_, _, err = r.updBalance(ctx, tx, balanceChange{transType: "redeem", id: quoteId, docNum: accrId, journalId: journalId, amount: accrAmount.Neg(), rate: rate, meta: meta})
+	if err != nil {
+		return fmt.Errorf("AccountRepo - exchange - r.updBalance: %w", err)
+	}
+
+	return nil
+}
+
+// transferLegs - transfer from redeem account to accrual account in journal entry, rate of conversion is nil
+// for accounts in the same currency. Fee of operation is charged from redeem account.
+type transferLegs struct {
+	redeemId     int64
+	accrId       int64
+	journalId    int64
+	redeemAmount decimal.Decimal
+	accrAmount   decimal.Decimal
+	rate         *entity.Rate
+	fee          entity.FeeOperation
+	meta         entity.TransactionMeta
+}
+
+// transfer - write both legs of transfer inside transaction with fee and cashback of redeem account,
+// conversion goes through exchange system accounts.
+func (r *AccountRepo) transfer(ctx context.Context, tx *pgx.Tx, t transferLegs) (accrAcc, redeemAcc entity.Account, err error) {
+	redeemAcc, redeemTransId, err := r.updBalance(ctx, tx, balanceChange{transType: "redeem", id: t.redeemId, docNum: t.accrId, journalId: t.journalId, amount: t.redeemAmount.Neg(), rate: t.rate, meta: t.meta})
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - transfer - r.updBalance: %w", err)
+	}
+
+	redeemAcc, err = r.debited(ctx, tx, t.fee, redeemAcc, t.journalId, redeemTransId, t.redeemAmount, t.meta)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - transfer - r.debited: %w", err)
+	}
+
+	accrAcc, _, err = r.updBalance(ctx, tx, balanceChange{transType: "accrual", id: t.accrId, docNum: t.redeemId, journalId: t.journalId, amount: t.accrAmount, rate: t.rate, meta: t.meta})
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - transfer - r.updBalance: %w", err)
+	}
+
+	if t.rate == nil && redeemAcc.Currency != accrAcc.Currency ||
+		t.rate != nil && (redeemAcc.Currency != t.rate.Base || accrAcc.Currency != t.rate.Quote) {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - transfer - validation: %w", entity.ErrCurrencyMismatch)
+	}
+
+	if t.rate != nil {
+		err = r.exchange(ctx, tx, t.journalId, t.rate, redeemAcc.Id, accrAcc.Id, t.redeemAmount, t.accrAmount, t.meta)
+		if err != nil {
+			return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - transfer - r.exchange: %w", err)
+		}
+	}
+
+	return
+}
+
 // TransferAmount - transfer amount of money from redeem account to accrual account.
-func (r *AccountRepo) TransferAmount(ctx context.Context, redeemId, accrId int64, amount float64) (accrAcc, redeemAcc entity.Account, err error) {
+// Redeem amount is in currency of redeem account and accrual amount is in currency of accrual account,
+// the rate of conversion is passed when currencies are different. Accrual account of unknown owner
+// is created in the same transaction. Both legs of transfer are postings of one journal entry, conversion goes
+// through exchange system accounts of both currencies. Meta is written to both legs of transfer.
+// Fee of transfer is charged from redeem account and cashback of campaigns is paid to it in the same journal entry.
+// Request with idempotency key is done once, retry or concurrent request with the same key gets the original accounts.
+func (r *AccountRepo) TransferAmount(ctx context.Context, redeemRef, accrRef entity.AccountRef, redeemAmount, accrAmount decimal.Decimal, rate *entity.Rate, meta entity.TransactionMeta, key *entity.IdempotencyKey) (accrAcc, redeemAcc entity.Account, err error) {
+	defer func() {
+		pair := [2]entity.Account{accrAcc, redeemAcc}
+		err = concurrentUpdate(r.replayRace(ctx, key, &pair, err))
+		accrAcc, redeemAcc = pair[0], pair[1]
+	}()
+
 	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
 	if err != nil {
 		return accrAcc, redeemAcc, err
 	}
 	defer tx.Rollback(ctx)
 
-	redeemAcc, err = r.updBalance(ctx, &tx, "redeem", redeemId, accrId, -amount)
+	if key != nil {
+		pair := [2]entity.Account{}
+
+		found, err := r.replay(ctx, &tx, key, &pair)
+		if err != nil {
+			return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.replay: %w", err)
+		} else if found {
+			return pair[0], pair[1], nil
+		}
+	}
+
+	redeemId, accrCurrency, err := r.resolve(ctx, &tx, redeemRef, "")
 	if err != nil {
-		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.updBalance: %w", err)
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.resolve: %w", err)
+	}
+
+	if rate != nil {
+		accrCurrency = rate.Quote
 	}
 
-	accrAcc, err = r.updBalance(ctx, &tx, "accrual", accrId, redeemId, amount)
+	accrId, _, err := r.resolve(ctx, &tx, accrRef, accrCurrency)
 	if err != nil {
-		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.updBalance: %w", err)
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.resolve: %w", err)
+	}
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.journal: %w", err)
+	}
+
+	accrAcc, redeemAcc, err = r.transfer(ctx, &tx, transferLegs{redeemId: redeemId, accrId: accrId, journalId: journalId, redeemAmount: redeemAmount,
+		accrAmount: accrAmount, rate: rate, fee: entity.FeeTransfer, meta: meta})
+	if err != nil {
+		return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.transfer: %w", err)
+	}
+
+	if key != nil {
+		err = r.remember(ctx, &tx, key, [2]entity.Account{accrAcc, redeemAcc})
+		if err != nil {
+			return accrAcc, redeemAcc, fmt.Errorf("AccountRepo - TransferAmount - r.remember: %w", err)
+		}
 	}
 
 	err = tx.Commit(ctx)
@@ -194,18 +882,18 @@ func (r *AccountRepo) TransferAmount(ctx context.Context, redeemId, accrId int64
 	return
 }
 
-// GetHistory - get history of transaction.
-func (r *AccountRepo) GetHistory(ctx context.Context, id int64, limit, offset uint64, sort string, isDecreasing bool) (trns []*entity.Transaction, err error) {
+// GetHistory - get history of transaction with conditions of filter.
+func (r *AccountRepo) GetHistory(ctx context.Context, id int64, limit, offset uint64, sort string, isDecreasing bool, f entity.TransactionFilter) (trns []*entity.Transaction, err error) {
 	pred := fmt.Sprintf("%s ASC", sort)
 
 	if isDecreasing {
 		pred = fmt.Sprintf("%s DESC", sort)
 	}
 
-	sql, _, err := r.Builder.
-		Select("id, trans_dt, account_id, doc_num, type, amount").
+	sql, args, err := r.Builder.
+		Select(_transactionColumns).
 		From("fct_transcation").
-		Where(sq.Eq{"account_id": id}).
+		Where(transactionFilterPred(id, f)).
 		OrderBy(pred).
 		Limit(limit).
 		Offset(offset).
@@ -215,10 +903,216 @@ func (r *AccountRepo) GetHistory(ctx context.Context, id int64, limit, offset ui
 	}
 
 	if err := pgxscan.Select(
-		ctx, r.Pool, &trns, sql, id,
+		ctx, r.Pool, &trns, sql, args...,
 	); err != nil {
 		return nil, fmt.Errorf("AccountRepo - GetHistory - pgxscan.Select: %w", err)
 	}
 
 	return
 }
+
+// ChangeStatus - move account to status with reason. Closing account with non-zero balance
+// requires ID of account to sweep the balance to, otherwise sweepId is 0.
+func (r *AccountRepo) ChangeStatus(ctx context.Context, id int64, to entity.AccountStatus, reason string, sweepId int64) (acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
+		From("account").
+		Where(customerPred(id)).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - tx.QueryRow: %w", err)
+	}
+
+	from := acc.Status
+	if !from.CanChangeTo(to) {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - %s to %s: %w", from, to, entity.ErrStatusTransition)
+	}
+
+	if to == entity.AccountClosed && acc.Reserved.IsPositive() {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - validation: %w", entity.ErrHoldsActive)
+	}
+
+	if to == entity.AccountClosed {
+		open, err := r.openEscrows(ctx, &tx, id)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.openEscrows: %w", err)
+		} else if open {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - validation: %w", entity.ErrEscrowsOpen)
+		}
+	}
+
+	var sweep *int64
+	if to == entity.AccountClosed && !acc.Balance.IsZero() {
+		if sweepId == 0 || acc.Balance.IsNegative() {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - validation: %w", entity.ErrBalanceNotZero)
+		}
+
+		_, _, err = r.resolve(ctx, &tx, entity.AccountRef{Id: sweepId}, "")
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.resolve: %w", err)
+		}
+
+		sweep = &sweepId
+
+		journalId, err := r.journal(ctx, &tx)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.journal: %w", err)
+		}
+
+		_, _, err = r.updBalance(ctx, &tx, balanceChange{transType: "redeem", id: id, docNum: sweepId, journalId: journalId, amount: acc.Balance.Neg(), sweep: true})
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.updBalance: %w", err)
+		}
+
+		sweepAcc, _, err := r.updBalance(ctx, &tx, balanceChange{transType: "accrual", id: sweepId, docNum: id, journalId: journalId, amount: acc.Balance})
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.updBalance: %w", err)
+		}
+
+		if sweepAcc.Currency != acc.Currency {
+			return acc, fmt.Errorf("AccountRepo - ChangeStatus - validation: %w", entity.ErrCurrencyMismatch)
+		}
+	}
+
+	sql, args, err = r.Builder.
+		Update("account").
+		Set("status", string(to)).
+		Set("status_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + _accountColumns).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - tx.QueryRow: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("account_status_history").
+		Columns("account_id, from_status, to_status, reason, sweep_id, changed_dt").
+		Values(id, string(from), string(to), reason, sweep, acc.StatusDt).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - r.Builder: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - tx.Exec: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - ChangeStatus - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// SetCreditLimit - set credit limit of account, the limit can not be less than account's debt and holds.
+func (r *AccountRepo) SetCreditLimit(ctx context.Context, id int64, limit decimal.Decimal) (acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
+		From("account").
+		Where(customerPred(id)).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - tx.QueryRow: %w", err)
+	}
+
+	if acc.Status == entity.AccountClosed {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - validation: %w", entity.ErrAccountClosed)
+	}
+
+	if acc.Balance.Add(limit).LessThan(acc.Reserved) {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - validation: %w", entity.ErrLimitBelowDebt)
+	}
+
+	sql, args, err = r.Builder.
+		Update("account").
+		Set("credit_limit", limit).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + _accountColumns).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - tx.QueryRow: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetCreditLimit - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// SetTier - set tier of account, fee rules of the tier apply to its operations.
+func (r *AccountRepo) SetTier(ctx context.Context, id int64, tier entity.AccountTier) (acc entity.Account, err error) {
+	sql, args, err := r.Builder.
+		Update("account").
+		Set("tier", string(tier)).
+		Where(customerPred(id)).
+		Suffix("RETURNING " + _accountColumns).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetTier - r.Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - SetTier - r.Pool.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// GetStatusHistory - get history of account's status changes.
+func (r *AccountRepo) GetStatusHistory(ctx context.Context, id int64) (changes []*entity.StatusChange, err error) {
+	sql, args, err := r.Builder.
+		Select("id, account_id, from_status, to_status, reason, sweep_id, changed_dt").
+		From("account_status_history").
+		Where(sq.Eq{"account_id": id}).
+		OrderBy("changed_dt ASC").
+		ToSql()
+	if err != nil {
+		return changes, fmt.Errorf("AccountRepo - GetStatusHistory - r.Builder: %w", err)
+	}
+
+	if err := pgxscan.Select(
+		ctx, r.Pool, &changes, sql, args...,
+	); err != nil {
+		return nil, fmt.Errorf("AccountRepo - GetStatusHistory - pgxscan.Select: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,311 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _campaignColumns - columns of cashback campaign.
+const _campaignColumns = "id, name, purpose, currency, percent, monthly_cap, budget, spent, " +
+	"status, start_dt, end_dt, created_dt, updated_dt"
+
+// changedCampaign - error of change of campaign which is in another status, or not found.
+func (r *AccountRepo) changedCampaign(ctx context.Context, id int64, statusErr error) error {
+	_, err := r.GetCampaign(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return statusErr
+}
+
+// campaigns - get running active campaigns of redeems in currency with purpose inside transaction. Campaigns
+// are not locked, so redeems do not wait for each other, campaign is locked by spend of its budget.
+func (r *AccountRepo) campaigns(ctx context.Context, tx *pgx.Tx, currency, purpose string) (campaigns []*entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Select(_campaignColumns).
+		From("campaign").
+		Where(sq.Eq{"status": string(entity.CampaignActive), "currency": currency, "purpose": []string{"", purpose}}).
+		Where("start_dt <= NOW()").
+		Where(sq.Or{sq.Eq{"end_dt": nil}, sq.Expr("end_dt > NOW()")}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return campaigns, fmt.Errorf("AccountRepo - campaigns - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, *tx, &campaigns, sql, args...)
+	if err != nil {
+		return campaigns, fmt.Errorf("AccountRepo - campaigns - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// monthlyCashback - cashback of campaign paid to account in current calendar month (UTC) inside transaction.
+func (r *AccountRepo) monthlyCashback(ctx context.Context, tx *pgx.Tx, campaignId, accountId int64) (amount decimal.Decimal, err error) {
+	sql, args, err := r.Builder.
+		Select("COALESCE(SUM(amount), 0)").
+		From("fct_transcation").
+		Where(sq.Eq{"campaign_id": campaignId, "account_id": accountId}).
+		Where("trans_dt >= date_trunc('month', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'").
+		ToSql()
+	if err != nil {
+		return amount, fmt.Errorf("AccountRepo - monthlyCashback - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&amount)
+	if err != nil {
+		return amount, fmt.Errorf("AccountRepo - monthlyCashback - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// spendCampaign - add amount to spent budget of active campaign inside transaction, campaign is exhausted when
+// its budget is spent. Spent is false when the budget left is less than amount or campaign is not active anymore.
+func (r *AccountRepo) spendCampaign(ctx context.Context, tx *pgx.Tx, id int64, amount decimal.Decimal) (spent bool, err error) {
+	sql, args, err := r.Builder.
+		Update("campaign").
+		Set("spent", sq.Expr("spent + ?", amount)).
+		Set("status", sq.Expr("CASE WHEN spent + ? >= budget THEN ?::campaign_status ELSE status END", amount, string(entity.CampaignExhausted))).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": string(entity.CampaignActive)}).
+		Where(sq.Expr("spent + ? <= budget", amount)).
+		ToSql()
+	if err != nil {
+		return spent, fmt.Errorf("AccountRepo - spendCampaign - r.Builder: %w", err)
+	}
+
+	tag, err := (*tx).Exec(ctx, sql, args...)
+	if err != nil {
+		return spent, fmt.Errorf("AccountRepo - spendCampaign - tx.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// refundCampaign - return amount of cashback taken back to budget of campaign inside transaction.
+func (r *AccountRepo) refundCampaign(ctx context.Context, tx *pgx.Tx, id int64, amount decimal.Decimal) error {
+	sql, args, err := r.Builder.
+		Update("campaign").
+		Set("spent", sq.Expr("spent - ?", amount)).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AccountRepo - refundCampaign - r.Builder: %w", err)
+	}
+
+	_, err = (*tx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - refundCampaign - tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// cashback - pay cashback of redeem of amount with meta from account inside transaction by running campaigns.
+// Cashback is posted from promo system account in journal entry of redeem, it is limited by monthly cap
+// of campaign and by its budget left. Returns account after cashback.
+func (r *AccountRepo) cashback(ctx context.Context, tx *pgx.Tx, acc entity.Account, journalId int64, amount decimal.Decimal, meta entity.TransactionMeta) (entity.Account, error) {
+	campaigns, err := r.campaigns(ctx, tx, acc.Currency, meta.Purpose)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - cashback - r.campaigns: %w", err)
+	}
+
+	cur, _ := entity.CurrencyByCode(acc.Currency)
+
+	var promoId int64
+
+	for _, c := range campaigns {
+		cashback := cur.Round(c.Cashback(amount))
+
+		if c.MonthlyCap.Valid {
+			paid, err := r.monthlyCashback(ctx, tx, c.Id, acc.Id)
+			if err != nil {
+				return acc, fmt.Errorf("AccountRepo - cashback - r.monthlyCashback: %w", err)
+			}
+
+			cashback = decimal.Min(cashback, c.MonthlyCap.Decimal.Sub(paid))
+		}
+
+		cashback = decimal.Min(cashback, c.Budget.Sub(c.Spent))
+		if !cashback.IsPositive() {
+			continue
+		}
+
+		spent, err := r.spendCampaign(ctx, tx, c.Id, cashback)
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - cashback - r.spendCampaign: %w", err)
+		} else if !spent {
+			continue
+		}
+
+		if promoId == 0 {
+			promoId, err = r.systemAccount(ctx, tx, entity.SystemPromo, acc.Currency)
+			if err != nil {
+				return acc, fmt.Errorf("AccountRepo - cashback - r.systemAccount: %w", err)
+			}
+		}
+
+		cashbackMeta := entity.TransactionMeta{Description: c.Name, Purpose: meta.Purpose, Source: meta.Source}
+
+		acc, _, err = r.updBalance(ctx, tx, balanceChange{transType: "cashback", id: acc.Id, docNum: promoId, journalId: journalId, amount: cashback, campaignId: &c.Id, meta: cashbackMeta})
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - cashback - r.updBalance: %w", err)
+		}
+
+		_, _, err = r.updBalance(ctx, tx, balanceChange{transType: "cashback", id: promoId, docNum: acc.Id, journalId: journalId, amount: cashback.Neg(), campaignId: &c.Id, meta: cashbackMeta})
+		if err != nil {
+			return acc, fmt.Errorf("AccountRepo - cashback - r.updBalance: %w", err)
+		}
+	}
+
+	return acc, nil
+}
+
+// CreateCampaign - store active cashback campaign.
+func (r *AccountRepo) CreateCampaign(ctx context.Context, c entity.Campaign) (created entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Insert("campaign").
+		Columns("name, purpose, currency, percent, monthly_cap, budget, start_dt, end_dt").
+		Values(c.Name, c.Purpose, c.Currency, c.Percent, c.MonthlyCap, c.Budget, c.StartDt, c.EndDt).
+		Suffix("RETURNING " + _campaignColumns).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateCampaign - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &created, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateCampaign - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// GetCampaign - get cashback campaign by ID.
+func (r *AccountRepo) GetCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Select(_campaignColumns).
+		From("campaign").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return c, fmt.Errorf("AccountRepo - GetCampaign - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &c, sql, args...)
+	if pgxscan.NotFound(err) {
+		return c, fmt.Errorf("AccountRepo - GetCampaign - pgxscan.Get: %w", entity.ErrCampaignNotFound)
+	} else if err != nil {
+		return c, fmt.Errorf("AccountRepo - GetCampaign - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ListCampaigns - get cashback campaigns in status in order of ID, empty status means any status.
+func (r *AccountRepo) ListCampaigns(ctx context.Context, status entity.CampaignStatus) (campaigns []*entity.Campaign, err error) {
+	pred := sq.And{}
+
+	if status != "" {
+		pred = append(pred, sq.Eq{"status": string(status)})
+	}
+
+	sql, args, err := r.Builder.
+		Select(_campaignColumns).
+		From("campaign").
+		Where(pred).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return campaigns, fmt.Errorf("AccountRepo - ListCampaigns - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &campaigns, sql, args...)
+	if err != nil {
+		return campaigns, fmt.Errorf("AccountRepo - ListCampaigns - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// AddCampaignBudget - add amount to budget of campaign, exhausted campaign is active again.
+func (r *AccountRepo) AddCampaignBudget(ctx context.Context, id int64, amount decimal.Decimal) (c entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Update("campaign").
+		Set("budget", sq.Expr("budget + ?", amount)).
+		Set("status", sq.Expr("CASE WHEN status = ? THEN ?::campaign_status ELSE status END",
+			string(entity.CampaignExhausted), string(entity.CampaignActive))).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + _campaignColumns).
+		ToSql()
+	if err != nil {
+		return c, fmt.Errorf("AccountRepo - AddCampaignBudget - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &c, sql, args...)
+	if pgxscan.NotFound(err) {
+		return c, fmt.Errorf("AccountRepo - AddCampaignBudget - pgxscan.Get: %w", entity.ErrCampaignNotFound)
+	} else if err != nil {
+		return c, fmt.Errorf("AccountRepo - AddCampaignBudget - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// PauseCampaign - pause active or exhausted campaign.
+func (r *AccountRepo) PauseCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Update("campaign").
+		Set("status", string(entity.CampaignPaused)).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": []string{string(entity.CampaignActive), string(entity.CampaignExhausted)}}).
+		Suffix("RETURNING " + _campaignColumns).
+		ToSql()
+	if err != nil {
+		return c, fmt.Errorf("AccountRepo - PauseCampaign - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &c, sql, args...)
+	if pgxscan.NotFound(err) {
+		return c, fmt.Errorf("AccountRepo - PauseCampaign - r.changedCampaign: %w", r.changedCampaign(ctx, id, entity.ErrCampaignPaused))
+	} else if err != nil {
+		return c, fmt.Errorf("AccountRepo - PauseCampaign - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ResumeCampaign - resume paused campaign, it is exhausted when its budget is spent.
+func (r *AccountRepo) ResumeCampaign(ctx context.Context, id int64) (c entity.Campaign, err error) {
+	sql, args, err := r.Builder.
+		Update("campaign").
+		Set("status", sq.Expr("CASE WHEN budget > spent THEN ?::campaign_status ELSE ?::campaign_status END",
+			string(entity.CampaignActive), string(entity.CampaignExhausted))).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": string(entity.CampaignPaused)}).
+		Suffix("RETURNING " + _campaignColumns).
+		ToSql()
+	if err != nil {
+		return c, fmt.Errorf("AccountRepo - ResumeCampaign - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &c, sql, args...)
+	if pgxscan.NotFound(err) {
+		return c, fmt.Errorf("AccountRepo - ResumeCampaign - r.changedCampaign: %w", r.changedCampaign(ctx, id, entity.ErrCampaignNotPaused))
+	} else if err != nil {
+		return c, fmt.Errorf("AccountRepo - ResumeCampaign - pgxscan.Get: %w", err)
+	}
+
+	return
+}
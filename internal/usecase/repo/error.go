@@ -1,5 +0,0 @@
-package repo
-
-import "errors"
-
-var ErrNotEnoughMoney error = errors.New("not enough money")
@@ -0,0 +1,341 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+const (
+	// _escrowColumns - columns of safe deal.
+	_escrowColumns = "id, buyer_id, seller_id, order_id, amount, currency, status, expires_dt, created_dt, updated_dt, " +
+		"description, purpose, source"
+	// _escrowTimeoutReason - reason of refund of expired safe deal.
+	_escrowTimeoutReason = "timeout"
+)
+
+// getEscrow - lock safe deal by ID inside transaction.
+func (r *AccountRepo) getEscrow(ctx context.Context, tx *pgx.Tx, id int64) (e entity.Escrow, err error) {
+	sql, args, err := r.Builder.
+		Select(_escrowColumns).
+		From("escrow").
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return e, fmt.Errorf("AccountRepo - getEscrow - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, *tx, &e, sql, args...)
+	if pgxscan.NotFound(err) {
+		return e, fmt.Errorf("AccountRepo - getEscrow - pgxscan.Get: %w", entity.ErrEscrowNotFound)
+	} else if err != nil {
+		return e, fmt.Errorf("AccountRepo - getEscrow - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// recordEscrow - write status change of safe deal to its history inside transaction, from is nil for funding.
+func (r *AccountRepo) recordEscrow(ctx context.Context, tx *pgx.Tx, e entity.Escrow, from *entity.EscrowStatus, reason string, journalId *int64) error {
+	var fromStatus *string
+	if from != nil {
+		status := string(*from)
+		fromStatus = &status
+	}
+
+	sql, args, err := r.Builder.
+		Insert("escrow_history").
+		Columns("escrow_id, from_status, to_status, reason, journal_id, changed_dt").
+		Values(e.Id, fromStatus, string(e.Status), reason, journalId, e.UpdatedDt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AccountRepo - recordEscrow - r.Builder: %w", err)
+	}
+
+	_, err = (*tx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - recordEscrow - tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// openEscrows - check that account is buyer or seller of safe deal which is not released or refunded inside transaction.
+func (r *AccountRepo) openEscrows(ctx context.Context, tx *pgx.Tx, accountId int64) (open bool, err error) {
+	sql, args, err := r.Builder.
+		Select("1").
+		From("escrow").
+		Where(sq.Or{sq.Eq{"buyer_id": accountId}, sq.Eq{"seller_id": accountId}}).
+		Where(sq.Eq{"status": []string{string(entity.EscrowFunded), string(entity.EscrowDisputed)}}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return open, fmt.Errorf("AccountRepo - openEscrows - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&open)
+	if err != nil {
+		return open, fmt.Errorf("AccountRepo - openEscrows - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// settleEscrow - move safe deal to status with reason inside transaction. Release pays amount from escrow
+// system account to seller, refund returns it to buyer, dispute moves no money. Funded deal which is expired
+// can be refunded only. Returns account which gets the money, it is zero for dispute.
+func (r *AccountRepo) settleEscrow(ctx context.Context, tx *pgx.Tx, e entity.Escrow, to entity.EscrowStatus, reason string) (settled entity.Escrow, acc entity.Account, err error) {
+	from := e.Status
+	if !from.CanChangeTo(to) {
+		return e, acc, fmt.Errorf("AccountRepo - settleEscrow - %s to %s: %w", from, to, entity.ErrEscrowTransition)
+	}
+
+	if from == entity.EscrowFunded && to != entity.EscrowRefunded && !e.ExpiresDt.After(time.Now()) {
+		return e, acc, fmt.Errorf("AccountRepo - settleEscrow - validation: %w", entity.ErrEscrowExpired)
+	}
+
+	var journalId int64
+
+	if to != entity.EscrowDisputed {
+		payeeId := e.SellerId
+		if to == entity.EscrowRefunded {
+			payeeId = e.BuyerId
+		}
+
+		systemId, err := r.systemAccount(ctx, tx, entity.SystemEscrow, e.Currency)
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.systemAccount: %w", err)
+		}
+
+		journalId, err = r.journal(ctx, tx)
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.journal: %w", err)
+		}
+
+		_, _, err = r.updBalance(ctx, tx, balanceChange{transType: "redeem", id: systemId, docNum: payeeId, journalId: journalId, amount: e.Amount.Neg(), escrowId: &e.Id, meta: e.TransactionMeta})
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.updBalance: %w", err)
+		}
+
+		acc, _, err = r.updBalance(ctx, tx, balanceChange{transType: "accrual", id: payeeId, docNum: systemId, journalId: journalId, amount: e.Amount, escrowId: &e.Id, meta: e.TransactionMeta})
+		if err != nil {
+			return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.updBalance: %w", err)
+		}
+	}
+
+	sql, args, err := r.Builder.
+		Update("escrow").
+		Set("status", string(to)).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": e.Id}).
+		Suffix("RETURNING " + _escrowColumns).
+		ToSql()
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, *tx, &settled, sql, args...)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - settleEscrow - pgxscan.Get: %w", err)
+	}
+
+	var journal *int64
+	if journalId != 0 {
+		journal = &journalId
+	}
+
+	err = r.recordEscrow(ctx, tx, settled, &from, reason, journal)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - settleEscrow - r.recordEscrow: %w", err)
+	}
+
+	return
+}
+
+// CreateEscrow - fund safe deal: amount is moved from buyer to escrow system account in currency of buyer,
+// the deal expires after ttl. One order of buyer has one safe deal.
+func (r *AccountRepo) CreateEscrow(ctx context.Context, e entity.Escrow, ttl time.Duration) (created entity.Escrow, acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return created, acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Insert("escrow").
+		Columns("buyer_id, seller_id, order_id, amount, currency, expires_dt, description, purpose, source").
+		Values(e.BuyerId, e.SellerId, e.OrderId, e.Amount, e.Currency, sq.Expr("NOW() + ? * INTERVAL '1 second'", ttl.Seconds()),
+			e.Description, e.Purpose, e.Source).
+		Suffix("ON CONFLICT (buyer_id, order_id) DO NOTHING RETURNING " + _escrowColumns).
+		ToSql()
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &created, sql, args...)
+	if pgxscan.NotFound(err) {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - pgxscan.Get: %w", entity.ErrEscrowExists)
+	} else if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - pgxscan.Get: %w", err)
+	}
+
+	systemId, err := r.systemAccount(ctx, &tx, entity.SystemEscrow, e.Currency)
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.systemAccount: %w", err)
+	}
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.journal: %w", err)
+	}
+
+	acc, _, err = r.updBalance(ctx, &tx, balanceChange{transType: "redeem", id: e.BuyerId, docNum: systemId, journalId: journalId, amount: e.Amount.Neg(), escrowId: &created.Id, meta: e.TransactionMeta})
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.updBalance: %w", err)
+	}
+
+	_, _, err = r.updBalance(ctx, &tx, balanceChange{transType: "accrual", id: systemId, docNum: e.BuyerId, journalId: journalId, amount: e.Amount, escrowId: &created.Id, meta: e.TransactionMeta})
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.updBalance: %w", err)
+	}
+
+	if acc.Currency != e.Currency {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - validation: %w", entity.ErrCurrencyMismatch)
+	}
+
+	err = r.recordEscrow(ctx, &tx, created, nil, "", &journalId)
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - r.recordEscrow: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return created, acc, fmt.Errorf("AccountRepo - CreateEscrow - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// GetEscrow - get safe deal by ID.
+func (r *AccountRepo) GetEscrow(ctx context.Context, id int64) (e entity.Escrow, err error) {
+	sql, args, err := r.Builder.
+		Select(_escrowColumns).
+		From("escrow").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return e, fmt.Errorf("AccountRepo - GetEscrow - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &e, sql, args...)
+	if pgxscan.NotFound(err) {
+		return e, fmt.Errorf("AccountRepo - GetEscrow - pgxscan.Get: %w", entity.ErrEscrowNotFound)
+	} else if err != nil {
+		return e, fmt.Errorf("AccountRepo - GetEscrow - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ChangeEscrowStatus - move safe deal to status with reason, money is moved in the same transaction.
+// Returns account which gets the money, it is zero for dispute.
+func (r *AccountRepo) ChangeEscrowStatus(ctx context.Context, id int64, to entity.EscrowStatus, reason string) (e entity.Escrow, acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return e, acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	e, err = r.getEscrow(ctx, &tx, id)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - ChangeEscrowStatus - r.getEscrow: %w", err)
+	}
+
+	e, acc, err = r.settleEscrow(ctx, &tx, e, to, reason)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - ChangeEscrowStatus - r.settleEscrow: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return e, acc, fmt.Errorf("AccountRepo - ChangeEscrowStatus - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// RefundExpiredEscrows - refund up to limit funded safe deals which are expired, returns number of refunded deals.
+// Deals are locked with SKIP LOCKED, so several instances of service refund different deals at once.
+func (r *AccountRepo) RefundExpiredEscrows(ctx context.Context, limit uint64) (count int64, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: batchIsoLevel})
+	if err != nil {
+		return count, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_escrowColumns).
+		From("escrow").
+		Where(sq.Eq{"status": string(entity.EscrowFunded)}).
+		Where(sq.LtOrEq{"expires_dt": sq.Expr("NOW()")}).
+		OrderBy("expires_dt", "id").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - RefundExpiredEscrows - r.Builder: %w", err)
+	}
+
+	var escrows []entity.Escrow
+
+	err = pgxscan.Select(ctx, tx, &escrows, sql, args...)
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - RefundExpiredEscrows - pgxscan.Select: %w", err)
+	}
+
+	for _, e := range escrows {
+		_, _, err = r.settleEscrow(ctx, &tx, e, entity.EscrowRefunded, _escrowTimeoutReason)
+		if err != nil {
+			return count, fmt.Errorf("AccountRepo - RefundExpiredEscrows - r.settleEscrow: %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - RefundExpiredEscrows - tx.Commit: %w", err)
+	}
+
+	return int64(len(escrows)), nil
+}
+
+// GetEscrowHistory - get history of safe deal's status changes, the deal must exist.
+func (r *AccountRepo) GetEscrowHistory(ctx context.Context, id int64) (changes []*entity.EscrowChange, err error) {
+	_, err = r.GetEscrow(ctx, id)
+	if err != nil {
+		return changes, fmt.Errorf("AccountRepo - GetEscrowHistory - r.GetEscrow: %w", err)
+	}
+
+	sql, args, err := r.Builder.
+		Select("id, escrow_id, from_status, to_status, reason, journal_id, changed_dt").
+		From("escrow_history").
+		Where(sq.Eq{"escrow_id": id}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return changes, fmt.Errorf("AccountRepo - GetEscrowHistory - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &changes, sql, args...)
+	if err != nil {
+		return changes, fmt.Errorf("AccountRepo - GetEscrowHistory - pgxscan.Select: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,183 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _feeRuleColumns - columns of fee rule.
+const _feeRuleColumns = "id, operation, currency, tier, kind, flat, percent, tiers, min_fee, max_fee, active, created_dt"
+
+// feeRule - get active rule of operation in currency for account of tier inside transaction,
+// rule of the exact tier wins over rule of any tier. Found is false when operation has no fee.
+func (r *AccountRepo) feeRule(ctx context.Context, tx *pgx.Tx, op entity.FeeOperation, currency string, tier entity.AccountTier) (rule entity.FeeRule, found bool, err error) {
+	sql, args, err := r.Builder.
+		Select(_feeRuleColumns).
+		From("fee_rule").
+		Where(sq.Eq{"active": true, "operation": string(op), "currency": currency}).
+		Where(sq.Or{sq.Eq{"tier": string(tier)}, sq.Eq{"tier": nil}}).
+		OrderBy("tier NULLS LAST").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return rule, found, fmt.Errorf("AccountRepo - feeRule - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, *tx, &rule, sql, args...)
+	if pgxscan.NotFound(err) {
+		return rule, false, nil
+	} else if err != nil {
+		return rule, found, fmt.Errorf("AccountRepo - feeRule - pgxscan.Get: %w", err)
+	}
+
+	return rule, true, nil
+}
+
+// chargeFee - charge fee of operation with amount from account inside transaction by fee rule. The fee is posted
+// from account to fees system account in journal entry of operation, both postings point to operationId,
+// which is posting of operation on account. Returns account after fee, unchanged account when there is no fee.
+func (r *AccountRepo) chargeFee(ctx context.Context, tx *pgx.Tx, op entity.FeeOperation, acc entity.Account, journalId, operationId int64, amount decimal.Decimal, meta entity.TransactionMeta) (entity.Account, error) {
+	rule, found, err := r.feeRule(ctx, tx, op, acc.Currency, acc.Tier)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - chargeFee - r.feeRule: %w", err)
+	} else if !found {
+		return acc, nil
+	}
+
+	cur, _ := entity.CurrencyByCode(acc.Currency)
+
+	fee := cur.Round(rule.Fee(amount))
+	if !fee.IsPositive() {
+		return acc, nil
+	}
+
+	feesId, err := r.systemAccount(ctx, tx, entity.SystemFees, acc.Currency)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - chargeFee - r.systemAccount: %w", err)
+	}
+
+	charged, _, err := r.updBalance(ctx, tx, balanceChange{transType: "fee", id: acc.Id, docNum: feesId, journalId: journalId, amount: fee.Neg(), feeOf: &operationId, meta: meta})
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - chargeFee - r.updBalance: %w", err)
+	}
+
+	_, _, err = r.updBalance(ctx, tx, balanceChange{transType: "fee", id: feesId, docNum: acc.Id, journalId: journalId, amount: fee, feeOf: &operationId, meta: meta})
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - chargeFee - r.updBalance: %w", err)
+	}
+
+	return charged, nil
+}
+
+// CreateFeeRule - store active fee rule, it replaces active rule of the same operation, currency and tier.
+func (r *AccountRepo) CreateFeeRule(ctx context.Context, rule entity.FeeRule) (created entity.FeeRule, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return created, err
+	}
+	defer tx.Rollback(ctx)
+
+	pred := sq.Eq{"active": true, "operation": string(rule.Operation), "currency": rule.Currency, "tier": nil}
+	if rule.Tier != nil {
+		pred["tier"] = string(*rule.Tier)
+	}
+
+	sql, args, err := r.Builder.
+		Update("fee_rule").
+		Set("active", false).
+		Where(pred).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - r.Builder: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - tx.Exec: %w", err)
+	}
+
+	if rule.Tiers == nil {
+		rule.Tiers = []entity.FeeTier{}
+	}
+
+	tiers, err := json.Marshal(rule.Tiers)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - json.Marshal: %w", err)
+	}
+
+	var tier interface{}
+	if rule.Tier != nil {
+		tier = string(*rule.Tier)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("fee_rule").
+		Columns("operation, currency, tier, kind, flat, percent, tiers, min_fee, max_fee").
+		Values(string(rule.Operation), rule.Currency, tier, string(rule.Kind), rule.Flat, rule.Percent, tiers, rule.MinFee, rule.MaxFee).
+		Suffix("RETURNING " + _feeRuleColumns).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &created, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - pgxscan.Get: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateFeeRule - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// ListFeeRules - get active fee rules in order of operation, currency and tier.
+func (r *AccountRepo) ListFeeRules(ctx context.Context) (rules []*entity.FeeRule, err error) {
+	sql, args, err := r.Builder.
+		Select(_feeRuleColumns).
+		From("fee_rule").
+		Where(sq.Eq{"active": true}).
+		OrderBy("operation", "currency", "tier NULLS FIRST").
+		ToSql()
+	if err != nil {
+		return rules, fmt.Errorf("AccountRepo - ListFeeRules - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &rules, sql, args...)
+	if err != nil {
+		return rules, fmt.Errorf("AccountRepo - ListFeeRules - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// DisableFeeRule - disable active fee rule, operation is not charged by it anymore.
+func (r *AccountRepo) DisableFeeRule(ctx context.Context, id int64) (rule entity.FeeRule, err error) {
+	sql, args, err := r.Builder.
+		Update("fee_rule").
+		Set("active", false).
+		Where(sq.Eq{"id": id, "active": true}).
+		Suffix("RETURNING " + _feeRuleColumns).
+		ToSql()
+	if err != nil {
+		return rule, fmt.Errorf("AccountRepo - DisableFeeRule - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &rule, sql, args...)
+	if pgxscan.NotFound(err) {
+		return rule, fmt.Errorf("AccountRepo - DisableFeeRule - pgxscan.Get: %w", entity.ErrFeeRuleNotFound)
+	} else if err != nil {
+		return rule, fmt.Errorf("AccountRepo - DisableFeeRule - pgxscan.Get: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,366 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _holdColumns - columns of hold table in order of holdFields.
+const _holdColumns = "id, account_id, order_id, amount, captured, currency, status, created_dt, expires_dt, updated_dt"
+
+// holdFields - destinations to scan row of _holdColumns.
+func holdFields(h *entity.Hold) []interface{} {
+	return []interface{}{&h.Id, &h.AccountId, &h.OrderId, &h.Amount, &h.Captured, &h.Currency, &h.Status, &h.CreatedDt, &h.ExpiresDt, &h.UpdatedDt}
+}
+
+// scanHolds - scan rows of _holdColumns and close them.
+func scanHolds(rows pgx.Rows) ([]entity.Hold, error) {
+	defer rows.Close()
+
+	holds := make([]entity.Hold, 0, _defaultEntityCap)
+
+	for rows.Next() {
+		hold := entity.Hold{}
+
+		err := rows.Scan(holdFields(&hold)...)
+		if err != nil {
+			return nil, err
+		}
+
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// reserve - change amount reserved on account by hold inside transaction. Balance of account is not changed,
+// so the change is not a posting of journal entry, events of hold are its statuses in hold table.
+func (r *AccountRepo) reserve(ctx context.Context, tx *pgx.Tx, hold entity.Hold, amount decimal.Decimal) (acc entity.Account, err error) {
+	sql, args, err := r.Builder.
+		Update("account").
+		Set("reserved", sq.Expr("reserved + ?", amount)).
+		Where(sq.Eq{"id": hold.AccountId}).
+		Suffix("RETURNING " + _accountColumns).
+		ToSql()
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - reserve - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return acc, fmt.Errorf("AccountRepo - reserve - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// holdExpired - write memo of expired hold to transaction history inside transaction. Balance of account is not changed,
+// so the memo has zero amount and no journal entry, it has no other side and doc_num is the account itself.
+func (r *AccountRepo) holdExpired(ctx context.Context, tx *pgx.Tx, hold entity.Hold) error {
+	sql, args, err := r.Builder.
+		Insert("fct_transcation").
+		Columns("account_id, doc_num, type, amount, currency, hold_id, description").
+		Values(hold.AccountId, hold.AccountId, "hold_expired", decimal.Zero, hold.Currency, hold.Id,
+			fmt.Sprintf("hold of order %s for %s is expired", hold.OrderId, hold.Amount)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AccountRepo - holdExpired - r.Builder: %w", err)
+	}
+
+	_, err = (*tx).Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - holdExpired - tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// getHold - get hold by ID inside transaction.
+func (r *AccountRepo) getHold(ctx context.Context, tx *pgx.Tx, id int64) (hold entity.Hold, err error) {
+	sql, args, err := r.Builder.
+		Select(_holdColumns).
+		From("hold").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return hold, fmt.Errorf("AccountRepo - getHold - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(holdFields(&hold)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hold, fmt.Errorf("AccountRepo - getHold - tx.QueryRow: %w", entity.ErrHoldNotFound)
+	} else if err != nil {
+		return hold, fmt.Errorf("AccountRepo - getHold - tx.QueryRow: %w", err)
+	}
+
+	if hold.Status != entity.HoldActive {
+		return hold, fmt.Errorf("AccountRepo - getHold - validation: %w", entity.ErrHoldNotActive)
+	}
+
+	if !hold.ExpiresDt.After(time.Now()) {
+		return hold, fmt.Errorf("AccountRepo - getHold - validation: %w", entity.ErrHoldExpired)
+	}
+
+	return
+}
+
+// closeHold - move active hold to final status with captured amount inside transaction.
+func (r *AccountRepo) closeHold(ctx context.Context, tx *pgx.Tx, id int64, status entity.HoldStatus, captured decimal.Decimal) (hold entity.Hold, err error) {
+	sql, args, err := r.Builder.
+		Update("hold").
+		Set("status", string(status)).
+		Set("captured", captured).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + _holdColumns).
+		ToSql()
+	if err != nil {
+		return hold, fmt.Errorf("AccountRepo - closeHold - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(holdFields(&hold)...)
+	if err != nil {
+		return hold, fmt.Errorf("AccountRepo - closeHold - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// CreateHold - reserve amount on account for order for ttl, one order has one hold on account.
+func (r *AccountRepo) CreateHold(ctx context.Context, accountId int64, orderId string, amount decimal.Decimal, ttl time.Duration) (hold entity.Hold, acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return hold, acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_accountColumns).
+		From("account").
+		Where(customerPred(accountId)).
+		ToSql()
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(accountFields(&acc)...)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - tx.QueryRow: %w", err)
+	}
+
+	switch {
+	case acc.Status == entity.AccountClosed:
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - validation: %w", entity.ErrAccountClosed)
+	case acc.Status == entity.AccountFrozen:
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - validation: %w", entity.ErrAccountFrozen)
+	case acc.Available.LessThan(amount):
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - validation: %w", insufficientFunds(acc.Available, acc.CreditLimit))
+	}
+
+	sql, args, err = r.Builder.
+		Insert("hold").
+		Columns("account_id, order_id, amount, currency, expires_dt").
+		Values(accountId, orderId, amount, acc.Currency, sq.Expr("NOW() + ? * INTERVAL '1 second'", ttl.Seconds())).
+		Suffix("ON CONFLICT (account_id, order_id) DO NOTHING RETURNING " + _holdColumns).
+		ToSql()
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(holdFields(&hold)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - tx.QueryRow: %w", entity.ErrHoldExists)
+	} else if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - tx.QueryRow: %w", err)
+	}
+
+	acc, err = r.reserve(ctx, &tx, hold, amount)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - r.reserve: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CreateHold - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// CaptureHold - charge amount of active hold from account to cash-out system account, the rest of hold is released.
+// Capture is withdrawal, so it is charged with fee of withdrawal and gets cashback of campaigns.
+func (r *AccountRepo) CaptureHold(ctx context.Context, id int64, amount decimal.Decimal) (hold entity.Hold, acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return hold, acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err = r.getHold(ctx, &tx, id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.getHold: %w", err)
+	}
+
+	if amount.GreaterThan(hold.Amount) {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - validation: %w", entity.ErrHoldExceeded)
+	}
+
+	_, err = r.reserve(ctx, &tx, hold, hold.Amount.Neg())
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.reserve: %w", err)
+	}
+
+	systemId, err := r.systemAccount(ctx, &tx, entity.SystemCashOut, hold.Currency)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.systemAccount: %w", err)
+	}
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.journal: %w", err)
+	}
+
+	acc, transId, err := r.updBalance(ctx, &tx, balanceChange{transType: "redeem", id: hold.AccountId, docNum: systemId, journalId: journalId, amount: amount.Neg(), holdId: &hold.Id})
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.updBalance: %w", err)
+	}
+
+	acc, err = r.debited(ctx, &tx, entity.FeeWithdrawal, acc, journalId, transId, amount, entity.TransactionMeta{})
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.debited: %w", err)
+	}
+
+	_, _, err = r.updBalance(ctx, &tx, balanceChange{transType: "accrual", id: systemId, docNum: hold.AccountId, journalId: journalId, amount: amount, holdId: &hold.Id})
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.updBalance: %w", err)
+	}
+
+	hold, err = r.closeHold(ctx, &tx, id, entity.HoldCaptured, amount)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - r.closeHold: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - CaptureHold - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// ReleaseHold - return amount of active hold to available balance of account.
+func (r *AccountRepo) ReleaseHold(ctx context.Context, id int64) (hold entity.Hold, acc entity.Account, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return hold, acc, err
+	}
+	defer tx.Rollback(ctx)
+
+	hold, err = r.getHold(ctx, &tx, id)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - ReleaseHold - r.getHold: %w", err)
+	}
+
+	acc, err = r.reserve(ctx, &tx, hold, hold.Amount.Neg())
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - ReleaseHold - r.reserve: %w", err)
+	}
+
+	hold, err = r.closeHold(ctx, &tx, id, entity.HoldReleased, decimal.Zero)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - ReleaseHold - r.closeHold: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return hold, acc, fmt.Errorf("AccountRepo - ReleaseHold - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// GetHold - get hold by ID.
+func (r *AccountRepo) GetHold(ctx context.Context, id int64) (hold entity.Hold, err error) {
+	sql, args, err := r.Builder.
+		Select(_holdColumns).
+		From("hold").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return hold, fmt.Errorf("AccountRepo - GetHold - r.Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(holdFields(&hold)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hold, fmt.Errorf("AccountRepo - GetHold - r.Pool.QueryRow: %w", entity.ErrHoldNotFound)
+	} else if err != nil {
+		return hold, fmt.Errorf("AccountRepo - GetHold - r.Pool.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// ExpireHolds - release up to limit active holds which are expired and write hold_expired memo for each of them,
+// returns number of released holds.
+// Holds are locked with SKIP LOCKED, so several instances of service expire different holds at once.
+func (r *AccountRepo) ExpireHolds(ctx context.Context, limit uint64) (count int64, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: batchIsoLevel})
+	if err != nil {
+		return count, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_holdColumns).
+		From("hold").
+		Where(sq.Eq{"status": string(entity.HoldActive)}).
+		Where(sq.LtOrEq{"expires_dt": sq.Expr("NOW()")}).
+		OrderBy("expires_dt", "id").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - ExpireHolds - r.Builder: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - ExpireHolds - tx.Query: %w", err)
+	}
+
+	holds, err := scanHolds(rows)
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - ExpireHolds - scanHolds: %w", err)
+	}
+
+	for _, hold := range holds {
+		_, err = r.reserve(ctx, &tx, hold, hold.Amount.Neg())
+		if err != nil {
+			return count, fmt.Errorf("AccountRepo - ExpireHolds - r.reserve: %w", err)
+		}
+
+		_, err = r.closeHold(ctx, &tx, hold.Id, entity.HoldExpired, decimal.Zero)
+		if err != nil {
+			return count, fmt.Errorf("AccountRepo - ExpireHolds - r.closeHold: %w", err)
+		}
+
+		err = r.holdExpired(ctx, &tx, hold)
+		if err != nil {
+			return count, fmt.Errorf("AccountRepo - ExpireHolds - r.holdExpired: %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return count, fmt.Errorf("AccountRepo - ExpireHolds - tx.Commit: %w", err)
+	}
+
+	return int64(len(holds)), nil
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// _unbalancedJournalsLimit - max number of unbalanced journal entries in result of ledger check.
+const _unbalancedJournalsLimit = 100
+
+// CheckLedger - sum postings of all journal entries by currency and find journal entries which are not balanced.
+// Sums are read in one snapshot.
+func (r *AccountRepo) CheckLedger(ctx context.Context) (check entity.LedgerCheck, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return check, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select("currency, SUM(amount) AS sum").
+		From("fct_transcation").
+		GroupBy("currency").
+		OrderBy("currency").
+		ToSql()
+	if err != nil {
+		return check, fmt.Errorf("AccountRepo - CheckLedger - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, tx, &check.Totals, sql, args...)
+	if err != nil {
+		return check, fmt.Errorf("AccountRepo - CheckLedger - pgxscan.Select: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Select("DISTINCT journal_id").
+		From("fct_transcation").
+		GroupBy("journal_id", "currency").
+		Having("SUM(amount) <> 0").
+		OrderBy("journal_id").
+		Limit(_unbalancedJournalsLimit).
+		ToSql()
+	if err != nil {
+		return check, fmt.Errorf("AccountRepo - CheckLedger - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, tx, &check.UnbalancedJournals, sql, args...)
+	if err != nil {
+		return check, fmt.Errorf("AccountRepo - CheckLedger - pgxscan.Select: %w", err)
+	}
+
+	check.Balanced = len(check.UnbalancedJournals) == 0
+
+	for _, total := range check.Totals {
+		if !total.Sum.IsZero() {
+			check.Balanced = false
+		}
+	}
+
+	return
+}
+
+// SystemAccounts - get chart of system accounts with their balances, which are sums of their postings.
+func (r *AccountRepo) SystemAccounts(ctx context.Context) (accs []entity.LedgerAccount, err error) {
+	sql, args, err := r.Builder.
+		Select("a.id, a.system_code AS code, a.currency, COALESCE(SUM(t.amount), 0) AS balance").
+		From("account a").
+		LeftJoin("fct_transcation t ON t.account_id = a.id AND t.journal_id IS NOT NULL").
+		Where(sq.Eq{"a.type": string(entity.AccountSystem)}).
+		GroupBy("a.id").
+		OrderBy("a.id DESC").
+		ToSql()
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - SystemAccounts - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &accs, sql, args...)
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - SystemAccounts - pgxscan.Select: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// _inverseRatePrecision - number of decimal places of rate calculated from reverse pair.
+const _inverseRatePrecision = 10
+
+// RateFile - exchange rates stored in JSON file.
+type RateFile struct {
+	path string
+}
+
+// NewRateFile - create new file rate provider.
+func NewRateFile(path string) *RateFile {
+	return &RateFile{path}
+}
+
+// Rate - get rate of currency pair, file is read on each call.
+func (r *RateFile) Rate(ctx context.Context, base, quote string) (rate entity.Rate, err error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return rate, fmt.Errorf("RateFile - Rate - os.ReadFile: %w", err)
+	}
+
+	var rates []entity.Rate
+	err = json.Unmarshal(data, &rates)
+	if err != nil {
+		return rate, fmt.Errorf("RateFile - Rate - json.Unmarshal: %w", err)
+	}
+
+	for _, rt := range rates {
+		if rt.Base == base && rt.Quote == quote {
+			return rt, nil
+		}
+	}
+
+	for _, rt := range rates {
+		if rt.Base == quote && rt.Quote == base && !rt.Value.IsZero() {
+			rate = entity.Rate{
+				Base:      base,
+				Quote:     quote,
+				Value:     decimal.NewFromInt(1).DivRound(rt.Value, _inverseRatePrecision),
+				Spread:    rt.Spread,
+				ValidFrom: rt.ValidFrom,
+			}
+
+			return rate, nil
+		}
+	}
+
+	return rate, fmt.Errorf("RateFile - Rate - %s/%s: %w", base, quote, entity.ErrRateNotFound)
+}
@@ -0,0 +1,165 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/pkg/postgres"
+)
+
+// RateRepo - repository with history of exchange rates.
+type RateRepo struct {
+	*postgres.Postgres
+}
+
+// NewRateRepo - create new exchange rate repository.
+func NewRateRepo(pg *postgres.Postgres) *RateRepo {
+	return &RateRepo{pg}
+}
+
+// Create - add rate to history, the rate is valid till the next rate of the pair.
+// History is append-only: rate must start after the latest rate of the pair, which is closed by the new one.
+func (r *RateRepo) Create(ctx context.Context, rate entity.Rate) (entity.Rate, error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - r.Pool.BeginTx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	pair := sq.Eq{"base": rate.Base, "quote": rate.Quote}
+
+	sql, args, err := r.Builder.
+		Select("COUNT(*)").
+		From("exchange_rate").
+		Where(pair).
+		Where(sq.GtOrEq{"valid_from": rate.ValidFrom}).
+		ToSql()
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - r.Builder: %w", err)
+	}
+
+	var count int
+	err = tx.QueryRow(ctx, sql, args...).Scan(&count)
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - tx.QueryRow: %w", err)
+	}
+
+	if count > 0 {
+		return rate, fmt.Errorf("RateRepo - Create - %s/%s: %w", rate.Base, rate.Quote, entity.ErrRateConflict)
+	}
+
+	sql, args, err = r.Builder.
+		Update("exchange_rate").
+		Set("valid_to", rate.ValidFrom).
+		Where(pair).
+		Where(sq.Eq{"valid_to": nil}).
+		ToSql()
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - r.Builder: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - tx.Exec: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("exchange_rate").
+		Columns("base, quote, rate, spread, valid_from").
+		Values(rate.Base, rate.Quote, rate.Value, rate.Spread, rate.ValidFrom).
+		Suffix("RETURNING \"id\"").
+		ToSql()
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - r.Builder: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, sql, args...).Scan(&rate.Id)
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - tx.QueryRow: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - Create - tx.Commit: %w", err)
+	}
+
+	return rate, nil
+}
+
+// getAt - get rate of pair which was valid at the moment.
+func (r *RateRepo) getAt(ctx context.Context, base, quote string, at time.Time) (rate entity.Rate, err error) {
+	sql, args, err := r.Builder.
+		Select("id, base, quote, rate, spread, valid_from, valid_to").
+		From("exchange_rate").
+		Where(sq.Eq{"base": base, "quote": quote}).
+		Where(sq.LtOrEq{"valid_from": at}).
+		Where(sq.Or{sq.Eq{"valid_to": nil}, sq.Gt{"valid_to": at}}).
+		ToSql()
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - getAt - r.Builder: %w", err)
+	}
+
+	err = r.Pool.QueryRow(ctx, sql, args...).Scan(&rate.Id, &rate.Base, &rate.Quote, &rate.Value, &rate.Spread, &rate.ValidFrom, &rate.ValidTo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rate, fmt.Errorf("RateRepo - getAt - %s/%s: %w", base, quote, entity.ErrRateNotFound)
+	}
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - getAt - r.Pool.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// GetAt - get rate of pair which was valid at the moment, the rate of reverse pair is inverted if direct one is absent.
+func (r *RateRepo) GetAt(ctx context.Context, base, quote string, at time.Time) (rate entity.Rate, err error) {
+	rate, err = r.getAt(ctx, base, quote, at)
+	if !errors.Is(err, entity.ErrRateNotFound) {
+		return
+	}
+
+	rate, err = r.getAt(ctx, quote, base, at)
+	if err != nil {
+		return rate, fmt.Errorf("RateRepo - GetAt - r.getAt: %w", err)
+	}
+
+	rate.Base, rate.Quote = base, quote
+	rate.Value = decimal.NewFromInt(1).DivRound(rate.Value, _inverseRatePrecision)
+
+	return
+}
+
+// Rate - get current rate of pair.
+func (r *RateRepo) Rate(ctx context.Context, base, quote string) (entity.Rate, error) {
+	return r.GetAt(ctx, base, quote, time.Now())
+}
+
+// GetHistory - get history of pair's rates.
+func (r *RateRepo) GetHistory(ctx context.Context, base, quote string, limit, offset uint64) (rates []*entity.Rate, err error) {
+	sql, args, err := r.Builder.
+		Select("id, base, quote, rate AS value, spread, valid_from, valid_to").
+		From("exchange_rate").
+		Where(sq.Eq{"base": base, "quote": quote}).
+		OrderBy("valid_from DESC").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return rates, fmt.Errorf("RateRepo - GetHistory - r.Builder: %w", err)
+	}
+
+	if err := pgxscan.Select(
+		ctx, r.Pool, &rates, sql, args...,
+	); err != nil {
+		return nil, fmt.Errorf("RateRepo - GetHistory - pgxscan.Select: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,253 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// _recurringColumns - columns of recurring payment plan.
+const _recurringColumns = "id, redeem_id, accrual_id, amount, currency, schedule, status, next_charge_dt, next_attempt_dt, charges, max_charges, " +
+	"end_dt, failed_attempts, created_dt, updated_dt, description, purpose, source"
+
+// _recurringAttemptColumns - columns of attempt of recurring charge.
+const _recurringAttemptColumns = "id, plan_id, charge, attempt_dt, amount, status, failure, failure_code"
+
+// changedRecurring - error of change of plan which is in another status, or not found.
+func (r *AccountRepo) changedRecurring(ctx context.Context, id int64, statusErr error) error {
+	_, err := r.GetRecurringPlan(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return statusErr
+}
+
+// CreateRecurringPlan - store recurring payment plan, the first charge is at next charge time.
+func (r *AccountRepo) CreateRecurringPlan(ctx context.Context, plan entity.RecurringPlan) (created entity.RecurringPlan, err error) {
+	sql, args, err := r.Builder.
+		Insert("recurring_plan").
+		Columns("redeem_id, accrual_id, amount, currency, schedule, next_charge_dt, next_attempt_dt, max_charges, end_dt, description, purpose, source").
+		Values(plan.RedeemId, plan.AccrualId, plan.Amount, plan.Currency, plan.Schedule, plan.NextChargeDt, plan.NextChargeDt, plan.MaxCharges, plan.EndDt,
+			plan.Description, plan.Purpose, plan.Source).
+		Suffix("RETURNING " + _recurringColumns).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateRecurringPlan - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &created, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateRecurringPlan - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// GetRecurringPlan - get recurring payment plan by ID.
+func (r *AccountRepo) GetRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	sql, args, err := r.Builder.
+		Select(_recurringColumns).
+		From("recurring_plan").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - GetRecurringPlan - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &plan, sql, args...)
+	if pgxscan.NotFound(err) {
+		return plan, fmt.Errorf("AccountRepo - GetRecurringPlan - pgxscan.Get: %w", entity.ErrRecurringNotFound)
+	} else if err != nil {
+		return plan, fmt.Errorf("AccountRepo - GetRecurringPlan - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ListRecurringPlans - find recurring payment plans by filter in order of ID.
+func (r *AccountRepo) ListRecurringPlans(ctx context.Context, f entity.RecurringPlanFilter) (plans []*entity.RecurringPlan, err error) {
+	pred := sq.And{}
+
+	if f.AccountId != 0 {
+		pred = append(pred, sq.Or{sq.Eq{"redeem_id": f.AccountId}, sq.Eq{"accrual_id": f.AccountId}})
+	}
+
+	if f.Status != "" {
+		pred = append(pred, sq.Eq{"status": string(f.Status)})
+	}
+
+	sql, args, err := r.Builder.
+		Select(_recurringColumns).
+		From("recurring_plan").
+		Where(pred).
+		OrderBy("id").
+		Limit(f.Limit).
+		Offset(f.Offset).
+		ToSql()
+	if err != nil {
+		return plans, fmt.Errorf("AccountRepo - ListRecurringPlans - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &plans, sql, args...)
+	if err != nil {
+		return plans, fmt.Errorf("AccountRepo - ListRecurringPlans - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// CancelRecurringPlan - cancel active or suspended plan.
+func (r *AccountRepo) CancelRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	sql, args, err := r.Builder.
+		Update("recurring_plan").
+		Set("status", string(entity.RecurringCancelled)).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": []string{string(entity.RecurringActive), string(entity.RecurringSuspended)}}).
+		Suffix("RETURNING " + _recurringColumns).
+		ToSql()
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - CancelRecurringPlan - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &plan, sql, args...)
+	if pgxscan.NotFound(err) {
+		return plan, fmt.Errorf("AccountRepo - CancelRecurringPlan - r.changedRecurring: %w", r.changedRecurring(ctx, id, entity.ErrRecurringClosed))
+	} else if err != nil {
+		return plan, fmt.Errorf("AccountRepo - CancelRecurringPlan - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ResumeRecurringPlan - activate suspended plan, the failed charge is attempted at once with retries from the start.
+func (r *AccountRepo) ResumeRecurringPlan(ctx context.Context, id int64) (plan entity.RecurringPlan, err error) {
+	sql, args, err := r.Builder.
+		Update("recurring_plan").
+		Set("status", string(entity.RecurringActive)).
+		Set("failed_attempts", 0).
+		Set("next_attempt_dt", sq.Expr("NOW()")).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": string(entity.RecurringSuspended)}).
+		Suffix("RETURNING " + _recurringColumns).
+		ToSql()
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - ResumeRecurringPlan - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &plan, sql, args...)
+	if pgxscan.NotFound(err) {
+		return plan, fmt.Errorf("AccountRepo - ResumeRecurringPlan - r.changedRecurring: %w", r.changedRecurring(ctx, id, entity.ErrRecurringNotSuspended))
+	} else if err != nil {
+		return plan, fmt.Errorf("AccountRepo - ResumeRecurringPlan - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// DueRecurringPlans - get up to limit active plans which time of the next attempt has come.
+func (r *AccountRepo) DueRecurringPlans(ctx context.Context, limit uint64) (plans []*entity.RecurringPlan, err error) {
+	sql, args, err := r.Builder.
+		Select(_recurringColumns).
+		From("recurring_plan").
+		Where(sq.Eq{"status": string(entity.RecurringActive)}).
+		Where(sq.LtOrEq{"next_attempt_dt": sq.Expr("NOW()")}).
+		OrderBy("next_attempt_dt", "id").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return plans, fmt.Errorf("AccountRepo - DueRecurringPlans - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &plans, sql, args...)
+	if err != nil {
+		return plans, fmt.Errorf("AccountRepo - DueRecurringPlans - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// RecordRecurringAttempt - store attempt of charge and move plan from prev state to next one.
+// Plan which is changed since prev state was read, e.g. by another instance of service, is entity.ErrRecurringNotDue.
+func (r *AccountRepo) RecordRecurringAttempt(ctx context.Context, prev, next entity.RecurringPlan, attempt entity.RecurringAttempt) (plan entity.RecurringPlan, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return plan, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Update("recurring_plan").
+		Set("status", string(next.Status)).
+		Set("next_charge_dt", next.NextChargeDt).
+		Set("next_attempt_dt", next.NextAttemptDt).
+		Set("charges", next.Charges).
+		Set("failed_attempts", next.FailedAttempts).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{
+			"id":              prev.Id,
+			"status":          string(entity.RecurringActive),
+			"next_charge_dt":  prev.NextChargeDt,
+			"charges":         prev.Charges,
+			"failed_attempts": prev.FailedAttempts,
+		}).
+		Suffix("RETURNING " + _recurringColumns).
+		ToSql()
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &plan, sql, args...)
+	if pgxscan.NotFound(err) {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - pgxscan.Get: %w", entity.ErrRecurringNotDue)
+	} else if err != nil {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - pgxscan.Get: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("recurring_attempt").
+		Columns("plan_id, charge, amount, status, failure, failure_code").
+		Values(prev.Id, attempt.Charge, attempt.Amount, string(attempt.Status), attempt.Failure, string(attempt.FailureCode)).
+		ToSql()
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - r.Builder: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - tx.Exec: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return plan, fmt.Errorf("AccountRepo - RecordRecurringAttempt - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// GetRecurringAttempts - get attempts of charges of plan in order of attempts.
+func (r *AccountRepo) GetRecurringAttempts(ctx context.Context, planId int64, limit, offset uint64) (attempts []*entity.RecurringAttempt, err error) {
+	sql, args, err := r.Builder.
+		Select(_recurringAttemptColumns).
+		From("recurring_attempt").
+		Where(sq.Eq{"plan_id": planId}).
+		OrderBy("id").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return attempts, fmt.Errorf("AccountRepo - GetRecurringAttempts - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &attempts, sql, args...)
+	if err != nil {
+		return attempts, fmt.Errorf("AccountRepo - GetRecurringAttempts - pgxscan.Select: %w", err)
+	}
+
+	return
+}
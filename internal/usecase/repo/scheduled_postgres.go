@@ -0,0 +1,280 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _scheduledColumns - columns of scheduled transfer.
+const _scheduledColumns = "id, redeem_id, accrual_id, amount, currency, status, run_dt, next_attempt_dt, attempts, failure, failure_code, journal_id, " +
+	"created_dt, updated_dt, description, purpose, source"
+
+// closedScheduled - error of change of scheduled transfer which is not pending, or not found.
+func (r *AccountRepo) closedScheduled(ctx context.Context, id int64) error {
+	_, err := r.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return entity.ErrScheduledNotPending
+}
+
+// CreateScheduledTransfer - store transfer which is made at run time.
+func (r *AccountRepo) CreateScheduledTransfer(ctx context.Context, st entity.ScheduledTransfer) (created entity.ScheduledTransfer, err error) {
+	sql, args, err := r.Builder.
+		Insert("scheduled_transfer").
+		Columns("redeem_id, accrual_id, amount, currency, run_dt, next_attempt_dt, description, purpose, source").
+		Values(st.RedeemId, st.AccrualId, st.Amount, st.Currency, st.RunDt, st.RunDt, st.Description, st.Purpose, st.Source).
+		Suffix("RETURNING " + _scheduledColumns).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &created, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// GetScheduledTransfer - get scheduled transfer by ID.
+func (r *AccountRepo) GetScheduledTransfer(ctx context.Context, id int64) (st entity.ScheduledTransfer, err error) {
+	sql, args, err := r.Builder.
+		Select(_scheduledColumns).
+		From("scheduled_transfer").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - GetScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &st, sql, args...)
+	if pgxscan.NotFound(err) {
+		return st, fmt.Errorf("AccountRepo - GetScheduledTransfer - pgxscan.Get: %w", entity.ErrScheduledNotFound)
+	} else if err != nil {
+		return st, fmt.Errorf("AccountRepo - GetScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ListScheduledTransfers - find scheduled transfers by filter in order of run time.
+func (r *AccountRepo) ListScheduledTransfers(ctx context.Context, f entity.ScheduledTransferFilter) (sts []*entity.ScheduledTransfer, err error) {
+	pred := sq.And{}
+
+	if f.AccountId != 0 {
+		pred = append(pred, sq.Or{sq.Eq{"redeem_id": f.AccountId}, sq.Eq{"accrual_id": f.AccountId}})
+	}
+
+	if f.Status != "" {
+		pred = append(pred, sq.Eq{"status": string(f.Status)})
+	}
+
+	sql, args, err := r.Builder.
+		Select(_scheduledColumns).
+		From("scheduled_transfer").
+		Where(pred).
+		OrderBy("run_dt", "id").
+		Limit(f.Limit).
+		Offset(f.Offset).
+		ToSql()
+	if err != nil {
+		return sts, fmt.Errorf("AccountRepo - ListScheduledTransfers - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &sts, sql, args...)
+	if err != nil {
+		return sts, fmt.Errorf("AccountRepo - ListScheduledTransfers - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// UpdateScheduledTransfer - change amount and run time of pending transfer, zero values are not changed.
+// The change starts attempts of transfer over.
+func (r *AccountRepo) UpdateScheduledTransfer(ctx context.Context, id int64, amount decimal.Decimal, runDt time.Time) (st entity.ScheduledTransfer, err error) {
+	upd := r.Builder.
+		Update("scheduled_transfer").
+		Set("attempts", 0).
+		Set("failure", "").
+		Set("failure_code", "").
+		Set("updated_dt", sq.Expr("NOW()"))
+
+	if !amount.IsZero() {
+		upd = upd.Set("amount", amount)
+	}
+
+	if !runDt.IsZero() {
+		upd = upd.Set("run_dt", runDt).Set("next_attempt_dt", runDt)
+	} else {
+		upd = upd.Set("next_attempt_dt", sq.Expr("run_dt"))
+	}
+
+	sql, args, err := upd.
+		Where(sq.Eq{"id": id, "status": string(entity.ScheduledPending)}).
+		Suffix("RETURNING " + _scheduledColumns).
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - UpdateScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &st, sql, args...)
+	if pgxscan.NotFound(err) {
+		return st, fmt.Errorf("AccountRepo - UpdateScheduledTransfer - r.closedScheduled: %w", r.closedScheduled(ctx, id))
+	} else if err != nil {
+		return st, fmt.Errorf("AccountRepo - UpdateScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// CancelScheduledTransfer - cancel pending transfer.
+func (r *AccountRepo) CancelScheduledTransfer(ctx context.Context, id int64) (st entity.ScheduledTransfer, err error) {
+	sql, args, err := r.Builder.
+		Update("scheduled_transfer").
+		Set("status", string(entity.ScheduledCancelled)).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": string(entity.ScheduledPending)}).
+		Suffix("RETURNING " + _scheduledColumns).
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - CancelScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &st, sql, args...)
+	if pgxscan.NotFound(err) {
+		return st, fmt.Errorf("AccountRepo - CancelScheduledTransfer - r.closedScheduled: %w", r.closedScheduled(ctx, id))
+	} else if err != nil {
+		return st, fmt.Errorf("AccountRepo - CancelScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// DueScheduledTransfers - get IDs of up to limit pending transfers which time of the next attempt has come.
+func (r *AccountRepo) DueScheduledTransfers(ctx context.Context, limit uint64) (ids []int64, err error) {
+	sql, args, err := r.Builder.
+		Select("id").
+		From("scheduled_transfer").
+		Where(sq.Eq{"status": string(entity.ScheduledPending)}).
+		Where(sq.LtOrEq{"next_attempt_dt": sq.Expr("NOW()")}).
+		OrderBy("next_attempt_dt", "id").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return ids, fmt.Errorf("AccountRepo - DueScheduledTransfers - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &ids, sql, args...)
+	if err != nil {
+		return ids, fmt.Errorf("AccountRepo - DueScheduledTransfers - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// ExecuteScheduledTransfer - make pending transfer which time of the next attempt has come. Transfer is locked
+// with SKIP LOCKED, so transfer which is made by another instance of service, or is not due, is entity.ErrScheduledNotDue.
+// Transfer which lost to concurrent operation is entity.ErrConcurrentUpdate.
+func (r *AccountRepo) ExecuteScheduledTransfer(ctx context.Context, id int64) (st entity.ScheduledTransfer, err error) {
+	defer func() { err = concurrentUpdate(err) }()
+
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return st, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.Builder.
+		Select(_scheduledColumns).
+		From("scheduled_transfer").
+		Where(sq.Eq{"id": id, "status": string(entity.ScheduledPending)}).
+		Where(sq.LtOrEq{"next_attempt_dt": sq.Expr("NOW()")}).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &st, sql, args...)
+	if pgxscan.NotFound(err) {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - pgxscan.Get: %w", entity.ErrScheduledNotDue)
+	} else if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.journal: %w", err)
+	}
+
+	_, _, err = r.transfer(ctx, &tx, transferLegs{redeemId: st.RedeemId, accrId: st.AccrualId, journalId: journalId, redeemAmount: st.Amount,
+		accrAmount: st.Amount, fee: entity.FeeTransfer, meta: st.TransactionMeta})
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.transfer: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Update("scheduled_transfer").
+		Set("status", string(entity.ScheduledExecuted)).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("failure", "").
+		Set("failure_code", "").
+		Set("journal_id", journalId).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		Suffix("RETURNING " + _scheduledColumns).
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &st, sql, args...)
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// FailScheduledTransfer - record failed attempt of pending transfer with code and text of its reason. The next attempt
+// is made after retryDelay, transfer fails when it has maxAttempts failed attempts.
+func (r *AccountRepo) FailScheduledTransfer(ctx context.Context, id int64, code entity.FailureCode, reason string, maxAttempts int, retryDelay time.Duration) (st entity.ScheduledTransfer, err error) {
+	sql, args, err := r.Builder.
+		Update("scheduled_transfer").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("failure", reason).
+		Set("failure_code", string(code)).
+		Set("status", sq.Expr("CASE WHEN attempts + 1 >= ? THEN ?::scheduled_status ELSE status END", maxAttempts, string(entity.ScheduledFailed))).
+		Set("next_attempt_dt", sq.Expr("NOW() + ? * INTERVAL '1 second'", retryDelay.Seconds())).
+		Set("updated_dt", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "status": string(entity.ScheduledPending)}).
+		Suffix("RETURNING " + _scheduledColumns).
+		ToSql()
+	if err != nil {
+		return st, fmt.Errorf("AccountRepo - FailScheduledTransfer - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &st, sql, args...)
+	if pgxscan.NotFound(err) {
+		return st, fmt.Errorf("AccountRepo - FailScheduledTransfer - r.closedScheduled: %w", r.closedScheduled(ctx, id))
+	} else if err != nil {
+		return st, fmt.Errorf("AccountRepo - FailScheduledTransfer - pgxscan.Get: %w", err)
+	}
+
+	return
+}
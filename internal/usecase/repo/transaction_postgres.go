@@ -0,0 +1,277 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _transactionColumns - columns of transaction history.
+const _transactionColumns = "id, trans_dt, account_id, doc_num, type, amount, currency, rate_id, rate, spread, hold_id, journal_id, reversal_of, fee_of, campaign_id, escrow_id, " +
+	"description, purpose, source, (SELECT batch_id FROM journal WHERE journal.id = fct_transcation.journal_id) AS batch_id"
+
+// _likeEscaper - escapes wildcards of LIKE pattern.
+var _likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// transactionFilterPred - predicate of account's transaction history conditions, history of system account is not returned.
+func transactionFilterPred(accountId int64, f entity.TransactionFilter) sq.And {
+	pred := sq.And{
+		sq.Eq{"account_id": accountId},
+		sq.Expr("account_id IN (SELECT id FROM account WHERE type = ?)", string(entity.AccountCustomer)),
+	}
+
+	if f.Description != "" {
+		pred = append(pred, sq.ILike{"description": "%" + _likeEscaper.Replace(f.Description) + "%"})
+	}
+
+	if f.Purpose != "" {
+		pred = append(pred, sq.Eq{"purpose": f.Purpose})
+	}
+
+	if f.Source != "" {
+		pred = append(pred, sq.Eq{"source": f.Source})
+	}
+
+	return pred
+}
+
+// reversalLeg - transaction with amount of it to reverse.
+type reversalLeg struct {
+	trn    entity.Transaction
+	amount decimal.Decimal
+}
+
+// change - change of account balance by reversal of leg.
+func (l reversalLeg) change() decimal.Decimal {
+	if l.trn.Amount.IsPositive() {
+		return l.amount.Neg()
+	}
+
+	return l.amount
+}
+
+// getTransaction - get transaction of history by ID inside transaction.
+func (r *AccountRepo) getTransaction(ctx context.Context, tx *pgx.Tx, id int64) (trn entity.Transaction, err error) {
+	sql, args, err := r.Builder.
+		Select(_transactionColumns).
+		From("fct_transcation").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return trn, fmt.Errorf("AccountRepo - getTransaction - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, *tx, &trn, sql, args...)
+	if pgxscan.NotFound(err) {
+		return trn, fmt.Errorf("AccountRepo - getTransaction - pgxscan.Get: %w", entity.ErrTransactionNotFound)
+	} else if err != nil {
+		return trn, fmt.Errorf("AccountRepo - getTransaction - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// postings - get original postings of journal entry inside transaction.
+func (r *AccountRepo) postings(ctx context.Context, tx *pgx.Tx, journalId int64) (trns []entity.Transaction, err error) {
+	sql, args, err := r.Builder.
+		Select(_transactionColumns).
+		From("fct_transcation").
+		Where(sq.Eq{"journal_id": journalId, "reversal_of": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - postings - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, *tx, &trns, sql, args...)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - postings - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// remaining - amount of transaction which is not reversed yet.
+func (r *AccountRepo) remaining(ctx context.Context, tx *pgx.Tx, trn entity.Transaction) (amount decimal.Decimal, err error) {
+	sql, args, err := r.Builder.
+		Select("COALESCE(SUM(ABS(amount)), 0)").
+		From("fct_transcation").
+		Where(sq.Eq{"reversal_of": trn.Id}).
+		ToSql()
+	if err != nil {
+		return amount, fmt.Errorf("AccountRepo - remaining - r.Builder: %w", err)
+	}
+
+	var reversed decimal.Decimal
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&reversed)
+	if err != nil {
+		return amount, fmt.Errorf("AccountRepo - remaining - tx.QueryRow: %w", err)
+	}
+
+	return trn.Amount.Abs().Sub(reversed), nil
+}
+
+// GetTransaction - get transaction of history by ID.
+func (r *AccountRepo) GetTransaction(ctx context.Context, id int64) (trn entity.Transaction, err error) {
+	sql, args, err := r.Builder.
+		Select(_transactionColumns).
+		From("fct_transcation").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return trn, fmt.Errorf("AccountRepo - GetTransaction - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &trn, sql, args...)
+	if pgxscan.NotFound(err) {
+		return trn, fmt.Errorf("AccountRepo - GetTransaction - pgxscan.Get: %w", entity.ErrTransactionNotFound)
+	} else if err != nil {
+		return trn, fmt.Errorf("AccountRepo - GetTransaction - pgxscan.Get: %w", err)
+	}
+
+	return
+}
+
+// ReverseTransaction - reverse amount of accrual or redeem with new journal entry of compensating transactions
+// which point to the original ones and have the same meta, zero amount means the whole remaining amount.
+// All postings of the original journal entry are reversed at once, including the other leg of transfer
+// and system accounts, amount of each posting is in proportion to reversed amount.
+func (r *AccountRepo) ReverseTransaction(ctx context.Context, id int64, amount decimal.Decimal) (trns []*entity.Transaction, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return trns, err
+	}
+	defer tx.Rollback(ctx)
+
+	trn, err := r.getTransaction(ctx, &tx, id)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.getTransaction: %w", err)
+	}
+
+	// Money of safe deal is moved by its status changes only.
+	if trn.JournalId == nil || trn.ReversalOf != nil || trn.EscrowId != nil || trn.Type != "accrual" && trn.Type != "redeem" {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - validation: %w", entity.ErrNotReversible)
+	}
+
+	// Posting of system account is not reachable by customer, the entry is reversed by posting of customer account.
+	_, _, err = r.resolve(ctx, &tx, entity.AccountRef{Id: trn.AccountId}, "")
+	if errors.Is(err, pgx.ErrNoRows) {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - validation: %w", entity.ErrNotReversible)
+	} else if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.resolve: %w", err)
+	}
+
+	remaining, err := r.remaining(ctx, &tx, trn)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.remaining: %w", err)
+	}
+
+	if amount.IsZero() {
+		amount = remaining
+	}
+
+	if !amount.IsPositive() || amount.GreaterThan(remaining) {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - validation: %w", entity.ErrReversalExceeded)
+	}
+
+	postings, err := r.postings(ctx, &tx, *trn.JournalId)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.postings: %w", err)
+	}
+
+	legs := make([]reversalLeg, 0, len(postings))
+
+	for _, posting := range postings {
+		legAmount, err := r.remaining(ctx, &tx, posting)
+		if err != nil {
+			return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.remaining: %w", err)
+		}
+
+		// Postings come in pairs of equal amounts in journal entry, legs of operation, fee and cashback,
+		// so they are rounded equally and reversal entry stays balanced.
+		if amount.LessThan(remaining) {
+			cur, _ := entity.CurrencyByCode(posting.Currency)
+			legAmount = decimal.Min(legAmount, cur.Round(amount.Mul(posting.Amount.Abs()).Div(trn.Amount.Abs())))
+		}
+
+		// Fee or cashback too small to return in part is kept, both legs of it are rounded to zero.
+		if legAmount.IsZero() && (posting.FeeOf != nil || posting.CampaignId != nil) {
+			continue
+		}
+
+		if !legAmount.IsPositive() {
+			return trns, fmt.Errorf("AccountRepo - ReverseTransaction - validation: %w", entity.ErrReversalTooSmall)
+		}
+
+		legs = append(legs, reversalLeg{trn: posting, amount: legAmount})
+	}
+
+	// Accounts which give money back go before accounts which get it, so reversal of transfer fails
+	// when the money is already spent. Cashback is taken back from the refund of its redeem,
+	// as legs of account are netted and money comes to account before it goes.
+	net := make(map[int64]decimal.Decimal, len(legs))
+	for _, leg := range legs {
+		net[leg.trn.AccountId] = net[leg.trn.AccountId].Add(leg.change())
+	}
+
+	sort.SliceStable(legs, func(i, j int) bool {
+		gi, gj := net[legs[i].trn.AccountId].IsNegative(), net[legs[j].trn.AccountId].IsNegative()
+		if gi != gj {
+			return gi
+		}
+
+		return legs[i].change().IsPositive() && legs[j].change().IsNegative()
+	})
+
+	journalId, err := r.journal(ctx, &tx)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.journal: %w", err)
+	}
+
+	for _, leg := range legs {
+		_, transId, err := r.updBalance(ctx, &tx, balanceChange{
+			transType:  "reversal",
+			id:         leg.trn.AccountId,
+			docNum:     leg.trn.DocNum,
+			journalId:  journalId,
+			amount:     leg.change(),
+			reversalOf: &leg.trn.Id,
+			campaignId: leg.trn.CampaignId,
+			meta:       leg.trn.TransactionMeta,
+		})
+		if err != nil {
+			return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.updBalance: %w", err)
+		}
+
+		// Cashback taken back from customer returns to budget of its campaign.
+		if leg.trn.CampaignId != nil && leg.trn.Amount.IsPositive() {
+			err = r.refundCampaign(ctx, &tx, *leg.trn.CampaignId, leg.amount)
+			if err != nil {
+				return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.refundCampaign: %w", err)
+			}
+		}
+
+		rev, err := r.getTransaction(ctx, &tx, transId)
+		if err != nil {
+			return trns, fmt.Errorf("AccountRepo - ReverseTransaction - r.getTransaction: %w", err)
+		}
+
+		trns = append(trns, &rev)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return trns, fmt.Errorf("AccountRepo - ReverseTransaction - tx.Commit: %w", err)
+	}
+
+	return
+}
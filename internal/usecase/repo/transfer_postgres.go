@@ -0,0 +1,204 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	pgx "github.com/jackc/pgx/v4"
+)
+
+// lockAccounts - lock customer accounts inside transaction in order of ID, so concurrent operations
+// with several accounts wait for each other instead of deadlock. IDs must be sorted.
+func (r *AccountRepo) lockAccounts(ctx context.Context, tx *pgx.Tx, ids []int64) error {
+	sql, args, err := r.Builder.
+		Select("id").
+		From("account").
+		Where(sq.Eq{"id": ids, "type": string(entity.AccountCustomer)}).
+		OrderBy("id").
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("AccountRepo - lockAccounts - r.Builder: %w", err)
+	}
+
+	rows, err := (*tx).Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - lockAccounts - tx.Query: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("AccountRepo - lockAccounts - rows.Err: %w", err)
+	}
+
+	if count != len(ids) {
+		return fmt.Errorf("AccountRepo - lockAccounts - validation: %w", entity.ErrAccountNotFound)
+	}
+
+	return nil
+}
+
+// BatchTransfer - make all legs of transfer at once or none of them. Every leg has its own journal entry with its fee
+// and cashback, so leg is reversed apart from the others, entries of legs are one batch. Accounts are locked
+// in order of ID before the first leg. Returns all accounts of legs in order of ID.
+// Request with idempotency key is done once, retry or concurrent request with the same key gets the original accounts.
+func (r *AccountRepo) BatchTransfer(ctx context.Context, legs []entity.TransferLeg, key *entity.IdempotencyKey) (accs []entity.Account, err error) {
+	defer func() { err = concurrentUpdate(r.replayRace(ctx, key, &accs, err)) }()
+
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return accs, err
+	}
+	defer tx.Rollback(ctx)
+
+	if key != nil {
+		found, err := r.replay(ctx, &tx, key, &accs)
+		if err != nil {
+			return accs, fmt.Errorf("AccountRepo - BatchTransfer - r.replay: %w", err)
+		} else if found {
+			return accs, nil
+		}
+	}
+
+	byId := make(map[int64]entity.Account, 2*len(legs))
+	ids := make([]int64, 0, 2*len(legs))
+
+	for _, leg := range legs {
+		for _, id := range []int64{leg.RedeemId, leg.AccrualId} {
+			if _, ok := byId[id]; !ok {
+				byId[id] = entity.Account{}
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	err = r.lockAccounts(ctx, &tx, ids)
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - BatchTransfer - r.lockAccounts: %w", err)
+	}
+
+	var batchId int64
+
+	for _, leg := range legs {
+		journalId, err := r.batchJournal(ctx, &tx, batchId)
+		if err != nil {
+			return accs, fmt.Errorf("AccountRepo - BatchTransfer - r.batchJournal: %w", err)
+		}
+
+		if batchId == 0 {
+			batchId = journalId
+		}
+
+		accrAcc, redeemAcc, err := r.transfer(ctx, &tx, transferLegs{redeemId: leg.RedeemId, accrId: leg.AccrualId, journalId: journalId, redeemAmount: leg.Amount,
+			accrAmount: leg.Amount, fee: entity.FeeBatchTransfer, meta: leg.TransactionMeta})
+		if err != nil {
+			return accs, fmt.Errorf("AccountRepo - BatchTransfer - r.transfer: %w", err)
+		}
+
+		byId[redeemAcc.Id], byId[accrAcc.Id] = redeemAcc, accrAcc
+	}
+
+	accs = make([]entity.Account, 0, len(ids))
+	for _, id := range ids {
+		accs = append(accs, byId[id])
+	}
+
+	if key != nil {
+		err = r.remember(ctx, &tx, key, accs)
+		if err != nil {
+			return accs, fmt.Errorf("AccountRepo - BatchTransfer - r.remember: %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return accs, fmt.Errorf("AccountRepo - BatchTransfer - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// SplitTransfer - transfer amounts of shares from redeem account to payee accounts at once. Every share is a transfer
+// with its own journal entry, fee and cashback, so share is reversed apart from the others, entries of shares
+// are one batch. Accounts are locked in order of ID before the first share.
+// Request with idempotency key is done once, retry or concurrent request with the same key gets the original result.
+func (r *AccountRepo) SplitTransfer(ctx context.Context, redeemId int64, shares []entity.SplitShare, meta entity.TransactionMeta, key *entity.IdempotencyKey) (split entity.SplitTransfer, err error) {
+	defer func() { err = concurrentUpdate(r.replayRace(ctx, key, &split, err)) }()
+
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return split, err
+	}
+	defer tx.Rollback(ctx)
+
+	if key != nil {
+		found, err := r.replay(ctx, &tx, key, &split)
+		if err != nil {
+			return split, fmt.Errorf("AccountRepo - SplitTransfer - r.replay: %w", err)
+		} else if found {
+			return split, nil
+		}
+	}
+
+	ids := make([]int64, 0, len(shares)+1)
+	ids = append(ids, redeemId)
+	for _, share := range shares {
+		ids = append(ids, share.AccountId)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	err = r.lockAccounts(ctx, &tx, ids)
+	if err != nil {
+		return split, fmt.Errorf("AccountRepo - SplitTransfer - r.lockAccounts: %w", err)
+	}
+
+	var batchId int64
+	split.AccrualAccounts = make([]entity.Account, 0, len(shares))
+
+	for _, share := range shares {
+		journalId, err := r.batchJournal(ctx, &tx, batchId)
+		if err != nil {
+			return split, fmt.Errorf("AccountRepo - SplitTransfer - r.batchJournal: %w", err)
+		}
+
+		if batchId == 0 {
+			batchId = journalId
+		}
+
+		accrAcc, redeemAcc, err := r.transfer(ctx, &tx, transferLegs{redeemId: redeemId, accrId: share.AccountId, journalId: journalId, redeemAmount: share.Amount,
+			accrAmount: share.Amount, fee: entity.FeeTransfer, meta: meta})
+		if err != nil {
+			return split, fmt.Errorf("AccountRepo - SplitTransfer - r.transfer: %w", err)
+		}
+
+		split.RedeemAccount = redeemAcc
+		split.AccrualAccounts = append(split.AccrualAccounts, accrAcc)
+	}
+
+	split.Shares = shares
+
+	if key != nil {
+		err = r.remember(ctx, &tx, key, split)
+		if err != nil {
+			return split, fmt.Errorf("AccountRepo - SplitTransfer - r.remember: %w", err)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return split, fmt.Errorf("AccountRepo - SplitTransfer - tx.Commit: %w", err)
+	}
+
+	return
+}
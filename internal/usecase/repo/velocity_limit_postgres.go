@@ -0,0 +1,199 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/georgysavva/scany/pgxscan"
+	pgx "github.com/jackc/pgx/v4"
+	"github.com/shopspring/decimal"
+)
+
+// _velocityLimitColumns - columns of velocity limit.
+const _velocityLimitColumns = "id, account_id, currency, period, max_amount, max_count, active, created_dt"
+
+// velocityLimits - get active limits of account in currency inside transaction, one limit per period,
+// limit of the exact account wins over default limit.
+func (r *AccountRepo) velocityLimits(ctx context.Context, tx *pgx.Tx, accountId int64, currency string) (limits []*entity.VelocityLimit, err error) {
+	sql, args, err := r.Builder.
+		Select(_velocityLimitColumns).
+		Options("DISTINCT ON (period)").
+		From("velocity_limit").
+		Where(sq.Eq{"active": true, "currency": currency}).
+		Where(sq.Or{sq.Eq{"account_id": accountId}, sq.Eq{"account_id": nil}}).
+		OrderBy("period", "account_id NULLS LAST").
+		ToSql()
+	if err != nil {
+		return limits, fmt.Errorf("AccountRepo - velocityLimits - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, *tx, &limits, sql, args...)
+	if err != nil {
+		return limits, fmt.Errorf("AccountRepo - velocityLimits - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// debits - total amount and number of debits of account in current calendar period (UTC) inside transaction.
+// Postings of journal entry or of entries of one batch are one debit, postings of the current entry journalId
+// and of its batch are in amount, but not in count.
+// Reversals of debits of the period are netted out of amount, reversed debit is still in count.
+func (r *AccountRepo) debits(ctx context.Context, tx *pgx.Tx, accountId, journalId int64, period entity.LimitPeriod) (amount decimal.Decimal, count int64, err error) {
+	start := sq.Expr("trans_dt >= date_trunc(?, NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'", string(period))
+	redeems := sq.And{sq.Eq{"account_id": accountId, "type": "redeem"}, sq.Lt{"amount": 0}, start}
+
+	sql, args, err := r.Builder.
+		Select("COALESCE(-SUM(amount), 0)").
+		Column(sq.Expr("COUNT(DISTINCT COALESCE(j.batch_id, j.id)) FILTER (WHERE type = 'redeem' AND "+
+			"COALESCE(j.batch_id, j.id) <> (SELECT COALESCE(batch_id, id) FROM journal WHERE id = ?))", journalId)).
+		From("fct_transcation").
+		Join("journal j ON j.id = journal_id").
+		Where(sq.Or{
+			redeems,
+			sq.And{
+				sq.Eq{"account_id": accountId, "type": "reversal"},
+				start,
+				sq.Expr("reversal_of IN (?)", sq.Select("id").From("fct_transcation").Where(redeems)),
+			},
+		}).
+		ToSql()
+	if err != nil {
+		return amount, count, fmt.Errorf("AccountRepo - debits - r.Builder: %w", err)
+	}
+
+	err = (*tx).QueryRow(ctx, sql, args...).Scan(&amount, &count)
+	if err != nil {
+		return amount, count, fmt.Errorf("AccountRepo - debits - tx.QueryRow: %w", err)
+	}
+
+	return
+}
+
+// checkVelocity - check that debit of amount from account in journal entry fits velocity limits of account
+// in every period inside transaction, the first exceeded limit is returned as entity.VelocityLimitError.
+func (r *AccountRepo) checkVelocity(ctx context.Context, tx *pgx.Tx, accountId, journalId int64, currency string, amount decimal.Decimal) error {
+	limits, err := r.velocityLimits(ctx, tx, accountId, currency)
+	if err != nil {
+		return fmt.Errorf("AccountRepo - checkVelocity - r.velocityLimits: %w", err)
+	}
+
+	for _, l := range limits {
+		spent, count, err := r.debits(ctx, tx, accountId, journalId, l.Period)
+		if err != nil {
+			return fmt.Errorf("AccountRepo - checkVelocity - r.debits: %w", err)
+		}
+
+		if l.MaxAmount.Valid && spent.Add(amount).GreaterThan(l.MaxAmount.Decimal) {
+			return &entity.VelocityLimitError{Period: l.Period, Kind: entity.LimitAmount, Max: l.MaxAmount.Decimal, Remaining: decimal.Max(l.MaxAmount.Decimal.Sub(spent), decimal.Zero)}
+		}
+
+		if l.MaxCount != nil && count+1 > *l.MaxCount {
+			max := decimal.NewFromInt(*l.MaxCount)
+
+			return &entity.VelocityLimitError{Period: l.Period, Kind: entity.LimitCount, Max: max, Remaining: decimal.Max(max.Sub(decimal.NewFromInt(count)), decimal.Zero)}
+		}
+	}
+
+	return nil
+}
+
+// CreateVelocityLimit - store active velocity limit, it replaces active limit of the same account, currency and period.
+func (r *AccountRepo) CreateVelocityLimit(ctx context.Context, limit entity.VelocityLimit) (created entity.VelocityLimit, err error) {
+	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
+	if err != nil {
+		return created, err
+	}
+	defer tx.Rollback(ctx)
+
+	pred := sq.Eq{"active": true, "currency": limit.Currency, "period": string(limit.Period), "account_id": nil}
+	if limit.AccountId != nil {
+		pred["account_id"] = *limit.AccountId
+	}
+
+	sql, args, err := r.Builder.
+		Update("velocity_limit").
+		Set("active", false).
+		Where(pred).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateVelocityLimit - r.Builder: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateVelocityLimit - tx.Exec: %w", err)
+	}
+
+	sql, args, err = r.Builder.
+		Insert("velocity_limit").
+		Columns("account_id, currency, period, max_amount, max_count").
+		Values(limit.AccountId, limit.Currency, string(limit.Period), limit.MaxAmount, limit.MaxCount).
+		Suffix("RETURNING " + _velocityLimitColumns).
+		ToSql()
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateVelocityLimit - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, tx, &created, sql, args...)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateVelocityLimit - pgxscan.Get: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return created, fmt.Errorf("AccountRepo - CreateVelocityLimit - tx.Commit: %w", err)
+	}
+
+	return
+}
+
+// ListVelocityLimits - get active velocity limits of account and default ones in order of currency, period
+// and account, zero accountId means limits of all accounts.
+func (r *AccountRepo) ListVelocityLimits(ctx context.Context, accountId int64) (limits []*entity.VelocityLimit, err error) {
+	builder := r.Builder.
+		Select(_velocityLimitColumns).
+		From("velocity_limit").
+		Where(sq.Eq{"active": true}).
+		OrderBy("currency", "period", "account_id NULLS FIRST")
+
+	if accountId != 0 {
+		builder = builder.Where(sq.Or{sq.Eq{"account_id": accountId}, sq.Eq{"account_id": nil}})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return limits, fmt.Errorf("AccountRepo - ListVelocityLimits - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Select(ctx, r.Pool, &limits, sql, args...)
+	if err != nil {
+		return limits, fmt.Errorf("AccountRepo - ListVelocityLimits - pgxscan.Select: %w", err)
+	}
+
+	return
+}
+
+// DisableVelocityLimit - disable active velocity limit, debits are not limited by it anymore.
+func (r *AccountRepo) DisableVelocityLimit(ctx context.Context, id int64) (limit entity.VelocityLimit, err error) {
+	sql, args, err := r.Builder.
+		Update("velocity_limit").
+		Set("active", false).
+		Where(sq.Eq{"id": id, "active": true}).
+		Suffix("RETURNING " + _velocityLimitColumns).
+		ToSql()
+	if err != nil {
+		return limit, fmt.Errorf("AccountRepo - DisableVelocityLimit - r.Builder: %w", err)
+	}
+
+	err = pgxscan.Get(ctx, r.Pool, &limit, sql, args...)
+	if pgxscan.NotFound(err) {
+		return limit, fmt.Errorf("AccountRepo - DisableVelocityLimit - pgxscan.Get: %w", entity.ErrVelocityLimitNotFound)
+	} else if err != nil {
+		return limit, fmt.Errorf("AccountRepo - DisableVelocityLimit - pgxscan.Get: %w", err)
+	}
+
+	return
+}
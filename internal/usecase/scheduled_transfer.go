@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// _maxScheduleAhead - max time between now and run time of scheduled transfer.
+const _maxScheduleAhead = 365 * 24 * time.Hour
+
+func (uc *AccountUseCase) runAtValidation(runAt time.Time) (err error) {
+	now := time.Now()
+
+	if !runAt.After(now) {
+		err = ErrorRunAtInPast
+	} else if runAt.Sub(now) > _maxScheduleAhead {
+		err = ErrorRunAtTooFar
+	}
+
+	return
+}
+
+// failureReason - readable reason of failed attempt of scheduled transfer.
+func failureReason(err error) string {
+	var funds *entity.InsufficientFundsError
+	if errors.As(err, &funds) {
+		return funds.Error()
+	}
+
+	var velocity *entity.VelocityLimitError
+	if errors.As(err, &velocity) {
+		return velocity.Error()
+	}
+
+	for errors.Unwrap(err) != nil {
+		err = errors.Unwrap(err)
+	}
+
+	return err.Error()
+}
+
+// failureCode - kind of reason of failed attempt of transfer.
+func failureCode(err error) entity.FailureCode {
+	var funds *entity.InsufficientFundsError
+	if errors.As(err, &funds) {
+		return entity.FailureInsufficientFunds
+	}
+
+	var velocity *entity.VelocityLimitError
+	if errors.As(err, &velocity) {
+		return entity.FailureVelocityLimit
+	}
+
+	return entity.FailureOther
+}
+
+// transient - check that attempt of transfer failed because of concurrent operation or stop of worker,
+// so it is retried without counting it as failed.
+func transient(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, entity.ErrConcurrentUpdate) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// CreateScheduledTransfer - schedule transfer from redeem account to accrual account at run time,
+// accounts must be in the same currency.
+func (uc *AccountUseCase) CreateScheduledTransfer(ctx context.Context, redeemId, accrId int64, amount decimal.Decimal, runAt time.Time, meta entity.TransactionMeta) (st entity.ScheduledTransfer, err error) {
+	if redeemId == accrId {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - validation: %w", ErrorSameRedeemAccrId)
+	}
+
+	err = uc.runAtValidation(runAt)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.runAtValidation: %w", err)
+	}
+
+	err = uc.metaValidation(meta)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.metaValidation: %w", err)
+	}
+
+	currencies := make([]string, 0, 2)
+
+	for _, id := range []int64{redeemId, accrId} {
+		err = uc.idValidation(id)
+		if err != nil {
+			return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.idValidation: %w", err)
+		}
+
+		acc, err := uc.repo.GetById(ctx, id)
+		if err != nil {
+			return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.repo.GetById: %w", err)
+		}
+
+		currencies = append(currencies, acc.Currency)
+	}
+
+	if currencies[0] != currencies[1] {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - validation: %w", ErrorCurrencyMismatch)
+	}
+
+	err = uc.amountValidation(amount, currencies[0])
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.amountValidation: %w", err)
+	}
+
+	st, err = uc.repo.CreateScheduledTransfer(ctx, entity.ScheduledTransfer{
+		RedeemId:        redeemId,
+		AccrualId:       accrId,
+		Amount:          amount,
+		Currency:        currencies[0],
+		RunDt:           runAt,
+		TransactionMeta: meta,
+	})
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CreateScheduledTransfer - uc.repo.CreateScheduledTransfer: %w", err)
+	}
+
+	return
+}
+
+// GetScheduledTransfer - get scheduled transfer by ID.
+func (uc *AccountUseCase) GetScheduledTransfer(ctx context.Context, id int64) (st entity.ScheduledTransfer, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - GetScheduledTransfer - uc.idValidation: %w", err)
+	}
+
+	st, err = uc.repo.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - GetScheduledTransfer - uc.repo.GetScheduledTransfer: %w", err)
+	}
+
+	return
+}
+
+// ListScheduledTransfers - find scheduled transfers by filter in order of run time.
+func (uc *AccountUseCase) ListScheduledTransfers(ctx context.Context, f entity.ScheduledTransferFilter) (sts []*entity.ScheduledTransfer, err error) {
+	if f.AccountId != 0 {
+		err = uc.idValidation(f.AccountId)
+		if err != nil {
+			return sts, fmt.Errorf("AccountUseCase - ListScheduledTransfers - uc.idValidation: %w", err)
+		}
+	}
+
+	if f.Status != "" && !f.Status.IsKnown() {
+		return sts, fmt.Errorf("AccountUseCase - ListScheduledTransfers - validation: %w", ErrorUnknownScheduledStatus)
+	}
+
+	if f.Limit > _maxPageLimit {
+		return sts, fmt.Errorf("AccountUseCase - ListScheduledTransfers - validation: %w", ErrorLimitTooLarge)
+	} else if f.Limit == 0 {
+		f.Limit = _defaultPageLimit
+	}
+
+	sts, err = uc.repo.ListScheduledTransfers(ctx, f)
+	if err != nil {
+		return sts, fmt.Errorf("AccountUseCase - ListScheduledTransfers - uc.repo.ListScheduledTransfers: %w", err)
+	}
+
+	return
+}
+
+// UpdateScheduledTransfer - change amount or run time of pending transfer, zero values are not changed.
+// Failed attempts of transfer are forgotten.
+func (uc *AccountUseCase) UpdateScheduledTransfer(ctx context.Context, id int64, amount decimal.Decimal, runAt time.Time) (st entity.ScheduledTransfer, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - uc.idValidation: %w", err)
+	}
+
+	if amount.IsZero() && runAt.IsZero() {
+		return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - validation: %w", ErrorNothingToUpdate)
+	}
+
+	if !runAt.IsZero() {
+		err = uc.runAtValidation(runAt)
+		if err != nil {
+			return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - uc.runAtValidation: %w", err)
+		}
+	}
+
+	if !amount.IsZero() {
+		st, err = uc.repo.GetScheduledTransfer(ctx, id)
+		if err != nil {
+			return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - uc.repo.GetScheduledTransfer: %w", err)
+		}
+
+		err = uc.amountValidation(amount, st.Currency)
+		if err != nil {
+			return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - uc.amountValidation: %w", err)
+		}
+	}
+
+	st, err = uc.repo.UpdateScheduledTransfer(ctx, id, amount, runAt)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - UpdateScheduledTransfer - uc.repo.UpdateScheduledTransfer: %w", err)
+	}
+
+	return
+}
+
+// CancelScheduledTransfer - cancel pending transfer.
+func (uc *AccountUseCase) CancelScheduledTransfer(ctx context.Context, id int64) (st entity.ScheduledTransfer, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CancelScheduledTransfer - uc.idValidation: %w", err)
+	}
+
+	st, err = uc.repo.CancelScheduledTransfer(ctx, id)
+	if err != nil {
+		return st, fmt.Errorf("AccountUseCase - CancelScheduledTransfer - uc.repo.CancelScheduledTransfer: %w", err)
+	}
+
+	return
+}
+
+// ExecuteScheduledTransfers - make up to batchSize due transfers, returns number of executed transfers.
+// Failed attempt is retried after retryDelay, transfer fails after maxAttempts failed attempts. Transient failure
+// is not counted and transfer is retried by the next run.
+func (uc *AccountUseCase) ExecuteScheduledTransfers(ctx context.Context, batchSize uint64, maxAttempts int, retryDelay time.Duration) (executed int64, err error) {
+	ids, err := uc.repo.DueScheduledTransfers(ctx, batchSize)
+	if err != nil {
+		return executed, fmt.Errorf("AccountUseCase - ExecuteScheduledTransfers - uc.repo.DueScheduledTransfers: %w", err)
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return executed, nil
+		}
+
+		_, err = uc.repo.ExecuteScheduledTransfer(ctx, id)
+		if errors.Is(err, entity.ErrScheduledNotDue) {
+			continue
+		} else if err == nil {
+			executed++
+
+			continue
+		} else if transient(ctx, err) {
+			continue
+		}
+
+		_, err = uc.repo.FailScheduledTransfer(ctx, id, failureCode(err), failureReason(err), maxAttempts, retryDelay)
+		if err != nil && !errors.Is(err, entity.ErrScheduledNotPending) {
+			return executed, fmt.Errorf("AccountUseCase - ExecuteScheduledTransfers - uc.repo.FailScheduledTransfer: %w", err)
+		}
+	}
+
+	return executed, nil
+}
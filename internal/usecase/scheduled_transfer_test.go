@@ -0,0 +1,301 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateScheduledTransfer(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	runAt := time.Now().Add(time.Hour)
+	payer := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB", CreatedDt: time.Now()}
+	payee := entity.Account{Id: 2, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    int64
+		arg3    decimal.Decimal
+		arg4    time.Time
+		arg5    entity.TransactionMeta
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(payer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(payee, nil)
+				f.accountRepo.EXPECT().CreateScheduledTransfer(f.ctx, entity.ScheduledTransfer{
+					RedeemId:        1,
+					AccrualId:       2,
+					Amount:          decimal.NewFromInt(100),
+					Currency:        "RUB",
+					RunDt:           runAt,
+					TransactionMeta: entity.TransactionMeta{Purpose: "rent"},
+				}).Return(entity.ScheduledTransfer{Id: 1, Status: entity.ScheduledPending}, nil)
+			},
+			arg1:    1,
+			arg2:    2,
+			arg3:    decimal.NewFromInt(100),
+			arg4:    runAt,
+			arg5:    entity.TransactionMeta{Purpose: "rent"},
+			wantErr: false,
+		},
+		{
+			name:    "Case of incorrect work: run time is in the past",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    2,
+			arg3:    decimal.NewFromInt(100),
+			arg4:    time.Now().Add(-time.Minute),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: run time is too far",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    2,
+			arg3:    decimal.NewFromInt(100),
+			arg4:    time.Now().Add(2 * 365 * 24 * time.Hour),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: redeem and accrual ID are the same",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    1,
+			arg3:    decimal.NewFromInt(100),
+			arg4:    runAt,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: accounts have different currencies",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(payer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(3)).Return(entity.Account{Id: 3, Currency: "USD"}, nil)
+			},
+			arg1:    1,
+			arg2:    3,
+			arg3:    decimal.NewFromInt(100),
+			arg4:    runAt,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(payer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(payee, nil)
+			},
+			arg1:    1,
+			arg2:    2,
+			arg3:    decimal.RequireFromString("0.001"),
+			arg4:    runAt,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if st, err := uc.CreateScheduledTransfer(f.ctx, tt.arg1, tt.arg2, tt.arg3, tt.arg4, tt.arg5); (err != nil) != tt.wantErr {
+				t.Errorf("CreateScheduledTransfer() transfer=%v error = %v, wantErr %v", st, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_UpdateScheduledTransfer(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	runAt := time.Now().Add(time.Hour)
+	pending := entity.ScheduledTransfer{Id: 1, RedeemId: 1, AccrualId: 2, Amount: decimal.NewFromInt(100), Currency: "RUB", Status: entity.ScheduledPending}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    decimal.Decimal
+		arg3    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: new amount",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetScheduledTransfer(f.ctx, int64(1)).Return(pending, nil)
+				f.accountRepo.EXPECT().UpdateScheduledTransfer(f.ctx, int64(1), decimal.NewFromInt(150), time.Time{}).Return(pending, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(150),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: new run time",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().UpdateScheduledTransfer(f.ctx, int64(1), decimal.Zero, runAt).Return(pending, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.Zero,
+			arg3:    runAt,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: transfer is already executed",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().UpdateScheduledTransfer(f.ctx, int64(1), decimal.Zero, runAt).Return(entity.ScheduledTransfer{}, entity.ErrScheduledNotPending)
+			},
+			arg1:    1,
+			arg2:    decimal.Zero,
+			arg3:    runAt,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: nothing to update",
+			prepare: func(f *fields) {},
+			arg1:    1,
+			arg2:    decimal.Zero,
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is negative",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetScheduledTransfer(f.ctx, int64(1)).Return(pending, nil)
+			},
+			arg1:    1,
+			arg2:    decimal.NewFromInt(-10),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if st, err := uc.UpdateScheduledTransfer(f.ctx, tt.arg1, tt.arg2, tt.arg3); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateScheduledTransfer() transfer=%v error = %v, wantErr %v", st, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_ExecuteScheduledTransfers(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	funds := &entity.InsufficientFundsError{Available: decimal.NewFromInt(5), Err: entity.ErrNotEnoughMoney}
+	velocity := &entity.VelocityLimitError{Period: entity.LimitDay, Kind: entity.LimitAmount, Max: decimal.NewFromInt(1000), Remaining: decimal.NewFromInt(250)}
+	tests := []struct {
+		name      string
+		prepare   func(f *fields)
+		wantCount int64
+		wantErr   bool
+	}{
+		{
+			name: "Case of correct work: transfer taken by another worker is skipped",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DueScheduledTransfers(f.ctx, uint64(10)).Return([]int64{1, 2}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(1)).Return(entity.ScheduledTransfer{Id: 1, Status: entity.ScheduledExecuted}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(2)).Return(entity.ScheduledTransfer{}, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - pgxscan.Get: %w", entity.ErrScheduledNotDue))
+			},
+			wantCount: 1,
+			wantErr:   false,
+		},
+		{
+			name: "Case of correct work: failed attempt is recorded with reason",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DueScheduledTransfers(f.ctx, uint64(10)).Return([]int64{1}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(1)).Return(entity.ScheduledTransfer{}, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.updBalance: %w", funds))
+				f.accountRepo.EXPECT().FailScheduledTransfer(f.ctx, int64(1), entity.FailureInsufficientFunds, funds.Error(), 3, time.Minute).Return(entity.ScheduledTransfer{Id: 1, Attempts: 1}, nil)
+			},
+			wantCount: 0,
+			wantErr:   false,
+		},
+		{
+			name: "Case of correct work: failed attempt is recorded with exceeded velocity limit",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DueScheduledTransfers(f.ctx, uint64(10)).Return([]int64{1}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(1)).Return(entity.ScheduledTransfer{}, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - r.updBalance: %w", velocity))
+				f.accountRepo.EXPECT().FailScheduledTransfer(f.ctx, int64(1), entity.FailureVelocityLimit, velocity.Error(), 3, time.Minute).Return(entity.ScheduledTransfer{Id: 1, Attempts: 1}, nil)
+			},
+			wantCount: 0,
+			wantErr:   false,
+		},
+		{
+			name: "Case of correct work: attempt lost to concurrent operation is not counted",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DueScheduledTransfers(f.ctx, uint64(10)).Return([]int64{1}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(1)).Return(entity.ScheduledTransfer{}, fmt.Errorf("AccountRepo - ExecuteScheduledTransfer - tx.Commit: %w", entity.ErrConcurrentUpdate))
+			},
+			wantCount: 0,
+			wantErr:   false,
+		},
+		{
+			name: "Case of incorrect work: failed attempt is not recorded",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DueScheduledTransfers(f.ctx, uint64(10)).Return([]int64{1}, nil)
+				f.accountRepo.EXPECT().ExecuteScheduledTransfer(f.ctx, int64(1)).Return(entity.ScheduledTransfer{}, entity.ErrAccountNotFound)
+				f.accountRepo.EXPECT().FailScheduledTransfer(f.ctx, int64(1), entity.FailureOther, entity.ErrAccountNotFound.Error(), 3, time.Minute).Return(entity.ScheduledTransfer{}, errors.New("db is down"))
+			},
+			wantCount: 0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			count, err := uc.ExecuteScheduledTransfers(f.ctx, 10, 3, time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteScheduledTransfers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("ExecuteScheduledTransfers() count = %v, want %v", count, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// ReverseTransaction - reverse accrual or redeem fully or partially with compensating transactions,
+// zero amount means the whole remaining amount. Transfer is reversed on both accounts.
+func (uc *AccountUseCase) ReverseTransaction(ctx context.Context, id int64, amount decimal.Decimal) (trns []*entity.Transaction, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return trns, fmt.Errorf("AccountUseCase - ReverseTransaction - uc.idValidation: %w", err)
+	}
+
+	if !amount.IsZero() {
+		trn, err := uc.repo.GetTransaction(ctx, id)
+		if err != nil {
+			return trns, fmt.Errorf("AccountUseCase - ReverseTransaction - uc.repo.GetTransaction: %w", err)
+		}
+
+		err = uc.amountValidation(amount, trn.Currency)
+		if err != nil {
+			return trns, fmt.Errorf("AccountUseCase - ReverseTransaction - uc.amountValidation: %w", err)
+		}
+	}
+
+	trns, err = uc.repo.ReverseTransaction(ctx, id, amount)
+	if err != nil {
+		return trns, fmt.Errorf("AccountUseCase - ReverseTransaction - uc.repo.ReverseTransaction: %w", err)
+	}
+
+	return
+}
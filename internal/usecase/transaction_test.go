@@ -0,0 +1,112 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_ReverseTransaction(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	redeem := entity.Transaction{Id: 7, TransDt: time.Now(), AccountId: 1, DocNum: 2, Type: "redeem", Amount: decimal.NewFromInt(-40), Currency: "RUB"}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		arg2    decimal.Decimal
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetTransaction(f.ctx, int64(7)).Return(redeem, nil)
+				f.accountRepo.EXPECT().ReverseTransaction(f.ctx, int64(7), decimal.NewFromInt(10)).Return([]*entity.Transaction{{Id: 9, Type: "reversal"}, {Id: 10, Type: "reversal"}}, nil)
+			},
+			arg1:    7,
+			arg2:    decimal.NewFromInt(10),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: whole remaining amount",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().ReverseTransaction(f.ctx, int64(7), decimal.Zero).Return([]*entity.Transaction{{Id: 9, Type: "reversal"}, {Id: 10, Type: "reversal"}}, nil)
+			},
+			arg1:    7,
+			arg2:    decimal.Zero,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: amount is greater than remaining amount",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetTransaction(f.ctx, int64(7)).Return(redeem, nil)
+				f.accountRepo.EXPECT().ReverseTransaction(f.ctx, int64(7), decimal.NewFromInt(50)).Return(nil, entity.ErrReversalExceeded)
+			},
+			arg1:    7,
+			arg2:    decimal.NewFromInt(50),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount has too many decimal places",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetTransaction(f.ctx, int64(7)).Return(redeem, nil)
+			},
+			arg1:    7,
+			arg2:    decimal.RequireFromString("0.001"),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount is negative",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetTransaction(f.ctx, int64(7)).Return(redeem, nil)
+			},
+			arg1:    7,
+			arg2:    decimal.NewFromInt(-10),
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: transaction is not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetTransaction(f.ctx, int64(8)).Return(entity.Transaction{}, entity.ErrTransactionNotFound)
+			},
+			arg1:    8,
+			arg2:    decimal.NewFromInt(10),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is zero",
+			prepare: func(f *fields) {},
+			arg1:    0,
+			arg2:    decimal.NewFromInt(10),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if trns, err := uc.ReverseTransaction(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("ReverseTransaction() transactions=%v error = %v, wantErr %v", trns, err, tt.wantErr)
+			}
+		})
+	}
+}
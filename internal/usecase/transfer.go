@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// _maxBatchLegs - max number of legs in batch transfer.
+const _maxBatchLegs = 100
+
+// BatchTransfer - make all legs of transfer at once or none of them, returns all accounts of legs with their balances.
+// Accounts of each leg must be in the same currency. Retry with the same idempotency key returns the original accounts
+// without second transfer.
+func (uc *AccountUseCase) BatchTransfer(ctx context.Context, legs []entity.TransferLeg, idempotencyKey string) (accs []entity.Account, err error) {
+	if len(legs) == 0 {
+		return accs, fmt.Errorf("AccountUseCase - BatchTransfer - validation: %w", ErrorBatchIsEmpty)
+	} else if len(legs) > _maxBatchLegs {
+		return accs, fmt.Errorf("AccountUseCase - BatchTransfer - validation: %w", ErrorBatchTooLarge)
+	}
+
+	currencies := make(map[int64]string, 2*len(legs))
+
+	for _, leg := range legs {
+		if leg.RedeemId == leg.AccrualId {
+			return accs, fmt.Errorf("AccountUseCase - BatchTransfer - validation: %w", ErrorSameRedeemAccrId)
+		}
+
+		err = uc.metaValidation(leg.TransactionMeta)
+		if err != nil {
+			return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.metaValidation: %w", err)
+		}
+
+		for _, id := range []int64{leg.RedeemId, leg.AccrualId} {
+			if _, ok := currencies[id]; ok {
+				continue
+			}
+
+			err = uc.idValidation(id)
+			if err != nil {
+				return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.idValidation: %w", err)
+			}
+
+			acc, err := uc.repo.GetById(ctx, id)
+			if err != nil {
+				return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.repo.GetById: %w", err)
+			}
+
+			currencies[id] = acc.Currency
+		}
+
+		if currencies[leg.RedeemId] != currencies[leg.AccrualId] {
+			return accs, fmt.Errorf("AccountUseCase - BatchTransfer - validation: %w", ErrorCurrencyMismatch)
+		}
+
+		err = uc.amountValidation(leg.Amount, currencies[leg.RedeemId])
+		if err != nil {
+			return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.amountValidation: %w", err)
+		}
+	}
+
+	key, err := uc.idempotencyKey(idempotencyKey, "batch", "BatchTransfer", legs)
+	if err != nil {
+		return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.idempotencyKey: %w", err)
+	}
+
+	accs, err = uc.repo.BatchTransfer(ctx, legs, key)
+	if err != nil {
+		return accs, fmt.Errorf("AccountUseCase - BatchTransfer - uc.repo.BatchTransfer: %w", idempotencyError(err))
+	}
+
+	return
+}
+
+// _maxSplitPercent - sum of percents of split shares.
+var _maxSplitPercent = decimal.NewFromInt(100)
+
+// splitPercentValidation - percent of share is in (0, 100] and has no more than 4 decimal places.
+func splitPercentValidation(percent decimal.Decimal) error {
+	if !percent.IsPositive() || percent.GreaterThan(_maxSplitPercent) || !percent.Equal(percent.Truncate(_percentPlaces)) {
+		return ErrorSplitPercent
+	}
+
+	return nil
+}
+
+// SplitTransfer - transfer from redeem account to several payee accounts of the same currency at once. Shares are
+// all percents of amount, which add up to 100, or all fixed amounts, then amount is their sum and zero amount means
+// the sum. Percent shares are rounded by largest remainder method, so they add up to amount exactly and equal requests
+// are split equally. Retry with the same idempotency key returns the original result without second transfer.
+func (uc *AccountUseCase) SplitTransfer(ctx context.Context, redeemId int64, amount decimal.Decimal, shares []entity.SplitShare, meta entity.TransactionMeta, idempotencyKey string) (split entity.SplitTransfer, err error) {
+	if len(shares) == 0 {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitIsEmpty)
+	} else if len(shares) > _maxBatchLegs {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitTooLarge)
+	}
+
+	err = uc.idValidation(redeemId)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.idValidation: %w", err)
+	}
+
+	err = uc.metaValidation(meta)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.metaValidation: %w", err)
+	}
+
+	byPercent := false
+	for _, share := range shares {
+		byPercent = byPercent || !share.Percent.IsZero()
+	}
+
+	seen := make(map[int64]bool, len(shares))
+	total := decimal.Zero
+
+	for _, share := range shares {
+		err = uc.idValidation(share.AccountId)
+		if err != nil {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.idValidation: %w", err)
+		}
+
+		if share.AccountId == redeemId {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSameRedeemAccrId)
+		} else if seen[share.AccountId] {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitDuplicateAccount)
+		}
+
+		seen[share.AccountId] = true
+
+		if byPercent && !share.Amount.IsZero() {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitMixedShares)
+		}
+
+		if byPercent {
+			err = splitPercentValidation(share.Percent)
+			if err != nil {
+				return split, fmt.Errorf("AccountUseCase - SplitTransfer - splitPercentValidation: %w", err)
+			}
+
+			total = total.Add(share.Percent)
+		} else {
+			total = total.Add(share.Amount)
+		}
+	}
+
+	if byPercent && !total.Equal(_maxSplitPercent) {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitPercentSum)
+	}
+
+	payer, err := uc.repo.GetById(ctx, redeemId)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.repo.GetById: %w", err)
+	}
+
+	for _, share := range shares {
+		acc, err := uc.repo.GetById(ctx, share.AccountId)
+		if err != nil {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.repo.GetById: %w", err)
+		}
+
+		if acc.Currency != payer.Currency {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorCurrencyMismatch)
+		}
+
+		if !byPercent {
+			err = uc.amountValidation(share.Amount, payer.Currency)
+			if err != nil {
+				return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.amountValidation: %w", err)
+			}
+		}
+	}
+
+	if !byPercent {
+		if !amount.IsZero() && !amount.Equal(total) {
+			return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitAmountMismatch)
+		}
+
+		amount = total
+	}
+
+	err = uc.amountValidation(amount, payer.Currency)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.amountValidation: %w", err)
+	}
+
+	key, err := uc.idempotencyKey(idempotencyKey, fmt.Sprintf("split:id:%d", redeemId), "SplitTransfer", redeemId, amount, shares, meta)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.idempotencyKey: %w", err)
+	}
+
+	if byPercent {
+		percents := make([]decimal.Decimal, 0, len(shares))
+		for _, share := range shares {
+			percents = append(percents, share.Percent)
+		}
+
+		cur, _ := entity.CurrencyByCode(payer.Currency)
+		parts := cur.Split(amount, percents)
+
+		shares = append([]entity.SplitShare(nil), shares...)
+		for i := range shares {
+			if !parts[i].IsPositive() {
+				return split, fmt.Errorf("AccountUseCase - SplitTransfer - validation: %w", ErrorSplitShareIsZero)
+			}
+
+			shares[i].Amount = parts[i]
+		}
+	}
+
+	split, err = uc.repo.SplitTransfer(ctx, redeemId, shares, meta, key)
+	if err != nil {
+		return split, fmt.Errorf("AccountUseCase - SplitTransfer - uc.repo.SplitTransfer: %w", idempotencyError(err))
+	}
+
+	return
+}
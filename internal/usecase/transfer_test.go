@@ -0,0 +1,300 @@
+package usecase_test
+
+import (
+	"context"
+	"time"
+
+	"testing"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_BatchTransfer(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	buyer := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB", CreatedDt: time.Now()}
+	seller := entity.Account{Id: 2, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}
+	platform := entity.Account{Id: 3, Balance: decimal.Zero, Currency: "RUB", CreatedDt: time.Now()}
+	legs := []entity.TransferLeg{
+		{RedeemId: 1, AccrualId: 2, Amount: decimal.NewFromInt(900), TransactionMeta: entity.TransactionMeta{Purpose: "order_payment"}},
+		{RedeemId: 1, AccrualId: 3, Amount: decimal.NewFromInt(100), TransactionMeta: entity.TransactionMeta{Purpose: "fee"}},
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    []entity.TransferLeg
+		arg2    string
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(3)).Return(platform, nil)
+				f.accountRepo.EXPECT().BatchTransfer(f.ctx, legs, (*entity.IdempotencyKey)(nil)).Return([]entity.Account{
+					{Id: 1, Balance: decimal.Zero, Currency: "RUB"},
+					{Id: 2, Balance: decimal.NewFromInt(900), Currency: "RUB"},
+					{Id: 3, Balance: decimal.NewFromInt(100), Currency: "RUB"},
+				}, nil)
+			},
+			arg1:    legs,
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: request with idempotency key",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(3)).Return(platform, nil)
+				f.accountRepo.EXPECT().BatchTransfer(f.ctx, legs, gomock.Not(gomock.Nil())).Return([]entity.Account{buyer, seller, platform}, nil)
+			},
+			arg1:    legs,
+			arg2:    "order-15-payment",
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: not enough money for the last leg",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(3)).Return(platform, nil)
+				f.accountRepo.EXPECT().BatchTransfer(f.ctx, legs, (*entity.IdempotencyKey)(nil)).Return(nil, entity.ErrNotEnoughMoney)
+			},
+			arg1:    legs,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: batch has no legs",
+			prepare: func(f *fields) {},
+			arg1:    []entity.TransferLeg{},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: batch has too many legs",
+			prepare: func(f *fields) {},
+			arg1:    make([]entity.TransferLeg, 101),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: redeem and accrual ID are the same",
+			prepare: func(f *fields) {},
+			arg1:    []entity.TransferLeg{{RedeemId: 1, AccrualId: 1, Amount: decimal.NewFromInt(10)}},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: accounts of leg have different currencies",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(4)).Return(entity.Account{Id: 4, Currency: "USD"}, nil)
+			},
+			arg1:    []entity.TransferLeg{{RedeemId: 1, AccrualId: 4, Amount: decimal.NewFromInt(10)}},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount of leg is zero",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(buyer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(seller, nil)
+			},
+			arg1:    []entity.TransferLeg{{RedeemId: 1, AccrualId: 2, Amount: decimal.Zero}},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is negative",
+			prepare: func(f *fields) {},
+			arg1:    []entity.TransferLeg{{RedeemId: -101, AccrualId: 2, Amount: decimal.NewFromInt(10)}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if accs, err := uc.BatchTransfer(f.ctx, tt.arg1, tt.arg2); (err != nil) != tt.wantErr {
+				t.Errorf("BatchTransfer() accounts=%v error = %v, wantErr %v", accs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_SplitTransfer(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	payer := entity.Account{Id: 1, Balance: decimal.NewFromInt(1000), Currency: "RUB", CreatedDt: time.Now()}
+	payees := func(f *fields, ids ...int64) {
+		f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(payer, nil)
+		for _, id := range ids {
+			f.accountRepo.EXPECT().GetById(f.ctx, id).Return(entity.Account{Id: id, Currency: "RUB", CreatedDt: time.Now()}, nil)
+		}
+	}
+	expectShares := func(f *fields, amounts ...string) {
+		f.accountRepo.EXPECT().SplitTransfer(f.ctx, int64(1), gomock.Any(), entity.TransactionMeta{}, nil).
+			DoAndReturn(func(_ context.Context, _ int64, shares []entity.SplitShare, _ entity.TransactionMeta, _ *entity.IdempotencyKey) (entity.SplitTransfer, error) {
+				for i, amount := range amounts {
+					if !shares[i].Amount.Equal(decimal.RequireFromString(amount)) {
+						t.Errorf("SplitTransfer() share %d = %s, want %s", i, shares[i].Amount, amount)
+					}
+				}
+				return entity.SplitTransfer{Shares: shares}, nil
+			})
+	}
+	percents := func(values ...string) []entity.SplitShare {
+		shares := make([]entity.SplitShare, 0, len(values))
+		for i, value := range values {
+			shares = append(shares, entity.SplitShare{AccountId: int64(i + 2), Percent: decimal.RequireFromString(value)})
+		}
+		return shares
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    decimal.Decimal
+		arg2    []entity.SplitShare
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: unit left goes to the largest remainder",
+			prepare: func(f *fields) {
+				payees(f, 2, 3, 4)
+				expectShares(f, "33.33", "33.33", "33.34")
+			},
+			arg1:    decimal.NewFromInt(100),
+			arg2:    percents("33.3333", "33.3333", "33.3334"),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: the first share wins tie",
+			prepare: func(f *fields) {
+				payees(f, 2, 3)
+				expectShares(f, "5.01", "5")
+			},
+			arg1:    decimal.RequireFromString("10.01"),
+			arg2:    percents("50", "50"),
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: fixed amounts",
+			prepare: func(f *fields) {
+				payees(f, 2, 3)
+				expectShares(f, "700", "300")
+			},
+			arg1: decimal.Zero,
+			arg2: []entity.SplitShare{
+				{AccountId: 2, Amount: decimal.NewFromInt(700)},
+				{AccountId: 3, Amount: decimal.NewFromInt(300)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: amount is too small to split",
+			prepare: func(f *fields) {
+				payees(f, 2, 3, 4)
+			},
+			arg1:    decimal.RequireFromString("0.02"),
+			arg2:    percents("50", "25", "25"),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: percents do not add up to 100",
+			prepare: func(f *fields) {},
+			arg1:    decimal.NewFromInt(100),
+			arg2:    percents("50", "40"),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: shares are mixed",
+			prepare: func(f *fields) {},
+			arg1:    decimal.NewFromInt(100),
+			arg2: []entity.SplitShare{
+				{AccountId: 2, Percent: decimal.NewFromInt(50)},
+				{AccountId: 3, Amount: decimal.NewFromInt(50)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: amount does not match the sum of shares",
+			prepare: func(f *fields) {
+				payees(f, 2, 3)
+			},
+			arg1: decimal.NewFromInt(999),
+			arg2: []entity.SplitShare{
+				{AccountId: 2, Amount: decimal.NewFromInt(700)},
+				{AccountId: 3, Amount: decimal.NewFromInt(300)},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: account has several shares",
+			prepare: func(f *fields) {},
+			arg1:    decimal.NewFromInt(100),
+			arg2: []entity.SplitShare{
+				{AccountId: 2, Percent: decimal.NewFromInt(50)},
+				{AccountId: 2, Percent: decimal.NewFromInt(50)},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: payer is payee",
+			prepare: func(f *fields) {},
+			arg1:    decimal.NewFromInt(100),
+			arg2:    []entity.SplitShare{{AccountId: 1, Percent: decimal.NewFromInt(100)}},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: accounts have different currencies",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(1)).Return(payer, nil)
+				f.accountRepo.EXPECT().GetById(f.ctx, int64(2)).Return(entity.Account{Id: 2, Currency: "USD"}, nil)
+			},
+			arg1:    decimal.NewFromInt(100),
+			arg2:    percents("100"),
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: split has no shares",
+			prepare: func(f *fields) {},
+			arg1:    decimal.NewFromInt(100),
+			arg2:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if split, err := uc.SplitTransfer(f.ctx, 1, tt.arg1, tt.arg2, entity.TransactionMeta{}, ""); (err != nil) != tt.wantErr {
+				t.Errorf("SplitTransfer() split=%v error = %v, wantErr %v", split, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// velocityLimitValidation - check velocity limit and normalize currency code to upper case.
+// Limit has max amount, max number of debits or both, zero max blocks debits in period.
+func velocityLimitValidation(limit entity.VelocityLimit) (entity.VelocityLimit, error) {
+	cur, ok := entity.CurrencyByCode(limit.Currency)
+	if !ok {
+		return limit, ErrorUnknownCurrency
+	}
+
+	limit.Currency = cur.Code
+
+	if !limit.Period.IsKnown() {
+		return limit, ErrorUnknownLimitPeriod
+	}
+
+	if !limit.MaxAmount.Valid && limit.MaxCount == nil {
+		return limit, ErrorVelocityLimitIsEmpty
+	}
+
+	if limit.MaxAmount.Valid {
+		if err := fixedAmountValidation(cur, limit.MaxAmount.Decimal); err != nil {
+			return limit, err
+		}
+	}
+
+	if limit.MaxCount != nil && *limit.MaxCount < 0 {
+		return limit, ErrorLimitCountIsNegative
+	}
+
+	return limit, nil
+}
+
+// CreateVelocityLimit - create limit of debits of account in currency per calendar period, nil account means
+// default limit of all accounts. The limit replaces active limit of the same account, currency and period.
+func (uc *AccountUseCase) CreateVelocityLimit(ctx context.Context, limit entity.VelocityLimit) (created entity.VelocityLimit, err error) {
+	limit, err = velocityLimitValidation(limit)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateVelocityLimit - velocityLimitValidation: %w", err)
+	}
+
+	if limit.AccountId != nil {
+		err = uc.idValidation(*limit.AccountId)
+		if err != nil {
+			return created, fmt.Errorf("AccountUseCase - CreateVelocityLimit - uc.idValidation: %w", err)
+		}
+
+		acc, err := uc.repo.GetById(ctx, *limit.AccountId)
+		if err != nil {
+			return created, fmt.Errorf("AccountUseCase - CreateVelocityLimit - uc.repo.GetById: %w", err)
+		}
+
+		if acc.Currency != limit.Currency {
+			return created, fmt.Errorf("AccountUseCase - CreateVelocityLimit - validation: %w", ErrorOperationCurrency)
+		}
+	}
+
+	created, err = uc.repo.CreateVelocityLimit(ctx, limit)
+	if err != nil {
+		return created, fmt.Errorf("AccountUseCase - CreateVelocityLimit - uc.repo.CreateVelocityLimit: %w", err)
+	}
+
+	return
+}
+
+// ListVelocityLimits - get active limits of account and default ones, zero ID means limits of all accounts.
+func (uc *AccountUseCase) ListVelocityLimits(ctx context.Context, accountId int64) (limits []*entity.VelocityLimit, err error) {
+	if accountId != 0 {
+		err = uc.idValidation(accountId)
+		if err != nil {
+			return limits, fmt.Errorf("AccountUseCase - ListVelocityLimits - uc.idValidation: %w", err)
+		}
+	}
+
+	limits, err = uc.repo.ListVelocityLimits(ctx, accountId)
+	if err != nil {
+		return limits, fmt.Errorf("AccountUseCase - ListVelocityLimits - uc.repo.ListVelocityLimits: %w", err)
+	}
+
+	return
+}
+
+// DisableVelocityLimit - disable active velocity limit, debits are not limited by it anymore.
+func (uc *AccountUseCase) DisableVelocityLimit(ctx context.Context, id int64) (limit entity.VelocityLimit, err error) {
+	err = uc.idValidation(id)
+	if err != nil {
+		return limit, fmt.Errorf("AccountUseCase - DisableVelocityLimit - uc.idValidation: %w", err)
+	}
+
+	limit, err = uc.repo.DisableVelocityLimit(ctx, id)
+	if err != nil {
+		return limit, fmt.Errorf("AccountUseCase - DisableVelocityLimit - uc.repo.DisableVelocityLimit: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,208 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+	"github.com/cut4cut/avito-test-work/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountUseCase_CreateVelocityLimit(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	accountId, zeroId := int64(1), int64(0)
+	count, negative := int64(20), int64(-1)
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    entity.VelocityLimit
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work: default limit",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().CreateVelocityLimit(f.ctx, entity.VelocityLimit{
+					Currency:  "RUB",
+					Period:    entity.LimitDay,
+					MaxAmount: decimal.NewNullDecimal(decimal.NewFromInt(50000)),
+					MaxCount:  &count,
+				}).Return(entity.VelocityLimit{Id: 1, Active: true, CreatedDt: time.Now()}, nil)
+			},
+			arg1: entity.VelocityLimit{
+				Currency:  "rub",
+				Period:    entity.LimitDay,
+				MaxAmount: decimal.NewNullDecimal(decimal.NewFromInt(50000)),
+				MaxCount:  &count,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case of correct work: limit of account blocks debits",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, accountId).Return(entity.Account{Id: 1, Currency: "RUB"}, nil)
+				f.accountRepo.EXPECT().CreateVelocityLimit(f.ctx, entity.VelocityLimit{
+					AccountId: &accountId,
+					Currency:  "RUB",
+					Period:    entity.LimitMonth,
+					MaxAmount: decimal.NewNullDecimal(decimal.Zero),
+				}).Return(entity.VelocityLimit{Id: 2, Active: true, CreatedDt: time.Now()}, nil)
+			},
+			arg1: entity.VelocityLimit{
+				AccountId: &accountId,
+				Currency:  "RUB",
+				Period:    entity.LimitMonth,
+				MaxAmount: decimal.NewNullDecimal(decimal.Zero),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: currency of account does not match",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, accountId).Return(entity.Account{Id: 1, Currency: "USD"}, nil)
+			},
+			arg1: entity.VelocityLimit{
+				AccountId: &accountId,
+				Currency:  "RUB",
+				Period:    entity.LimitWeek,
+				MaxCount:  &count,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Case of incorrect work: account not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().GetById(f.ctx, accountId).Return(entity.Account{}, entity.ErrAccountNotFound)
+			},
+			arg1: entity.VelocityLimit{
+				AccountId: &accountId,
+				Currency:  "RUB",
+				Period:    entity.LimitWeek,
+				MaxCount:  &count,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: account ID is zero",
+			prepare: func(f *fields) {},
+			arg1: entity.VelocityLimit{
+				AccountId: &zeroId,
+				Currency:  "RUB",
+				Period:    entity.LimitWeek,
+				MaxCount:  &count,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: neither max amount nor max count",
+			prepare: func(f *fields) {},
+			arg1:    entity.VelocityLimit{Currency: "RUB", Period: entity.LimitDay},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown period",
+			prepare: func(f *fields) {},
+			arg1:    entity.VelocityLimit{Currency: "RUB", Period: "year", MaxCount: &count},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: unknown currency",
+			prepare: func(f *fields) {},
+			arg1:    entity.VelocityLimit{Currency: "XXX", Period: entity.LimitDay, MaxCount: &count},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: max count is negative",
+			prepare: func(f *fields) {},
+			arg1:    entity.VelocityLimit{Currency: "RUB", Period: entity.LimitDay, MaxCount: &negative},
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: max amount does not fit currency",
+			prepare: func(f *fields) {},
+			arg1:    entity.VelocityLimit{Currency: "RUB", Period: entity.LimitDay, MaxAmount: decimal.NewNullDecimal(decimal.RequireFromString("10.001"))},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if limit, err := uc.CreateVelocityLimit(f.ctx, tt.arg1); (err != nil) != tt.wantErr {
+				t.Errorf("CreateVelocityLimit() limit=%v error = %v, wantErr %v", limit, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccountUseCase_DisableVelocityLimit(t *testing.T) {
+	type fields struct {
+		ctx         context.Context
+		accountRepo *MockAccountRepo
+		rates       *MockRateProvider
+	}
+	tests := []struct {
+		name    string
+		prepare func(f *fields)
+		arg1    int64
+		wantErr bool
+	}{
+		{
+			name: "Case of correct work",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DisableVelocityLimit(f.ctx, int64(1)).Return(entity.VelocityLimit{Id: 1}, nil)
+			},
+			arg1:    1,
+			wantErr: false,
+		},
+		{
+			name: "Case of incorrect work: limit not found",
+			prepare: func(f *fields) {
+				f.accountRepo.EXPECT().DisableVelocityLimit(f.ctx, int64(2)).Return(entity.VelocityLimit{}, entity.ErrVelocityLimitNotFound)
+			},
+			arg1:    2,
+			wantErr: true,
+		},
+		{
+			name:    "Case of incorrect work: ID is negative",
+			prepare: func(f *fields) {},
+			arg1:    -1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			f := fields{
+				ctx:         context.Background(),
+				accountRepo: NewMockAccountRepo(ctrl),
+				rates:       NewMockRateProvider(ctrl),
+			}
+			if tt.prepare != nil {
+				tt.prepare(&f)
+			}
+
+			uc := usecase.New(f.accountRepo, f.rates)
+			if limit, err := uc.DisableVelocityLimit(f.ctx, tt.arg1); (err != nil) != tt.wantErr {
+				t.Errorf("DisableVelocityLimit() limit=%v error = %v, wantErr %v", limit, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package webapi implements clients of external HTTP services.
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cut4cut/avito-test-work/internal/entity"
+)
+
+// RateWebAPI - exchange rates received from HTTP service.
+// The service responds on GET {url}?base=RUB&quote=USD with JSON of entity.Rate.
+type RateWebAPI struct {
+	client *http.Client
+	url    string
+}
+
+// NewRateWebAPI - create new HTTP rate provider.
+func NewRateWebAPI(url string, timeout time.Duration) *RateWebAPI {
+	return &RateWebAPI{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+	}
+}
+
+// Rate - request rate of currency pair.
+func (w *RateWebAPI) Rate(ctx context.Context, base, quote string) (rate entity.Rate, err error) {
+	u, err := url.Parse(w.url)
+	if err != nil {
+		return rate, fmt.Errorf("RateWebAPI - Rate - url.Parse: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("base", base)
+	q.Set("quote", quote)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return rate, fmt.Errorf("RateWebAPI - Rate - http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return rate, fmt.Errorf("RateWebAPI - Rate - w.client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return rate, fmt.Errorf("RateWebAPI - Rate - %s/%s: unexpected status %d", base, quote, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&rate)
+	if err != nil {
+		return rate, fmt.Errorf("RateWebAPI - Rate - json.Decode: %w", err)
+	}
+
+	return
+}